@@ -0,0 +1,161 @@
+// Package certs serves the gateway's TLS certificate and client CA pool,
+// reloading them when the files change on disk so certificates can be renewed
+// without a restart.
+package certs
+
+import (
+	"api-gateway/config"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Reloader holds the current certificate and client CA pool. Handshakes read
+// them through TLSConfig, so a reload applies to the next connection.
+type Reloader struct {
+	cfg    config.TLSConfig
+	logger *logrus.Logger
+
+	cert     atomic.Pointer[tls.Certificate]
+	clientCA atomic.Pointer[x509.CertPool]
+
+	mu     sync.Mutex // serialises Reload
+	stamp  string     // modification times and sizes of the loaded files
+	failed string     // stamp of the files that last failed to load
+}
+
+// NewReloader loads the files named in cfg. It fails when they cannot be read
+// or do not hold a usable key pair, so a bad setup is caught at startup.
+func NewReloader(cfg config.TLSConfig, logger *logrus.Logger) (*Reloader, error) {
+	r := &Reloader{cfg: cfg, logger: logger}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig returns a server configuration that picks up the current
+// certificate and client CA pool on every handshake. nextProtos sets the ALPN
+// protocols, e.g. "h2" and "http/1.1".
+func (r *Reloader) TLSConfig(nextProtos ...string) *tls.Config {
+	clientAuth := tls.NoClientCert
+	if r.cfg.ClientCAFile != "" {
+		clientAuth = tls.VerifyClientCertIfGiven
+		if r.cfg.ClientAuth == "require" {
+			clientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*r.cert.Load()},
+				ClientAuth:   clientAuth,
+				ClientCAs:    r.clientCA.Load(),
+			}, nil
+		},
+	}
+}
+
+// Reload reads the certificate, key and client CA files again if any of them
+// changed. On error the previous certificate and pool stay in use, and the
+// same files are not retried until they change again.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamp, err := r.fileStamp()
+	if err != nil {
+		return err
+	}
+	if stamp == r.stamp || stamp == r.failed {
+		return nil
+	}
+	if err := r.load(stamp); err != nil {
+		r.failed = stamp
+		return err
+	}
+	return nil
+}
+
+// load reads the files and makes them current.
+func (r *Reloader) load(stamp string) error {
+	cert, err := tls.LoadX509KeyPair(r.cfg.CertFile, r.cfg.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load TLS certificate: %w", err)
+	}
+	if cert.Leaf == nil {
+		return errors.New("load TLS certificate: no certificate in TLS_CERT_FILE")
+	}
+
+	var pool *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		data, err := os.ReadFile(r.cfg.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("read TLS_CLIENT_CA_FILE: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return errors.New("TLS_CLIENT_CA_FILE holds no PEM certificates")
+		}
+	}
+
+	r.cert.Store(&cert)
+	r.clientCA.Store(pool)
+	r.stamp = stamp
+	r.logger.WithFields(logrus.Fields{
+		"subject":   cert.Leaf.Subject.String(),
+		"not_after": cert.Leaf.NotAfter.Format(time.RFC3339),
+	}).Info("Loaded TLS certificate")
+	if time.Until(cert.Leaf.NotAfter) < 0 {
+		r.logger.Warn("TLS certificate has expired")
+	}
+	return nil
+}
+
+// Run checks the files for changes every TLS_RELOAD_INTERVAL until ctx is
+// cancelled.
+func (r *Reloader) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.ReloadInterval)
+	defer ticker.Stop()
+
+	r.logger.Infof("TLS certificate watcher started (every %s)", r.cfg.ReloadInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("TLS certificate watcher stopped")
+			return
+		case <-ticker.C:
+			if err := r.Reload(); err != nil {
+				r.logger.WithError(err).Error("TLS certificate reload failed, keeping the current certificate")
+			}
+		}
+	}
+}
+
+// fileStamp summarises the modification time and size of every watched file.
+func (r *Reloader) fileStamp() (string, error) {
+	var stamp string
+	for _, path := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", fmt.Errorf("stat TLS file: %w", err)
+		}
+		stamp += fmt.Sprintf("%s:%d:%d;", path, info.ModTime().UnixNano(), info.Size())
+	}
+	return stamp, nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Secret is a configuration value that must not appear in logs or config
+// dumps. It prints, and marshals to JSON or text, as "[redacted]" when set;
+// Value returns the real value.
+type Secret string
+
+// redacted is how a set Secret is printed
+const redacted = "[redacted]"
+
+// Value returns the secret itself.
+func (s Secret) Value() string { return string(s) }
+
+// String masks the secret for %v and %s.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// GoString masks the secret for %#v.
+func (s Secret) GoString() string { return fmt.Sprintf("%q", s.String()) }
+
+// MarshalText masks the secret in JSON, YAML and log fields.
+func (s Secret) MarshalText() ([]byte, error) { return []byte(s.String()), nil }
+
+// getSecret retrieves a secret from key or, when key is unset, from the file
+// named by key_FILE (e.g. a Docker secret or systemd credential). Trailing
+// line breaks are trimmed from the file. Setting both is an error.
+func (s *source) getSecret(key, defaultValue string) Secret {
+	value, _ := s.lookup(key)
+	path, _ := s.lookup(key + "_FILE")
+	switch {
+	case path == "" && value == "":
+		return Secret(defaultValue)
+	case path == "":
+		return Secret(value)
+	case value != "":
+		s.errors = append(s.errors, fmt.Errorf("set only one of %s and %s_FILE", key, key))
+		return Secret(value)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		s.errors = append(s.errors, fmt.Errorf("read %s_FILE: %w", key, err))
+		return Secret(defaultValue)
+	}
+	return Secret(strings.TrimRight(string(data), "\r\n"))
+}
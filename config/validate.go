@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultJWTSecret is the JWT_SECRET used when none is set
+const defaultJWTSecret = "default-secret-change-in-production"
+
+// placeholderSecrets are the JWT_SECRET values shipped in the defaults and
+// examples; release mode refuses them
+var placeholderSecrets = []string{defaultJWTSecret, "change-this-to-a-long-random-secret"}
+
+// minSecretLength is the shortest JWT_SECRET accepted in release mode
+const minSecretLength = 32
+
+// Validate reports every setting that is out of range. In release mode it also
+// refuses the insecure defaults: a placeholder or short JWT_SECRET and an
+// empty API_KEY.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(slices.Contains([]string{"debug", "release", "test"}, c.Server.GinMode),
+		"invalid GIN_MODE %q (want debug, release or test)", c.Server.GinMode)
+	for _, db := range []struct {
+		prefix string
+		cfg    DatabaseConfig
+	}{{"TICKET", c.TicketDB}, {"MACHINE", c.MachineDB}, {"TOKEN", c.TokenDB}} {
+		check(slices.Contains(encryptModes, db.cfg.Encrypt),
+			"invalid %s_DB_ENCRYPT %q (want disable, false, true or strict)", db.prefix, db.cfg.Encrypt)
+	}
+
+	_, err := logrus.ParseLevel(c.Runtime.LogLevel)
+	check(err == nil, "invalid LOG_LEVEL %q", c.Runtime.LogLevel)
+	check(len(c.Runtime.CORSOrigins) > 0, "CORS_ALLOWED_ORIGINS must list at least one origin")
+	for _, origin := range c.Runtime.CORSOrigins {
+		check(validOrigin(origin), "invalid origin %q in CORS_ALLOWED_ORIGINS (want * or scheme://host[:port])", origin)
+	}
+	limits := c.Runtime.RateLimits
+	check(limits.PerMinute > 0 && limits.PerHour > 0 && limits.PerDay > 0,
+		"RATE_LIMIT_DEFAULT_PER_MINUTE, _PER_HOUR and _PER_DAY must be positive")
+	check(c.Runtime.MetadataCacheTTL >= 0, "METADATA_CACHE_TTL must not be negative")
+
+	check((c.TLS.CertFile == "") == (c.TLS.KeyFile == ""), "TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	check(c.TLS.ClientCAFile == "" || c.TLS.Enabled(), "TLS_CLIENT_CA_FILE needs TLS_CERT_FILE and TLS_KEY_FILE")
+	check(slices.Contains([]string{"optional", "require"}, c.TLS.ClientAuth),
+		"invalid TLS_CLIENT_AUTH %q (want optional or require)", c.TLS.ClientAuth)
+	check(c.TLS.ReloadInterval > 0, "TLS_RELOAD_INTERVAL must be positive")
+	check(c.UsageLog.QueueSize > 0 && c.UsageLog.BatchSize > 0, "USAGE_LOG_QUEUE_SIZE and USAGE_LOG_BATCH_SIZE must be positive")
+	check(c.UsageLog.FlushInterval > 0, "USAGE_LOG_FLUSH_INTERVAL must be positive")
+	check(slices.Contains([]string{"memory", "database"}, c.RateLimit.Backend),
+		"invalid RATE_LIMIT_BACKEND %q (want memory or database)", c.RateLimit.Backend)
+	// An update's queries must be able to finish before a retry may take over its key
+	check(c.Idempotency.StaleAfter > c.Query.WriteTimeout+c.Query.ListTimeout,
+		"IDEMPOTENCY_STALE_AFTER must be longer than DB_WRITE_TIMEOUT plus DB_LIST_TIMEOUT (%s)", c.Query.WriteTimeout+c.Query.ListTimeout)
+	check(c.UsageLog.SampleRate >= 0 && c.UsageLog.SampleRate <= 1, "USAGE_LOG_SAMPLE_RATE must be between 0 and 1")
+
+	if c.Server.GinMode == "release" {
+		check(!slices.Contains(placeholderSecrets, c.Security.JWTSecret.Value()), "JWT_SECRET must be changed from the placeholder in release mode")
+		check(len(c.Security.JWTSecret) >= minSecretLength,
+			"JWT_SECRET must be at least %d characters in release mode", minSecretLength)
+		check(c.Security.APIKey != "", "API_KEY must be set in release mode")
+	}
+	return errors.Join(errs...)
+}
+
+// validOrigin reports whether origin is "*" or a bare scheme://host[:port].
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		u.User == nil && (u.Path == "" || u.Path == "/") && u.RawQuery == "" && u.Fragment == ""
+}
+
+// RestartRequired lists the sections of reloaded that differ from running
+// other than Runtime and the rotatable secrets, i.e. the changes a SIGHUP
+// cannot apply. Setting or clearing a rotatable secret still needs a restart.
+func RestartRequired(running, reloaded *Config) []string {
+	var changed []string
+	a, b := reflect.ValueOf(maskRotatable(*running)), reflect.ValueOf(maskRotatable(*reloaded))
+	for i := 0; i < a.NumField(); i++ {
+		name := a.Type().Field(i).Name
+		if name == "Runtime" {
+			continue
+		}
+		if !reflect.DeepEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// maskRotatable replaces the secrets that are applied on reload (database
+// passwords, CLOUD_APP_API_KEY and METRICS_TOKEN) with a marker of whether
+// they are set, so only changes in presence are compared.
+func maskRotatable(c Config) Config {
+	mask := func(s *Secret) {
+		if *s != "" {
+			*s = "set"
+		}
+	}
+	mask(&c.TicketDB.Password)
+	mask(&c.MachineDB.Password)
+	mask(&c.TokenDB.Password)
+	mask(&c.CloudApp.APIKey)
+	mask(&c.Metrics.Token)
+	return c
+}
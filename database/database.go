@@ -1,14 +1,22 @@
 package database
 
 import (
+	"api-gateway/config"
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"sync/atomic"
 	"time"
 
-	_ "github.com/microsoft/go-mssqldb" // SQL Server driver
+	mssql "github.com/microsoft/go-mssqldb" // SQL Server driver
 	"github.com/sirupsen/logrus"
 )
 
+// startupPingTimeout bounds the connectivity check at startup so an
+// unresponsive server cannot stall boot
+const startupPingTimeout = 5 * time.Second
+
 // DBManager manages multiple database connections
 // Holds separate connections for ticket, machine, and token databases
 type DBManager struct {
@@ -16,22 +24,61 @@ type DBManager struct {
 	MachineDB *sql.DB // Connection to machine_master database
 	TokenDB   *sql.DB // Connection to token_management database
 	logger    *logrus.Logger
+
+	// Connection settings per pool, swapped by UpdatePasswords
+	ticketConn, machineConn, tokenConn *connector
+}
+
+// connector opens SQL Server connections with the settings it currently holds,
+// so a pool picks up a rotated password for the connections it opens next.
+type connector struct {
+	cfg  config.DatabaseConfig
+	conn atomic.Pointer[mssql.Connector]
+}
+
+func newConnector(cfg config.DatabaseConfig) (*connector, error) {
+	c := &connector{}
+	if err := c.set(cfg); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// set parses cfg and uses it for every connection opened from now on.
+func (c *connector) set(cfg config.DatabaseConfig) error {
+	conn, err := mssql.NewConnector(cfg.GetDSN())
+	if err != nil {
+		return err
+	}
+	c.cfg = cfg
+	c.conn.Store(conn)
+	return nil
+}
+
+// Connect implements driver.Connector.
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.conn.Load().Connect(ctx)
+}
+
+// Driver implements driver.Connector.
+func (c *connector) Driver() driver.Driver {
+	return c.conn.Load().Driver()
 }
 
 // NewDBManager creates a new database manager with connections to all databases
 // Connections are non-fatal: if a database is unavailable at startup, the app
 // keeps running and the connection will succeed automatically once the database
-// becomes available. Health endpoint reports real-time status.
-func NewDBManager(ticketDSN, machineDSN, tokenDSN string, logger *logrus.Logger) *DBManager {
+// becomes available. The health prober reports their status.
+func NewDBManager(ticketCfg, machineCfg, tokenCfg config.DatabaseConfig, logger *logrus.Logger) *DBManager {
 	manager := &DBManager{
 		logger: logger,
 	}
 
-	manager.TicketDB = openDB(ticketDSN, "ticket_master", logger)
-	manager.MachineDB = openDB(machineDSN, "machine_master", logger)
+	manager.TicketDB, manager.ticketConn = openDB(ticketCfg, "ticket_master", logger)
+	manager.MachineDB, manager.machineConn = openDB(machineCfg, "machine_master", logger)
 
-	if tokenDSN != "" {
-		manager.TokenDB = openDB(tokenDSN, "token_management", logger)
+	if tokenCfg.Host != "" {
+		manager.TokenDB, manager.tokenConn = openDB(tokenCfg, "token_management", logger)
 	} else {
 		logger.Warn("Token database DSN not configured, token management will be unavailable")
 	}
@@ -42,24 +89,62 @@ func NewDBManager(ticketDSN, machineDSN, tokenDSN string, logger *logrus.Logger)
 // openDB opens a database connection, configures the pool, and pings.
 // Always returns the *sql.DB even if ping fails — Go's database/sql
 // will automatically reconnect when the database becomes available.
-func openDB(dsn, name string, logger *logrus.Logger) *sql.DB {
-	db, err := sql.Open("sqlserver", dsn)
+func openDB(cfg config.DatabaseConfig, name string, logger *logrus.Logger) (*sql.DB, *connector) {
+	logger.WithFields(logrus.Fields{
+		"dsn":                cfg.RedactedDSN(),
+		"max_open_conns":     cfg.MaxOpenConns,
+		"max_idle_conns":     cfg.MaxIdleConns,
+		"conn_max_lifetime":  cfg.ConnMaxLifetime.String(),
+		"conn_max_idle_time": cfg.ConnMaxIdleTime.String(),
+	}).Infof("Opening %s database", name)
+
+	conn, err := newConnector(cfg)
 	if err != nil {
 		logger.Warnf("Failed to open %s database: %v", name, err)
-		return nil
+		return nil, nil
 	}
+	db := sql.OpenDB(conn)
 
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	if err := db.Ping(); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), startupPingTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
 		logger.Warnf("%s database not available at startup: %v (will retry automatically)", name, err)
 	} else {
 		logger.Infof("Successfully connected to %s database", name)
 	}
 
-	return db
+	return db, conn
+}
+
+// UpdatePasswords switches each pool to the password in its new settings.
+// Connections already open stay logged in; new ones use the new password.
+// Other connection settings only change on restart.
+func (dm *DBManager) UpdatePasswords(ticketCfg, machineCfg, tokenCfg config.DatabaseConfig) error {
+	for _, pool := range []struct {
+		name string
+		conn *connector
+		cfg  config.DatabaseConfig
+	}{
+		{"ticket_master", dm.ticketConn, ticketCfg},
+		{"machine_master", dm.machineConn, machineCfg},
+		{"token_management", dm.tokenConn, tokenCfg},
+	} {
+		if pool.conn == nil || pool.conn.cfg.Password == pool.cfg.Password {
+			continue
+		}
+		updated := pool.conn.cfg
+		updated.Password = pool.cfg.Password
+		if err := pool.conn.set(updated); err != nil {
+			return fmt.Errorf("update %s password: %w", pool.name, err)
+		}
+		dm.logger.Infof("Password of %s database updated; new connections use it", pool.name)
+	}
+	return nil
 }
 
 // Close gracefully closes all database connections
@@ -103,31 +188,11 @@ func (dm *DBManager) Close() error {
 	return firstErr
 }
 
-// DatabaseHealth holds the real-time health status of each database connection
-type DatabaseHealth struct {
-	TicketDB  string `json:"ticket_database"`
-	MachineDB string `json:"machine_database"`
-	TokenDB   string `json:"token_database"`
-}
-
-// HealthCheck pings all databases in real-time and returns their current status.
-// If a database was unavailable at startup but has since come online, this will
-// reflect the updated status.
-func (dm *DBManager) HealthCheck() *DatabaseHealth {
-	return &DatabaseHealth{
-		TicketDB:  checkDB(dm.TicketDB),
-		MachineDB: checkDB(dm.MachineDB),
-		TokenDB:   checkDB(dm.TokenDB),
-	}
-}
-
-// checkDB pings a single database and returns its status string
-func checkDB(db *sql.DB) string {
+// Pinger returns db's PingContext for the health prober, or nil when the
+// database is not configured.
+func Pinger(db *sql.DB) func(ctx context.Context) error {
 	if db == nil {
-		return "not configured"
-	}
-	if err := db.Ping(); err != nil {
-		return fmt.Sprintf("disconnected: %v", err)
+		return nil
 	}
-	return "connected"
+	return db.PingContext
 }
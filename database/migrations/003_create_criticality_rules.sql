@@ -0,0 +1,66 @@
+-- ============================================================================
+-- Migration 003: Criticality Rules
+-- ============================================================================
+-- Purpose: Store the admin-managed rule set that decides which open tickets
+--          are reported by GET /api/v1/stats/critical.
+--          Every non-NULL condition on a rule must match (AND); a terminal is
+--          critical when at least one active rule matches (OR).
+-- ============================================================================
+
+USE token_management;
+GO
+
+-- ============================================================================
+-- Table: criticality_rules
+-- ============================================================================
+IF OBJECT_ID('criticality_rules', 'U') IS NULL
+BEGIN
+    CREATE TABLE criticality_rules (
+        id INT IDENTITY(1,1) PRIMARY KEY,
+        name NVARCHAR(200) NOT NULL,
+        description NVARCHAR(500),
+
+        -- Conditions (NULL = not checked)
+        priority NVARCHAR(50),              -- exact match on op.[Priority], e.g. 1.High
+        mode NVARCHAR(50),                  -- exact match on op.[Mode], e.g. Off-line
+        status NVARCHAR(100),               -- exact match on op.[Status], e.g. 0.NEW
+        min_tickets_duration FLOAT,         -- op.[Tickets duration] >= value (minutes)
+        max_balance INT,                    -- op.[Balance] <= value
+        region NVARCHAR(200),               -- atmi province / city / district or FLM area
+
+        is_active BIT NOT NULL DEFAULT 1,
+
+        -- Metadata
+        created_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+        updated_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+        created_by INT,
+
+        -- Foreign Keys
+        CONSTRAINT fk_criticality_rules_created_by FOREIGN KEY (created_by) REFERENCES admin_users(id),
+
+        -- Indexes
+        INDEX idx_is_active (is_active)
+    );
+    PRINT 'Table criticality_rules created.';
+END
+GO
+
+-- ============================================================================
+-- Default rules (the previous "tribal knowledge" definition)
+-- ============================================================================
+IF NOT EXISTS (SELECT 1 FROM criticality_rules)
+BEGIN
+    INSERT INTO criticality_rules (name, description, priority, mode, min_tickets_duration, created_by)
+    VALUES (
+        'High priority off-line',
+        'High priority terminals that have been off-line for more than 4 hours',
+        '1.High', 'Off-line', 240, 1
+    );
+    PRINT 'Default criticality rule created.';
+END
+GO
+
+PRINT '============================================';
+PRINT 'Migration 003 applied successfully!';
+PRINT '============================================';
+GO
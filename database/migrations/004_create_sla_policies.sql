@@ -0,0 +1,87 @@
+-- ============================================================================
+-- Migration 004: SLA Policies & Business-Hours Calendars
+-- ============================================================================
+-- Purpose: Store admin-managed SLA policies used to compute sla_due_at,
+--          sla_remaining_minutes and sla_breached on every data row.
+--          A policy targets one ticket priority and optionally one FLM vendor
+--          (mm.[FLM name]); the vendor-specific policy wins over the default.
+--          Targets are counted in business minutes of the linked calendar,
+--          or around the clock when no calendar is linked.
+-- ============================================================================
+
+USE token_management;
+GO
+
+-- ============================================================================
+-- Table: sla_calendars
+-- ============================================================================
+IF OBJECT_ID('sla_calendars', 'U') IS NULL
+BEGIN
+    CREATE TABLE sla_calendars (
+        id INT IDENTITY(1,1) PRIMARY KEY,
+        name NVARCHAR(200) NOT NULL UNIQUE,
+        timezone NVARCHAR(100) NOT NULL DEFAULT 'Asia/Jakarta', -- IANA zone name
+        work_days NVARCHAR(20) NOT NULL DEFAULT '1,2,3,4,5',    -- 0 = Sunday ... 6 = Saturday
+        work_start CHAR(5) NOT NULL DEFAULT '08:00',            -- HH:MM
+        work_end CHAR(5) NOT NULL DEFAULT '17:00',              -- HH:MM
+        holidays NVARCHAR(MAX),                                 -- JSON array of YYYY-MM-DD
+
+        -- Metadata
+        created_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+        updated_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+        created_by INT,
+
+        CONSTRAINT fk_sla_calendars_created_by FOREIGN KEY (created_by) REFERENCES admin_users(id)
+    );
+    PRINT 'Table sla_calendars created.';
+END
+GO
+
+-- ============================================================================
+-- Table: sla_policies
+-- ============================================================================
+IF OBJECT_ID('sla_policies', 'U') IS NULL
+BEGIN
+    CREATE TABLE sla_policies (
+        id INT IDENTITY(1,1) PRIMARY KEY,
+        name NVARCHAR(200) NOT NULL,
+        priority NVARCHAR(50) NOT NULL,       -- op.[Priority], e.g. 1.High
+        vendor_name NVARCHAR(200),            -- mm.[FLM name]; NULL = all vendors
+        response_minutes INT,                 -- time allowed to leave 0.NEW
+        resolution_minutes INT NOT NULL,      -- time allowed to close the ticket
+        calendar_id INT,                      -- NULL = 24x7
+        is_active BIT NOT NULL DEFAULT 1,
+
+        -- Metadata
+        created_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+        updated_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+        created_by INT,
+
+        -- Foreign Keys
+        CONSTRAINT fk_sla_policies_calendar_id FOREIGN KEY (calendar_id) REFERENCES sla_calendars(id),
+        CONSTRAINT fk_sla_policies_created_by FOREIGN KEY (created_by) REFERENCES admin_users(id),
+
+        -- One policy per priority/vendor pair (one default per priority)
+        CONSTRAINT uq_sla_policy_target UNIQUE (priority, vendor_name),
+
+        INDEX idx_is_active (is_active)
+    );
+    PRINT 'Table sla_policies created.';
+END
+GO
+
+-- ============================================================================
+-- Default calendar
+-- ============================================================================
+IF NOT EXISTS (SELECT 1 FROM sla_calendars WHERE name = 'Office hours (WIB)')
+BEGIN
+    INSERT INTO sla_calendars (name, timezone, work_days, work_start, work_end, holidays, created_by)
+    VALUES ('Office hours (WIB)', 'Asia/Jakarta', '1,2,3,4,5', '08:00', '17:00', '[]', 1);
+    PRINT 'Default SLA calendar created.';
+END
+GO
+
+PRINT '============================================';
+PRINT 'Migration 004 applied successfully!';
+PRINT '============================================';
+GO
@@ -0,0 +1,38 @@
+-- ============================================================================
+-- Migration 005: Row Version on open_ticket
+-- ============================================================================
+-- Purpose: Give every ticket row a rowversion so GET /api/v1/data/stream can
+--          detect created/updated tickets with a single watermark poll
+--          (WHERE [Row Version] > @last) instead of rescanning the table.
+--          SQL Server bumps the value automatically on every INSERT/UPDATE;
+--          no changes are needed in the systems writing to open_ticket.
+-- ============================================================================
+
+USE ticket_master;
+GO
+
+IF NOT EXISTS (
+    SELECT 1 FROM sys.columns
+    WHERE object_id = OBJECT_ID('dbo.open_ticket') AND name = 'Row Version'
+)
+BEGIN
+    ALTER TABLE dbo.open_ticket
+    ADD [Row Version] ROWVERSION;
+    PRINT 'Column [Row Version] added to open_ticket.';
+END
+GO
+
+IF NOT EXISTS (
+    SELECT 1 FROM sys.indexes
+    WHERE object_id = OBJECT_ID('dbo.open_ticket') AND name = 'idx_open_ticket_row_version'
+)
+BEGIN
+    CREATE INDEX idx_open_ticket_row_version ON dbo.open_ticket ([Row Version]);
+    PRINT 'Index idx_open_ticket_row_version created.';
+END
+GO
+
+PRINT '============================================';
+PRINT 'Migration 005 applied successfully!';
+PRINT '============================================';
+GO
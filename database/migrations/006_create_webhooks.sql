@@ -0,0 +1,81 @@
+-- ============================================================================
+-- Migration 006: Webhook Subscriptions & Delivery Outbox
+-- ============================================================================
+-- Purpose: Let API tokens subscribe a URL to ticket events
+--          (ticket.created, ticket.updated, ticket.closed).
+--          Every event is written to webhook_outbox, one row per matching
+--          subscription, and delivered by a background worker with an
+--          HMAC-SHA256 signature and exponential-backoff retries.
+--          Deliveries that exhaust their attempts stay in the outbox with
+--          status 'dead' (the dead-letter view) until retried by an admin.
+--          Events are only queued for terminals inside the token's vendor scope.
+-- ============================================================================
+
+USE token_management;
+GO
+
+-- ============================================================================
+-- Table: webhook_subscriptions
+-- ============================================================================
+IF OBJECT_ID('webhook_subscriptions', 'U') IS NULL
+BEGIN
+    CREATE TABLE webhook_subscriptions (
+        id INT IDENTITY(1,1) PRIMARY KEY,
+        token_id INT NOT NULL,
+        url NVARCHAR(2000) NOT NULL,
+        event_types NVARCHAR(200),              -- comma-separated; NULL/empty = all events
+        secret NVARCHAR(200) NOT NULL,          -- HMAC-SHA256 signing key
+        is_active BIT NOT NULL DEFAULT 1,
+
+        -- Metadata
+        created_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+        updated_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+        created_by INT,
+
+        -- Foreign Keys
+        CONSTRAINT fk_webhook_subscriptions_token_id FOREIGN KEY (token_id) REFERENCES api_tokens(id) ON DELETE CASCADE,
+        CONSTRAINT fk_webhook_subscriptions_created_by FOREIGN KEY (created_by) REFERENCES admin_users(id),
+
+        INDEX idx_token_id (token_id),
+        INDEX idx_is_active (is_active)
+    );
+    PRINT 'Table webhook_subscriptions created.';
+END
+GO
+
+-- ============================================================================
+-- Table: webhook_outbox
+-- ============================================================================
+IF OBJECT_ID('webhook_outbox', 'U') IS NULL
+BEGIN
+    CREATE TABLE webhook_outbox (
+        id BIGINT IDENTITY(1,1) PRIMARY KEY,
+        subscription_id INT NOT NULL,
+        event_id UNIQUEIDENTIFIER NOT NULL,     -- same for every subscription receiving the event
+        event_type NVARCHAR(50) NOT NULL,
+        terminal_id NVARCHAR(50) NOT NULL,
+        payload NVARCHAR(MAX) NOT NULL,         -- JSON body sent to the subscriber
+
+        -- Delivery state
+        status NVARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, delivered, dead
+        attempts INT NOT NULL DEFAULT 0,
+        next_attempt_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+        last_status_code INT,
+        last_error NVARCHAR(1000),
+        delivered_at DATETIME2,
+
+        created_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+
+        CONSTRAINT fk_webhook_outbox_subscription_id FOREIGN KEY (subscription_id) REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+
+        INDEX idx_status_next_attempt (status, next_attempt_at),
+        INDEX idx_subscription_id (subscription_id)
+    );
+    PRINT 'Table webhook_outbox created.';
+END
+GO
+
+PRINT '============================================';
+PRINT 'Migration 006 applied successfully!';
+PRINT '============================================';
+GO
@@ -0,0 +1,79 @@
+-- ============================================================================
+-- Migration 007: Cloud App Synchronisation Queue & Reconciliation History
+-- ============================================================================
+-- Purpose: Mirror ticket updates made through PUT /api/v1/data/:terminal_id
+--          to the cloud app (CLOUD_APP_URL).
+--          Every update writes a full ticket snapshot to cloud_sync_queue;
+--          a background worker pushes it with exponential-backoff retries.
+--          A newer snapshot of the same terminal supersedes older pending ones.
+--          Items that exhaust their attempts stay with status 'failed' until
+--          retried by an admin.
+--          A periodic reconciliation compares open_ticket with the cloud app,
+--          queues repairs and records a summary in cloud_sync_reconciliations.
+-- ============================================================================
+
+USE token_management;
+GO
+
+-- ============================================================================
+-- Table: cloud_sync_queue
+-- ============================================================================
+IF OBJECT_ID('cloud_sync_queue', 'U') IS NULL
+BEGIN
+    CREATE TABLE cloud_sync_queue (
+        id BIGINT IDENTITY(1,1) PRIMARY KEY,
+        terminal_id NVARCHAR(50) NOT NULL,
+        source NVARCHAR(20) NOT NULL DEFAULT 'update', -- update, reconcile
+        payload NVARCHAR(MAX) NOT NULL,                -- JSON ticket snapshot sent to the cloud app
+
+        -- Sync state
+        status NVARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, synced, failed, superseded
+        attempts INT NOT NULL DEFAULT 0,
+        next_attempt_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+        last_attempt_at DATETIME2,
+        last_status_code INT,
+        last_error NVARCHAR(1000),
+        synced_at DATETIME2,
+
+        created_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+
+        INDEX idx_status_next_attempt (status, next_attempt_at),
+        INDEX idx_terminal_id (terminal_id)
+    );
+    PRINT 'Table cloud_sync_queue created.';
+END
+GO
+
+-- ============================================================================
+-- Table: cloud_sync_reconciliations
+-- ============================================================================
+IF OBJECT_ID('cloud_sync_reconciliations', 'U') IS NULL
+BEGIN
+    CREATE TABLE cloud_sync_reconciliations (
+        id INT IDENTITY(1,1) PRIMARY KEY,
+        started_at DATETIME2 NOT NULL,
+        finished_at DATETIME2 NOT NULL,
+        triggered_by INT,                   -- admin user; NULL for scheduled runs
+
+        local_count INT NOT NULL DEFAULT 0,
+        remote_count INT NOT NULL DEFAULT 0,
+        matched INT NOT NULL DEFAULT 0,
+        mismatched INT NOT NULL DEFAULT 0,  -- present on both sides with different fields
+        missing_remote INT NOT NULL DEFAULT 0,
+        extra_remote INT NOT NULL DEFAULT 0, -- open in the cloud app, not in open_ticket
+        requeued INT NOT NULL DEFAULT 0,
+        error NVARCHAR(1000),
+        details NVARCHAR(MAX),              -- JSON array of differences (capped)
+
+        CONSTRAINT fk_cloud_sync_reconciliations_triggered_by FOREIGN KEY (triggered_by) REFERENCES admin_users(id),
+
+        INDEX idx_started_at (started_at)
+    );
+    PRINT 'Table cloud_sync_reconciliations created.';
+END
+GO
+
+PRINT '============================================';
+PRINT 'Migration 007 applied successfully!';
+PRINT '============================================';
+GO
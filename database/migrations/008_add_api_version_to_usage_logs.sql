@@ -0,0 +1,38 @@
+-- ============================================================================
+-- Migration 008: API Version on token_usage_logs
+-- ============================================================================
+-- Purpose: Record which REST API version (v1 or v2) each request used, so
+--          GET /api/v1/admin/analytics/api-versions can show which tokens
+--          still call the deprecated /api/v1 data endpoints.
+--          Rows logged before this migration, and gRPC calls, keep NULL.
+-- ============================================================================
+
+USE token_management;
+GO
+
+IF NOT EXISTS (
+    SELECT 1 FROM sys.columns
+    WHERE object_id = OBJECT_ID('token_usage_logs') AND name = 'api_version'
+)
+BEGIN
+    ALTER TABLE token_usage_logs
+    ADD api_version NVARCHAR(10) NULL;
+    PRINT 'Column api_version added to token_usage_logs.';
+END
+GO
+
+IF NOT EXISTS (
+    SELECT 1 FROM sys.indexes
+    WHERE object_id = OBJECT_ID('token_usage_logs') AND name = 'idx_api_version_created_at'
+)
+BEGIN
+    CREATE INDEX idx_api_version_created_at ON token_usage_logs (api_version, created_at)
+        INCLUDE (token_id, endpoint);
+    PRINT 'Index idx_api_version_created_at created.';
+END
+GO
+
+PRINT '============================================';
+PRINT 'Migration 008 applied successfully!';
+PRINT '============================================';
+GO
@@ -0,0 +1,27 @@
+-- ============================================================================
+-- Migration 009: Error Code Index on token_usage_logs
+-- ============================================================================
+-- Purpose: token_usage_logs.error_code now holds the stable error code of
+--          every failed request (e.g. rate_limited, out_of_scope).
+--          GET /api/v1/admin/analytics/errors groups by it over a time window.
+-- ============================================================================
+
+USE token_management;
+GO
+
+IF NOT EXISTS (
+    SELECT 1 FROM sys.indexes
+    WHERE object_id = OBJECT_ID('token_usage_logs') AND name = 'idx_error_code_created_at'
+)
+BEGIN
+    CREATE INDEX idx_error_code_created_at ON token_usage_logs (error_code, created_at)
+        INCLUDE (token_id)
+        WHERE error_code IS NOT NULL;
+    PRINT 'Index idx_error_code_created_at created.';
+END
+GO
+
+PRINT '============================================';
+PRINT 'Migration 009 applied successfully!';
+PRINT '============================================';
+GO
@@ -0,0 +1,49 @@
+-- ============================================================================
+-- Migration 010: Idempotency Keys
+-- ============================================================================
+-- Purpose: Make retried writes safe. A mutating data request sent with an
+--          Idempotency-Key header is recorded here per token together with a
+--          hash of the request and, once it finishes, its response.
+--          A replay within IDEMPOTENCY_TTL gets the stored response back
+--          instead of running again; reusing the key for a different request
+--          is rejected. Expired rows are purged by the gateway.
+-- ============================================================================
+
+USE token_management;
+GO
+
+IF OBJECT_ID('idempotency_keys', 'U') IS NULL
+BEGIN
+    CREATE TABLE idempotency_keys (
+        id BIGINT IDENTITY(1,1) PRIMARY KEY,
+        token_id INT NOT NULL,
+        idempotency_key NVARCHAR(255) NOT NULL,
+
+        -- Request fingerprint
+        method NVARCHAR(10) NOT NULL,
+        path NVARCHAR(1000) NOT NULL,
+        request_hash CHAR(64) NOT NULL,                    -- hex SHA-256 of method, URI and body
+
+        -- Stored response
+        status NVARCHAR(20) NOT NULL DEFAULT 'in_progress', -- in_progress, completed
+        response_status INT,
+        response_content_type NVARCHAR(100),
+        response_body VARBINARY(MAX),
+
+        created_at DATETIME2 NOT NULL DEFAULT GETDATE(),
+        completed_at DATETIME2,
+        expires_at DATETIME2 NOT NULL,
+
+        CONSTRAINT fk_idempotency_keys_token_id FOREIGN KEY (token_id) REFERENCES api_tokens(id) ON DELETE CASCADE,
+        CONSTRAINT uq_idempotency_keys_token_key UNIQUE (token_id, idempotency_key),
+
+        INDEX idx_expires_at (expires_at)
+    );
+    PRINT 'Table idempotency_keys created.';
+END
+GO
+
+PRINT '============================================';
+PRINT 'Migration 010 applied successfully!';
+PRINT '============================================';
+GO
@@ -0,0 +1,37 @@
+-- ============================================================================
+-- Migration 011: Request ID on audit_logs
+-- ============================================================================
+-- Purpose: Record the X-Request-ID of the request behind each audit entry, so
+--          an admin action can be matched with its token_usage_logs row and
+--          application log lines. Entries written before this migration, and
+--          by background workers, keep NULL.
+-- ============================================================================
+
+USE token_management;
+GO
+
+IF NOT EXISTS (
+    SELECT 1 FROM sys.columns
+    WHERE object_id = OBJECT_ID('audit_logs') AND name = 'request_id'
+)
+BEGIN
+    ALTER TABLE audit_logs
+    ADD request_id NVARCHAR(100) NULL;
+    PRINT 'Column request_id added to audit_logs.';
+END
+GO
+
+IF NOT EXISTS (
+    SELECT 1 FROM sys.indexes
+    WHERE object_id = OBJECT_ID('audit_logs') AND name = 'idx_audit_logs_request_id'
+)
+BEGIN
+    CREATE INDEX idx_audit_logs_request_id ON audit_logs (request_id);
+    PRINT 'Index idx_audit_logs_request_id created.';
+END
+GO
+
+PRINT '============================================';
+PRINT 'Migration 011 applied successfully!';
+PRINT '============================================';
+GO
@@ -0,0 +1,38 @@
+-- ============================================================================
+-- Migration 012: Client certificate binding on api_tokens
+-- ============================================================================
+-- Purpose: Let a vendor token be bound to the TLS client certificate it must be
+--          presented with (mTLS). client_cert_fingerprint is the SHA-256 of
+--          the DER certificate in lowercase hex; client_cert_subject is the
+--          subject DN in RFC 2253 form. NULL in both leaves the token unbound.
+-- ============================================================================
+
+USE token_management;
+GO
+
+IF NOT EXISTS (
+    SELECT 1 FROM sys.columns
+    WHERE object_id = OBJECT_ID('api_tokens') AND name = 'client_cert_fingerprint'
+)
+BEGIN
+    ALTER TABLE api_tokens
+    ADD client_cert_fingerprint NVARCHAR(64) NULL;
+    PRINT 'Column client_cert_fingerprint added to api_tokens.';
+END
+GO
+
+IF NOT EXISTS (
+    SELECT 1 FROM sys.columns
+    WHERE object_id = OBJECT_ID('api_tokens') AND name = 'client_cert_subject'
+)
+BEGIN
+    ALTER TABLE api_tokens
+    ADD client_cert_subject NVARCHAR(500) NULL;
+    PRINT 'Column client_cert_subject added to api_tokens.';
+END
+GO
+
+PRINT '============================================';
+PRINT 'Migration 012 applied successfully!';
+PRINT '============================================';
+GO
@@ -0,0 +1,19 @@
+// Package docs holds the OpenAPI specs generated from the handler annotations
+// by `make docs` (swag). The full spec is registered as the default instance
+// and served at /admin/docs; the "public" instance (Data and Health tags only)
+// is served at /docs. Do not edit the generated files by hand.
+//
+// The general API info of the public spec is below.
+//
+// @title ATM Monitoring API
+// @version 1.0
+// @description Public API for retrieving ATM terminal data. Authentication requires an X-API-Token issued by the system administrator.
+// @contact.name API Support
+// @contact.email support@example.com
+// @license.name Proprietary
+// @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Token
+// @description API token provided by the system administrator. Send in the X-API-Token request header.
+package docs
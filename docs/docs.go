@@ -0,0 +1,5381 @@
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
+package docs
+
+import "github.com/swaggo/swag"
+
+const docTemplate = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "termsOfService": "http://swagger.io/terms/",
+        "contact": {
+            "name": "API Support",
+            "url": "http://www.example.com/support",
+            "email": "support@example.com"
+        },
+        "license": {
+            "name": "Proprietary",
+            "url": "http://www.example.com/license"
+        },
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/api/v1/admin/analytics/api-versions": {
+            "get": {
+                "description": "Get v1 and v2 request counts per token, so clients still calling the deprecated v1 API can be found. Tokens with v1 traffic are listed first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get API Version Usage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/analytics/daily": {
+            "get": {
+                "description": "Get daily request volume",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get Daily Usage",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by Token ID",
+                        "name": "token_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/analytics/dashboard": {
+            "get": {
+                "description": "Get overview statistics for the admin dashboard",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get Dashboard Stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/analytics/endpoints": {
+            "get": {
+                "description": "Get usage statistics by endpoint",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get Endpoint Stats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days (default 7)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit results (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/analytics/errors": {
+            "get": {
+                "description": "Get failed request counts grouped by stable error code (e.g. rate_limited, out_of_scope)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get Error Stats",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days (default 7)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filter by Token ID",
+                        "name": "token_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/analytics/tokens/{id}": {
+            "get": {
+                "description": "Get detailed analytics for a specific token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get Token Analytics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of days (default 7)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/audit-logs": {
+            "get": {
+                "description": "Get administrative audit logs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Auth"
+                ],
+                "summary": "Get Audit Logs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit results (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/auth/login": {
+            "post": {
+                "description": "Authenticate admin user and return session token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Auth"
+                ],
+                "summary": "Admin Login",
+                "parameters": [
+                    {
+                        "description": "Login Credentials",
+                        "name": "login",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/auth/logout": {
+            "post": {
+                "description": "Invalidate current session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Auth"
+                ],
+                "summary": "Admin Logout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session Token",
+                        "name": "X-Session-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/auth/me": {
+            "get": {
+                "description": "Get details of currently logged in admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin Auth"
+                ],
+                "summary": "Get Current User",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/cloud-sync/failures": {
+            "get": {
+                "description": "Ticket snapshots that exhausted their retry attempts, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud Sync"
+                ],
+                "summary": "List Cloud Sync Failures",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max rows (default: 100, max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CloudSyncItemListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/cloud-sync/queue": {
+            "get": {
+                "description": "Recent queued ticket snapshots, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud Sync"
+                ],
+                "summary": "List Cloud Sync Queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, synced, failed or superseded",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max rows (default: 100, max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CloudSyncItemListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/cloud-sync/queue/{id}/retry": {
+            "post": {
+                "description": "Put a failed snapshot back in the queue with a fresh set of attempts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud Sync"
+                ],
+                "summary": "Retry Cloud Sync Item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Queue item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Cloud sync not configured",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/cloud-sync/reconcile": {
+            "post": {
+                "description": "Compare open_ticket with the cloud app now. Missing or differing tickets are queued again; tickets only the cloud app holds are reported.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud Sync"
+                ],
+                "summary": "Run Cloud Sync Reconciliation",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CloudSyncReconciliationResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Cloud sync not configured",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/cloud-sync/reconciliations": {
+            "get": {
+                "description": "Recent reconciliation runs (scheduled and manual), newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud Sync"
+                ],
+                "summary": "List Cloud Sync Reconciliations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max rows (default: 100, max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CloudSyncReconciliationListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/cloud-sync/status": {
+            "get": {
+                "description": "Whether cloud app sync is configured, queue counts, the most recent success and failure, and the latest reconciliation run",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cloud Sync"
+                ],
+                "summary": "Cloud Sync Status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CloudSyncStatusResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/criticality-rules": {
+            "get": {
+                "description": "Get all criticality rules used by GET /api/v1/stats/critical",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Criticality Rules"
+                ],
+                "summary": "List Criticality Rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CriticalityRuleListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a criticality rule. At least one condition (priority, mode, status, min_tickets_duration, max_balance, region) is required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Criticality Rules"
+                ],
+                "summary": "Create Criticality Rule",
+                "parameters": [
+                    {
+                        "description": "Rule Details",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CriticalityRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CriticalityRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/criticality-rules/{id}": {
+            "get": {
+                "description": "Get a single criticality rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Criticality Rules"
+                ],
+                "summary": "Get Criticality Rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CriticalityRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace all fields of a criticality rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Criticality Rules"
+                ],
+                "summary": "Update Criticality Rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule Details",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CriticalityRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CriticalityRuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently delete a criticality rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Criticality Rules"
+                ],
+                "summary": "Delete Criticality Rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/sla/calendars": {
+            "get": {
+                "description": "Get all business-hours calendars SLA policies can be counted in",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SLA"
+                ],
+                "summary": "List SLA Calendars",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a business-hours calendar. timezone is an IANA zone name, work_days are 0 (Sunday) to 6 (Saturday), holidays are YYYY-MM-DD dates.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SLA"
+                ],
+                "summary": "Create SLA Calendar",
+                "parameters": [
+                    {
+                        "description": "Calendar Details",
+                        "name": "calendar",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SLACalendarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLACalendarResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/sla/calendars/{id}": {
+            "get": {
+                "description": "Get a single SLA calendar",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SLA"
+                ],
+                "summary": "Get SLA Calendar",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Calendar ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLACalendarResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace all fields of an SLA calendar",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SLA"
+                ],
+                "summary": "Update SLA Calendar",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Calendar ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Calendar Details",
+                        "name": "calendar",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SLACalendarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLACalendarResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently delete an SLA calendar. Fails while a policy still uses it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SLA"
+                ],
+                "summary": "Delete SLA Calendar",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Calendar ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/sla/policies": {
+            "get": {
+                "description": "Get all SLA policies used to compute sla_due_at / sla_breached on data rows",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SLA"
+                ],
+                "summary": "List SLA Policies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create an SLA policy for a ticket priority, optionally narrowed to one FLM vendor. Omit calendar_id to count targets around the clock.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SLA"
+                ],
+                "summary": "Create SLA Policy",
+                "parameters": [
+                    {
+                        "description": "Policy Details",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SLAPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLAPolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/sla/policies/{id}": {
+            "get": {
+                "description": "Get a single SLA policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SLA"
+                ],
+                "summary": "Get SLA Policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLAPolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace all fields of an SLA policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SLA"
+                ],
+                "summary": "Update SLA Policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Policy Details",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SLAPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLAPolicyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently delete an SLA policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SLA"
+                ],
+                "summary": "Delete SLA Policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tokens": {
+            "get": {
+                "description": "Get all API tokens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token Management"
+                ],
+                "summary": "List API Tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new API token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token Management"
+                ],
+                "summary": "Create API Token",
+                "parameters": [
+                    {
+                        "description": "Token Details",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tokens/{id}": {
+            "get": {
+                "description": "Get details of a specific API token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token Management"
+                ],
+                "summary": "Get API Token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update details of an existing API token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token Management"
+                ],
+                "summary": "Update API Token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Details",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently delete an API token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token Management"
+                ],
+                "summary": "Delete API Token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tokens/{id}/disable": {
+            "patch": {
+                "description": "Temporarily disable an API token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token Management"
+                ],
+                "summary": "Disable API Token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tokens/{id}/enable": {
+            "patch": {
+                "description": "Re-enable a disabled API token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Token Management"
+                ],
+                "summary": "Enable API Token",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tokens/{id}/logs": {
+            "get": {
+                "description": "Get access logs for a specific token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Analytics"
+                ],
+                "summary": "Get Token Logs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit results (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tokens/{id}/webhooks": {
+            "get": {
+                "description": "Get the webhook subscriptions of an API token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List Token Webhooks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe a URL to ticket events (ticket.created, ticket.updated, ticket.closed; empty event_types = all). Only terminals inside the token's vendor scope are delivered. A signing secret is generated when none is given and is returned only in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Create Token Webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook Details",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tokens/{id}/webhooks/{webhook_id}": {
+            "get": {
+                "description": "Get a single webhook subscription (the secret is never returned)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Get Token Webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace a webhook's URL, event filter and status. Supplying a secret rotates it; omitting it keeps the current one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Update Token Webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook Details",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookSubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently delete a webhook subscription and its queued deliveries",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Delete Token Webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/tokens/{id}/webhooks/{webhook_id}/deliveries": {
+            "get": {
+                "description": "Recent outbox rows of one webhook, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List Webhook Deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Token ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, delivered or dead",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max rows (default: 100, max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDeliveryListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/webhooks/dead-letters": {
+            "get": {
+                "description": "Webhook deliveries that exhausted their retry attempts, across all tokens, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "List Dead-Letter Deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Max rows (default: 100, max: 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDeliveryListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/admin/webhooks/deliveries/{delivery_id}/retry": {
+            "post": {
+                "description": "Put an undelivered (typically dead) delivery back in the queue with a fresh set of attempts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhooks"
+                ],
+                "summary": "Retry Webhook Delivery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/data": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve joined ticket+machine rows with pagination, sorting, and filtering. Vendor-scoped tokens only see rows matching their filter. Admin/Internal tokens see all rows.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get all data",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number (default: all results)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Items per page (default: 100, max: 500)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: terminal_id, terminal_name, priority, mode, status, incident_start_datetime, count, balance, tickets_duration, open_time, close_time, flm_name, flm, slm, net",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort direction: asc or desc (default: desc)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by terminal_id or terminal_name (partial match)",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact status value (e.g. 0.NEW)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact mode value (e.g. Off-line)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact priority value (e.g. 1.High)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by computed SLA breach state (true or false)",
+                        "name": "sla_breached",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Data retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.DataListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid sla_breached value",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/data/by-flm": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve open tickets grouped by machine FLM branch with the service area and per-group counts. Groups are sorted by count (largest first). Vendor-scoped tokens only see rows matching their filter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get tickets grouped by FLM",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by exact status value (e.g. 0.NEW)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact priority value (e.g. 1.High)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tickets by FLM retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.TicketsByFLMResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/data/metadata": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve all valid values for status, mode, and priority fields. Cached for 1 hour.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get field metadata",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "Metadata retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.MetadataResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/data/stream": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events feed of created, updated and closed tickets. Each event's data is a ChangeEvent; vendor-scoped tokens only receive events for rows inside their filter. Reconnect with the Last-Event-ID header (or last_event_id query parameter) to resume; a \"reset\" event means the missed events are no longer available and the client should refetch GET /data.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Stream ticket changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Same as the Last-Event-ID header, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/data/{terminal_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve a single joined row by terminal ID. Vendor tokens return 404 if the terminal is outside their scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get data by terminal ID",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Terminal ID",
+                        "name": "terminal_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Data retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.DataResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update ticket fields for a terminal. Vendor tokens can only update terminals within their scope (returns 403 otherwise). Admin/Internal tokens can update any terminal.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Update ticket fields",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Terminal ID",
+                        "name": "terminal_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DataUpdateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key per intended update; a retry with the same key and body replays the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Outside vendor scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A request with this Idempotency-Key is still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key reused with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/graphql": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Read-only GraphQL API over open tickets (DataRow), machine master records, metadata and aggregated stats. Vendor-scoped tokens only see rows inside their filter. Queries deeper than GRAPHQL_MAX_DEPTH, or that could return more than GRAPHQL_MAX_ROWS records, are rejected before they run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "GraphQL query",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Query document (GET)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON-encoded variables (GET)",
+                        "name": "variables",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operation to run when the document has several (GET)",
+                        "name": "operationName",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data and, when a field failed, errors",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Syntax, validation or query cost error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stats/critical": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List open tickets that match at least one active criticality rule, longest-running first. Each row includes the terminal location and GPS from atmi. Vendor-scoped tokens only see terminals in their scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Get critical terminals",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "Critical terminals retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.CriticalTerminalsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/data": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Joined ticket+machine rows, one page at a time. Same filters and sorting as v1 GET /data, but results are always paginated and the filters are not echoed back.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "List data",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Items per page (default: 100, max: 500)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field, as on v1",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort direction: asc or desc (default: desc)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by terminal_id or terminal_name (partial match)",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact status value (e.g. 0.NEW)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact mode value (e.g. Off-line)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact priority value (e.g. 1.High)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by computed SLA breach state (true or false)",
+                        "name": "sla_breached",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of rows",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DataRow"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/models.EnvelopeMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid_request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "missing_token, invalid_token, token_revoked, token_expired or token_disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "rate_limited",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal_error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/data/by-flm": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Open tickets grouped by machine FLM branch, largest group first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get tickets grouped by FLM",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by exact status value (e.g. 0.NEW)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact priority value (e.g. 1.High)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Groups",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.FLMTicketsGroup"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "internal_error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/data/metadata": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "All valid values for the status, mode and priority fields. Cached for 1 hour.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get field metadata",
+                "responses": {
+                    "200": {
+                        "description": "Metadata",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.FieldMetadata"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "internal_error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/data/stream": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events feed of created, updated and closed tickets. Each event's data is a ChangeEvent; vendor-scoped tokens only receive events for rows inside their filter. Reconnect with the Last-Event-ID header (or last_event_id query parameter) to resume; a \"reset\" event means the missed events are no longer available and the client should refetch GET /data.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Stream ticket changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Same as the Last-Event-ID header, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/data/{terminal_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "A single joined row. Terminals outside a vendor token's scope are reported as not_found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get data by terminal ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Terminal ID",
+                        "name": "terminal_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Row",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DataRow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "not_found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update ticket fields for a terminal and return the updated row. Vendor tokens get out_of_scope (403) for terminals outside their scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Update ticket fields",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Terminal ID",
+                        "name": "terminal_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DataUpdateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key per intended update; a retry with the same key and body replays the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated row",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DataRow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid_request or no_fields_to_update",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "out_of_scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "not_found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "idempotency_in_progress",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "idempotency_key_reused",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal_error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/graphql": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Read-only GraphQL API over open tickets (DataRow), machine master records, metadata and aggregated stats. Vendor-scoped tokens only see rows inside their filter. Queries deeper than GRAPHQL_MAX_DEPTH, or that could return more than GRAPHQL_MAX_ROWS records, are rejected before they run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "GraphQL query",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Query document (GET)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON-encoded variables (GET)",
+                        "name": "variables",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operation to run when the document has several (GET)",
+                        "name": "operationName",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data and, when a field failed, errors",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Syntax, validation or query cost error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/stats/critical": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Open tickets matching at least one active criticality rule, longest-running first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Get critical terminals",
+                "responses": {
+                    "200": {
+                        "description": "Critical terminals",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.CriticalTerminal"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "internal_error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "service_unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check the health status of the API and database connections. Same verdict as /readyz in the original healthy/degraded/unhealthy format, with the per-dependency detail under dependencies.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "API is healthy or degraded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/livez": {
+            "get": {
+                "description": "Answers 200 while the process can serve requests. It never touches a dependency, so a hung database cannot fail it; use it for container health checks and restart policies.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "alive",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "Simple ping endpoint to check if the API is running",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Ping",
+                "responses": {
+                    "200": {
+                        "description": "pong",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Reports the cached result of the background dependency prober: per dependency its status, latency of the last ping and last success time. 503 when a critical dependency is down (status not_ready); 200 with status degraded when only non-critical ones are. Never waits on a database.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "ready or degraded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReadinessReport"
+                        }
+                    },
+                    "503": {
+                        "description": "not_ready",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReadinessReport"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.APIToken": {
+            "type": "object",
+            "required": [
+                "environment",
+                "name"
+            ],
+            "properties": {
+                "allowed_origins": {
+                    "description": "JSON array",
+                    "type": "string"
+                },
+                "client_cert_fingerprint": {
+                    "description": "Client certificate binding (mTLS). When set, requests with this token must\npresent a verified client certificate with this SHA-256 fingerprint\n(lowercase hex) and/or subject DN (RFC 2253, e.g. \"CN=avt-client,O=AVT\").",
+                    "type": "string"
+                },
+                "client_cert_subject": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Metadata",
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "environment": {
+                    "description": "Environment \u0026 Status",
+                    "type": "string",
+                    "enum": [
+                        "production",
+                        "staging",
+                        "development",
+                        "test"
+                    ]
+                },
+                "expires_at": {
+                    "description": "Expiration",
+                    "type": "string",
+                    "example": "2024-12-31T23:59:59Z"
+                },
+                "filter_column": {
+                    "type": "string"
+                },
+                "filter_value": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip_whitelist": {
+                    "description": "Security",
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_super_token": {
+                    "type": "boolean"
+                },
+                "last_used_at": {
+                    "description": "Usage Statistics",
+                    "type": "string",
+                    "example": "2024-02-15T10:30:00Z"
+                },
+                "last_used_endpoint": {
+                    "type": "string"
+                },
+                "last_used_ip": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 3
+                },
+                "permissions": {
+                    "description": "JSON object",
+                    "type": "string"
+                },
+                "rate_limit_per_day": {
+                    "type": "integer"
+                },
+                "rate_limit_per_hour": {
+                    "type": "integer"
+                },
+                "rate_limit_per_minute": {
+                    "description": "Rate Limiting",
+                    "type": "integer"
+                },
+                "revoked_at": {
+                    "type": "string",
+                    "example": "2024-02-01T09:00:00Z"
+                },
+                "revoked_by": {
+                    "type": "integer"
+                },
+                "revoked_reason": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "Permissions \u0026 Scopes (stored as JSON in database)",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Only shown once during creation",
+                    "type": "string"
+                },
+                "token_prefix": {
+                    "type": "string"
+                },
+                "total_requests": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "vendor_name": {
+                    "description": "Vendor Data Filter\nVendorName is a human-readable label for the vendor this token is scoped to (e.g. \"AVT\").\nFilterColumn is the logical column key used in the WHERE clause (e.g. \"flm_name\").\nFilterValue is the value that must match (e.g. \"AVT\").\nIsSuperToken bypasses all vendor filters – the token sees and can mutate all data.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AdminUser": {
+            "type": "object",
+            "required": [
+                "email",
+                "role",
+                "username"
+            ],
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "full_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "last_login_at": {
+                    "type": "string",
+                    "example": "2024-01-01T12:00:00Z"
+                },
+                "last_login_ip": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "super_admin",
+                        "admin",
+                        "viewer"
+                    ]
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                }
+            }
+        },
+        "models.ChangeEvent": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "row after the change (last known row for removals)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DataRow"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1042
+                },
+                "occurred_at": {
+                    "description": "when the change was detected",
+                    "type": "string",
+                    "example": "2024-01-15T10:35:00+07:00"
+                },
+                "terminal_id": {
+                    "type": "string",
+                    "example": "ATM-001"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "updated"
+                }
+            }
+        },
+        "models.CloudSyncDiff": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "description": "differing fields (mismatch only)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "status"
+                    ]
+                },
+                "kind": {
+                    "description": "mismatch, missing_remote, extra_remote",
+                    "type": "string",
+                    "example": "mismatch"
+                },
+                "terminal_id": {
+                    "type": "string",
+                    "example": "ATM-001"
+                }
+            }
+        },
+        "models.CloudSyncItem": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "failed"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "terminal_id": {
+                    "type": "string",
+                    "example": "ATM-001"
+                }
+            }
+        },
+        "models.CloudSyncItemListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CloudSyncItem"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CloudSyncQueueStats": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "last_failure_at": {
+                    "type": "string"
+                },
+                "last_failure_error": {
+                    "type": "string"
+                },
+                "last_synced_at": {
+                    "type": "string"
+                },
+                "oldest_pending_at": {
+                    "type": "string"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "superseded": {
+                    "type": "integer"
+                },
+                "synced": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CloudSyncReconciliation": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CloudSyncDiff"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "extra_remote": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "local_count": {
+                    "type": "integer"
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "mismatched": {
+                    "type": "integer"
+                },
+                "missing_remote": {
+                    "type": "integer"
+                },
+                "remote_count": {
+                    "type": "integer"
+                },
+                "requeued": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "triggered_by": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CloudSyncReconciliationListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CloudSyncReconciliation"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CloudSyncReconciliationResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.CloudSyncReconciliation"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CloudSyncStatus": {
+            "type": "object",
+            "properties": {
+                "cloud_app_url": {
+                    "type": "string",
+                    "example": "https://cloud.example.com"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "last_reconciliation": {
+                    "$ref": "#/definitions/models.CloudSyncReconciliation"
+                },
+                "queue": {
+                    "$ref": "#/definitions/models.CloudSyncQueueStats"
+                },
+                "reconcile_interval": {
+                    "type": "string",
+                    "example": "1h0m0s"
+                }
+            }
+        },
+        "models.CloudSyncStatusResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.CloudSyncStatus"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CreateTokenRequest": {
+            "type": "object",
+            "required": [
+                "environment",
+                "name"
+            ],
+            "properties": {
+                "allowed_origins": {
+                    "description": "Will be converted to JSON",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "client_cert_fingerprint": {
+                    "description": "Client certificate binding – optional, see APIToken",
+                    "type": "string"
+                },
+                "client_cert_subject": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "environment": {
+                    "type": "string",
+                    "enum": [
+                        "production",
+                        "staging",
+                        "development",
+                        "test"
+                    ]
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "filter_column": {
+                    "type": "string"
+                },
+                "filter_value": {
+                    "type": "string"
+                },
+                "ip_whitelist": {
+                    "description": "Will be converted to JSON",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_super_token": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 3
+                },
+                "rate_limit_per_day": {
+                    "type": "integer"
+                },
+                "rate_limit_per_hour": {
+                    "type": "integer"
+                },
+                "rate_limit_per_minute": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "description": "Will be converted to JSON",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "vendor_name": {
+                    "description": "Vendor filter – mutually exclusive with IsSuperToken\nVendorName is a human-readable label (e.g. \"AVT\").\nFilterColumn is the logical key used to build the WHERE clause (e.g. \"flm_name\").\nFilterValue is the value to match (e.g. \"AVT\").\nSet IsSuperToken = true to grant unrestricted read/write across all vendors.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateTokenResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "token": {
+                    "$ref": "#/definitions/models.APIToken"
+                },
+                "warning": {
+                    "description": "\"Save this token - it won't be shown again\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CriticalTerminal": {
+            "type": "object",
+            "properties": {
+                "duration_minutes": {
+                    "type": "number",
+                    "example": 758.8
+                },
+                "flm": {
+                    "type": "string",
+                    "example": "AVT - CIDENG"
+                },
+                "gps": {
+                    "type": "string",
+                    "example": "-6.200000,106.816666"
+                },
+                "location": {
+                    "type": "string",
+                    "example": "DKI Jakarta - Jakarta Pusat"
+                },
+                "matched_rules": {
+                    "description": "Names of the criticality rules that matched",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "High priority off-line"
+                    ]
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "problem": {
+                    "type": "string",
+                    "example": "Card reader error"
+                },
+                "slm": {
+                    "type": "string",
+                    "example": "KGP - WINCOR DW"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "Off-line"
+                },
+                "terminal_id": {
+                    "type": "string",
+                    "example": "ATM-001"
+                },
+                "terminal_name": {
+                    "type": "string",
+                    "example": "Main Branch ATM"
+                },
+                "ticket_status": {
+                    "type": "string",
+                    "example": "0.NEW"
+                }
+            }
+        },
+        "models.CriticalTerminalsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CriticalTerminal"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Critical terminals retrieved successfully"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "models.CriticalityRule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_balance": {
+                    "type": "integer",
+                    "example": 500000
+                },
+                "min_tickets_duration": {
+                    "type": "number",
+                    "example": 240
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "Off-line"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "Conditions",
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "region": {
+                    "type": "string",
+                    "example": "BANDUNG"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "0.NEW"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CriticalityRuleListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CriticalityRule"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CriticalityRuleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "max_balance": {
+                    "type": "integer"
+                },
+                "min_tickets_duration": {
+                    "type": "number"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 3
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CriticalityRuleResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.CriticalityRule"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DataListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DataRow"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "Set on errors",
+                    "type": "string"
+                },
+                "search": {
+                    "type": "string"
+                },
+                "sla_breached": {
+                    "type": "boolean"
+                },
+                "sort_by": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DataResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.DataRow"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "Set on errors",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DataRow": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "integer",
+                    "example": 1000000
+                },
+                "close_time": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T18:00:00+07:00"
+                },
+                "condition": {
+                    "type": "string",
+                    "example": "Critical"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "current_problem": {
+                    "type": "string",
+                    "example": "Card reader error"
+                },
+                "dsp_flm": {
+                    "type": "string",
+                    "example": "FLM-001"
+                },
+                "dsp_slm": {
+                    "type": "string",
+                    "example": "SLM-001"
+                },
+                "export_name": {
+                    "type": "string",
+                    "example": "ATM_Report_Jan2024"
+                },
+                "flm": {
+                    "description": "mm.[FLM]",
+                    "type": "string",
+                    "example": "AVT - BANDUNG"
+                },
+                "flm_name": {
+                    "description": "── Machine dimension fields (LEFT JOIN machine_master.dbo.machine) ──",
+                    "type": "string",
+                    "example": "AVT"
+                },
+                "incident_start_datetime": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T10:30:00+07:00"
+                },
+                "initial_problem": {
+                    "type": "string",
+                    "example": "Cash dispenser jam"
+                },
+                "last_withdrawal": {
+                    "type": "string",
+                    "example": "2024-01-15T09:30:00Z"
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "Off-line"
+                },
+                "mode_history": {
+                    "type": "string",
+                    "example": "Online-\u003eOffline-\u003eOnline"
+                },
+                "net": {
+                    "description": "mm.[Net]",
+                    "type": "string",
+                    "example": "NOSAIRIS"
+                },
+                "open_time": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T08:00:00+07:00"
+                },
+                "p_duration": {
+                    "type": "string",
+                    "example": "2h 30m"
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "problem_history": {
+                    "type": "string",
+                    "example": "Card reader issue resolved"
+                },
+                "remarks": {
+                    "type": "string",
+                    "example": "Waiting for technician"
+                },
+                "sla_breached": {
+                    "description": "closed after, or still open past, sla_due_at",
+                    "type": "boolean",
+                    "example": false
+                },
+                "sla_due_at": {
+                    "description": "resolution deadline",
+                    "type": "string",
+                    "example": "2024-01-15T14:30:00+07:00"
+                },
+                "sla_policy": {
+                    "description": "── SLA fields (computed from the matching SLA policy; null when none applies) ──",
+                    "type": "string",
+                    "example": "High priority"
+                },
+                "sla_remaining_minutes": {
+                    "description": "minutes until sla_due_at, negative once breached",
+                    "type": "integer",
+                    "example": 95
+                },
+                "sla_response_breached": {
+                    "description": "still 0.NEW past sla_response_due_at",
+                    "type": "boolean",
+                    "example": false
+                },
+                "sla_response_due_at": {
+                    "type": "string",
+                    "example": "2024-01-15T11:30:00+07:00"
+                },
+                "slm": {
+                    "description": "mm.[SLM]",
+                    "type": "string",
+                    "example": "KGP - WINCOR DW"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "0.NEW"
+                },
+                "terminal_id": {
+                    "description": "── Ticket fields ────────────────────────────────────────────",
+                    "type": "string",
+                    "example": "ATM-001"
+                },
+                "terminal_name": {
+                    "type": "string",
+                    "example": "Main Branch ATM"
+                },
+                "tickets_duration": {
+                    "type": "number",
+                    "example": 150.5
+                },
+                "tickets_no": {
+                    "type": "string",
+                    "example": "TKT-2024-001"
+                }
+            }
+        },
+        "models.DataUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "close_time": {
+                    "description": "RFC 3339 or any accepted ticket time format",
+                    "type": "string",
+                    "example": "2024-01-15T18:00:00+07:00"
+                },
+                "condition": {
+                    "type": "string",
+                    "example": "Normal"
+                },
+                "current_problem": {
+                    "type": "string",
+                    "example": "Card reader fixed"
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "Off-line"
+                },
+                "mode_history": {
+                    "type": "string",
+                    "example": "Online-\u003eOffline-\u003eOnline"
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "problem_history": {
+                    "type": "string",
+                    "example": "Card reader issue resolved"
+                },
+                "remarks": {
+                    "type": "string",
+                    "example": "Technician dispatched"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "2.Kirim FLM"
+                }
+            }
+        },
+        "models.DependencyHealth": {
+            "type": "object",
+            "properties": {
+                "critical": {
+                    "description": "down fails readiness; otherwise it only degrades it",
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "last_checked_at": {
+                    "type": "string"
+                },
+                "last_success_at": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number",
+                    "example": 3.2
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ticket_master"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "models.Envelope": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "meta": {
+                    "$ref": "#/definitions/models.EnvelopeMeta"
+                }
+            }
+        },
+        "models.EnvelopeMeta": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/models.Pagination"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Optional detailed error",
+                    "type": "string",
+                    "example": "detailed error information"
+                },
+                "message": {
+                    "description": "Error message",
+                    "type": "string",
+                    "example": "Error message describing what went wrong"
+                },
+                "request_id": {
+                    "description": "X-Request-ID of the failed request",
+                    "type": "string",
+                    "example": "3f1c9a4e-7b2d-4e8a-9c61-0d5f2b7e8a13"
+                },
+                "success": {
+                    "description": "Always false for errors",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.FLMTicketsGroup": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "type": "string",
+                    "example": "BANDUNG"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "flm": {
+                    "type": "string",
+                    "example": "AVT - BANDUNG"
+                },
+                "tickets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OpenTicket"
+                    }
+                }
+            }
+        },
+        "models.FieldMetadata": {
+            "type": "object",
+            "properties": {
+                "last_updated": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "modes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModeInfo"
+                    }
+                },
+                "priorities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriorityInfo"
+                    }
+                },
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatusInfo"
+                    }
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "username": {
+                    "type": "string",
+                    "minLength": 3
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "session_token": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.AdminUser"
+                }
+            }
+        },
+        "models.MetadataResponse": {
+            "type": "object",
+            "properties": {
+                "last_updated": {
+                    "description": "When metadata was last refreshed",
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "message": {
+                    "description": "Response message",
+                    "type": "string",
+                    "example": "Metadata retrieved successfully"
+                },
+                "modes": {
+                    "description": "Available mode values from database",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModeInfo"
+                    }
+                },
+                "priorities": {
+                    "description": "Available priority values from database",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriorityInfo"
+                    }
+                },
+                "statuses": {
+                    "description": "Available status values from database",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatusInfo"
+                    }
+                },
+                "success": {
+                    "description": "Operation success status",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.ModeInfo": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Mode code from database",
+                    "type": "string",
+                    "example": "Off-line"
+                },
+                "description": {
+                    "description": "Human-readable description",
+                    "type": "string",
+                    "example": "Terminal is offline"
+                },
+                "is_documented": {
+                    "description": "Whether this value has documentation",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.OpenTicket": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance amount",
+                    "type": "integer",
+                    "example": 1000000
+                },
+                "close_time": {
+                    "description": "Ticket close time (nullable)",
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T18:00:00+07:00"
+                },
+                "condition": {
+                    "description": "Condition status (nullable)",
+                    "type": "string",
+                    "example": "Critical"
+                },
+                "count": {
+                    "description": "Count value",
+                    "type": "integer",
+                    "example": 5
+                },
+                "current_problem": {
+                    "description": "Current problem description (nullable)",
+                    "type": "string",
+                    "example": "Card reader error"
+                },
+                "dsp_flm": {
+                    "description": "DSP FLM identifier (nullable)",
+                    "type": "string",
+                    "example": "FLM-001"
+                },
+                "dsp_slm": {
+                    "description": "DSP SLM identifier (nullable)",
+                    "type": "string",
+                    "example": "SLM-001"
+                },
+                "export_name": {
+                    "description": "Export name for reports (nullable)",
+                    "type": "string",
+                    "example": "ATM_Report_Jan2024"
+                },
+                "incident_start_datetime": {
+                    "description": "Incident start timestamp (nullable)",
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T10:30:00+07:00"
+                },
+                "initial_problem": {
+                    "description": "Initial problem description (nullable)",
+                    "type": "string",
+                    "example": "Cash dispenser jam"
+                },
+                "last_withdrawal": {
+                    "description": "Last withdrawal timestamp (nullable)",
+                    "type": "string",
+                    "example": "2024-01-15T09:30:00Z"
+                },
+                "mode": {
+                    "description": "Terminal mode (nullable): Closed, In Service, nan, Off-line, Supervisor",
+                    "type": "string",
+                    "example": "Off-line"
+                },
+                "mode_history": {
+                    "description": "Mode change history (nullable)",
+                    "type": "string",
+                    "example": "Online-\u003eOffline-\u003eOnline"
+                },
+                "open_time": {
+                    "description": "Ticket open time (nullable)",
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T08:00:00+07:00"
+                },
+                "p_duration": {
+                    "description": "Problem duration (nullable)",
+                    "type": "string",
+                    "example": "2h 30m"
+                },
+                "priority": {
+                    "description": "Priority level (nullable): 1.High, 2.Middle, 3.Low, 4.Minimum",
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "problem_history": {
+                    "description": "Problem history (nullable)",
+                    "type": "string",
+                    "example": "Card reader issue resolved"
+                },
+                "remarks": {
+                    "description": "Remarks/notes (nullable)",
+                    "type": "string",
+                    "example": "Waiting for technician"
+                },
+                "status": {
+                    "description": "Ticket status (nullable): 0.NEW, 1.Req FD ke HD, 2.Kirim FLM, etc.",
+                    "type": "string",
+                    "example": "0.NEW"
+                },
+                "terminal_id": {
+                    "description": "Terminal identifier",
+                    "type": "string",
+                    "example": "ATM-001"
+                },
+                "terminal_name": {
+                    "description": "Terminal name",
+                    "type": "string",
+                    "example": "Main Branch ATM"
+                },
+                "tickets_duration": {
+                    "description": "Ticket duration in minutes (float)",
+                    "type": "number",
+                    "example": 150.5
+                },
+                "tickets_no": {
+                    "description": "Ticket number (nullable)",
+                    "type": "string",
+                    "example": "TKT-2024-001"
+                }
+            }
+        },
+        "models.Pagination": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "total": {
+                    "description": "Rows matching the filters across all pages",
+                    "type": "integer",
+                    "example": 312
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "models.PriorityInfo": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Priority code from database",
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "description": {
+                    "description": "Human-readable description",
+                    "type": "string",
+                    "example": "High priority"
+                },
+                "is_documented": {
+                    "description": "Whether this value has documentation",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "not_found"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "not found"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/v2/data/ATM-001"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "3f1c9a4e-7b2d-4e8a-9c61-0d5f2b7e8a13"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "description": "HTTP status text",
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "models.ReadinessReport": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DependencyHealth"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ready"
+                }
+            }
+        },
+        "models.SLACalendar": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "holidays": {
+                    "description": "stored as JSON array",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2026-12-25"
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Office hours (WIB)"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Jakarta"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "work_days": {
+                    "description": "0 = Sunday ... 6 = Saturday",
+                    "type": "string",
+                    "example": "1,2,3,4,5"
+                },
+                "work_end": {
+                    "type": "string",
+                    "example": "17:00"
+                },
+                "work_start": {
+                    "type": "string",
+                    "example": "08:00"
+                }
+            }
+        },
+        "models.SLACalendarRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "timezone",
+                "work_days",
+                "work_end",
+                "work_start"
+            ],
+            "properties": {
+                "holidays": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "2026-12-25"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 3
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Jakarta"
+                },
+                "work_days": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3,
+                        4,
+                        5
+                    ]
+                },
+                "work_end": {
+                    "type": "string",
+                    "example": "17:00"
+                },
+                "work_start": {
+                    "type": "string",
+                    "example": "08:00"
+                }
+            }
+        },
+        "models.SLACalendarResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.SLACalendar"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SLAPolicy": {
+            "type": "object",
+            "properties": {
+                "calendar_id": {
+                    "description": "nil = 24x7",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "High priority"
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "resolution_minutes": {
+                    "type": "integer",
+                    "example": 240
+                },
+                "response_minutes": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "vendor_name": {
+                    "type": "string",
+                    "example": "AVT"
+                }
+            }
+        },
+        "models.SLAPolicyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "priority",
+                "resolution_minutes"
+            ],
+            "properties": {
+                "calendar_id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 3
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "resolution_minutes": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 240
+                },
+                "response_minutes": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 60
+                },
+                "vendor_name": {
+                    "type": "string",
+                    "example": "AVT"
+                }
+            }
+        },
+        "models.SLAPolicyResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.SLAPolicy"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.StatusInfo": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Status code from database",
+                    "type": "string",
+                    "example": "0.NEW"
+                },
+                "description": {
+                    "description": "Human-readable description",
+                    "type": "string",
+                    "example": "New ticket"
+                },
+                "is_documented": {
+                    "description": "Whether this value has documentation",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.TicketsByFLMResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FLMTicketsGroup"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Tickets by FLM retrieved successfully"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "total": {
+                    "description": "Number of FLM groups",
+                    "type": "integer",
+                    "example": 58
+                },
+                "total_tickets": {
+                    "description": "Number of tickets across all groups",
+                    "type": "integer",
+                    "example": 312
+                }
+            }
+        },
+        "models.TokenListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.APIToken"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.APIToken"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.UpdateTokenRequest": {
+            "type": "object",
+            "properties": {
+                "allowed_origins": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "client_cert_fingerprint": {
+                    "description": "Client certificate binding – set to \"\" to remove",
+                    "type": "string"
+                },
+                "client_cert_subject": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "filter_column": {
+                    "type": "string"
+                },
+                "filter_value": {
+                    "type": "string"
+                },
+                "ip_whitelist": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "is_super_token": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rate_limit_per_day": {
+                    "type": "integer"
+                },
+                "rate_limit_per_hour": {
+                    "type": "integer"
+                },
+                "rate_limit_per_minute": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "vendor_name": {
+                    "description": "Vendor filter fields – all optional, only updated when non-empty / explicitly set",
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "ticket.updated"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "subscription_id": {
+                    "type": "integer"
+                },
+                "terminal_id": {
+                    "type": "string",
+                    "example": "ATM-001"
+                }
+            }
+        },
+        "models.WebhookDeliveryListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookDelivery"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WebhookSubscription": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "integer"
+                },
+                "event_types": {
+                    "description": "empty = all events",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ticket.updated",
+                        "ticket.closed"
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "token_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://vendor.example.com/hooks/tickets"
+                }
+            }
+        },
+        "models.WebhookSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "ticket.updated",
+                        "ticket.closed"
+                    ]
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "secret": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 16
+                },
+                "url": {
+                    "type": "string",
+                    "maxLength": 2000,
+                    "example": "https://vendor.example.com/hooks/tickets"
+                }
+            }
+        },
+        "models.WebhookSubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.WebhookSubscription"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API token for authentication. Send in the X-API-Token header. Required for all /api/v1/data endpoints.",
+            "type": "apiKey",
+            "name": "X-API-Token",
+            "in": "header"
+        }
+    }
+}`
+
+// SwaggerInfo holds exported Swagger Info so clients can modify it
+var SwaggerInfo = &swag.Spec{
+	Version:          "1.0",
+	Host:             "",
+	BasePath:         "/",
+	Schemes:          []string{},
+	Title:            "API Gateway for On-Premise to Cloud Communication",
+	Description:      "This API Gateway provides a single unified endpoint /api/v1/data that joins ticket_master.dbo.open_ticket with machine_master.dbo.machine and applies vendor-scoped access control from the API token.\nAdmin endpoints (/admin/...) use the session from /admin/auth/login, sent as the session_token cookie or the X-Session-Token header.",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
+}
@@ -0,0 +1,1957 @@
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
+package docs
+
+import "github.com/swaggo/swag"
+
+const docTemplatepublic = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {
+            "name": "API Support",
+            "email": "support@example.com"
+        },
+        "license": {
+            "name": "Proprietary"
+        },
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/api/v1/data": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve joined ticket+machine rows with pagination, sorting, and filtering. Vendor-scoped tokens only see rows matching their filter. Admin/Internal tokens see all rows.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get all data",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number (default: all results)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Items per page (default: 100, max: 500)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: terminal_id, terminal_name, priority, mode, status, incident_start_datetime, count, balance, tickets_duration, open_time, close_time, flm_name, flm, slm, net",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort direction: asc or desc (default: desc)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by terminal_id or terminal_name (partial match)",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact status value (e.g. 0.NEW)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact mode value (e.g. Off-line)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact priority value (e.g. 1.High)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by computed SLA breach state (true or false)",
+                        "name": "sla_breached",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Data retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.DataListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid sla_breached value",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/data/by-flm": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve open tickets grouped by machine FLM branch with the service area and per-group counts. Groups are sorted by count (largest first). Vendor-scoped tokens only see rows matching their filter.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get tickets grouped by FLM",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by exact status value (e.g. 0.NEW)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact priority value (e.g. 1.High)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Tickets by FLM retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.TicketsByFLMResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/data/metadata": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve all valid values for status, mode, and priority fields. Cached for 1 hour.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get field metadata",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "Metadata retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.MetadataResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/data/stream": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events feed of created, updated and closed tickets. Each event's data is a ChangeEvent; vendor-scoped tokens only receive events for rows inside their filter. Reconnect with the Last-Event-ID header (or last_event_id query parameter) to resume; a \"reset\" event means the missed events are no longer available and the client should refetch GET /data.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Stream ticket changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Same as the Last-Event-ID header, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/data/{terminal_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve a single joined row by terminal ID. Vendor tokens return 404 if the terminal is outside their scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get data by terminal ID",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Terminal ID",
+                        "name": "terminal_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Data retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.DataResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update ticket fields for a terminal. Vendor tokens can only update terminals within their scope (returns 403 otherwise). Admin/Internal tokens can update any terminal.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Update ticket fields",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Terminal ID",
+                        "name": "terminal_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DataUpdateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key per intended update; a retry with the same key and body replays the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/models.DataResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Outside vendor scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A request with this Idempotency-Key is still in progress",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key reused with a different request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "Database query timed out",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/graphql": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Read-only GraphQL API over open tickets (DataRow), machine master records, metadata and aggregated stats. Vendor-scoped tokens only see rows inside their filter. Queries deeper than GRAPHQL_MAX_DEPTH, or that could return more than GRAPHQL_MAX_ROWS records, are rejected before they run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "GraphQL query",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Query document (GET)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON-encoded variables (GET)",
+                        "name": "variables",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operation to run when the document has several (GET)",
+                        "name": "operationName",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data and, when a field failed, errors",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Syntax, validation or query cost error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/data": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Joined ticket+machine rows, one page at a time. Same filters and sorting as v1 GET /data, but results are always paginated and the filters are not echoed back.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "List data",
+                "parameters": [
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Page number (default: 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 500,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "Items per page (default: 100, max: 500)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field, as on v1",
+                        "name": "sort_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort direction: asc or desc (default: desc)",
+                        "name": "sort_order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by terminal_id or terminal_name (partial match)",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact status value (e.g. 0.NEW)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact mode value (e.g. Off-line)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact priority value (e.g. 1.High)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Filter by computed SLA breach state (true or false)",
+                        "name": "sla_breached",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of rows",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.DataRow"
+                                            }
+                                        },
+                                        "meta": {
+                                            "$ref": "#/definitions/models.EnvelopeMeta"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid_request",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "missing_token, invalid_token, token_revoked, token_expired or token_disabled",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "429": {
+                        "description": "rate_limited",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal_error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/data/by-flm": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Open tickets grouped by machine FLM branch, largest group first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get tickets grouped by FLM",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by exact status value (e.g. 0.NEW)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by exact priority value (e.g. 1.High)",
+                        "name": "priority",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Groups",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.FLMTicketsGroup"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "internal_error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/data/metadata": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "All valid values for the status, mode and priority fields. Cached for 1 hour.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get field metadata",
+                "responses": {
+                    "200": {
+                        "description": "Metadata",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.FieldMetadata"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "internal_error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/data/stream": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Server-Sent Events feed of created, updated and closed tickets. Each event's data is a ChangeEvent; vendor-scoped tokens only receive events for rows inside their filter. Reconnect with the Last-Event-ID header (or last_event_id query parameter) to resume; a \"reset\" event means the missed events are no longer available and the client should refetch GET /data.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Stream ticket changes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Same as the Last-Event-ID header, for clients that cannot set headers",
+                        "name": "last_event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeEvent"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/data/{terminal_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "A single joined row. Terminals outside a vendor token's scope are reported as not_found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Get data by terminal ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Terminal ID",
+                        "name": "terminal_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Row",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DataRow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "not_found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update ticket fields for a terminal and return the updated row. Vendor tokens get out_of_scope (403) for terminals outside their scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "Update ticket fields",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Terminal ID",
+                        "name": "terminal_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DataUpdateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Unique key per intended update; a retry with the same key and body replays the stored response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339",
+                        "name": "legacy_time_format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Updated row",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.Envelope"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.DataRow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "invalid_request or no_fields_to_update",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "out_of_scope",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "not_found",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "idempotency_in_progress",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "idempotency_key_reused",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "internal_error",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout",
+                        "schema": {
+                            "$ref": "#/definitions/models.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/graphql": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Read-only GraphQL API over open tickets (DataRow), machine master records, metadata and aggregated stats. Vendor-scoped tokens only see rows inside their filter. Queries deeper than GRAPHQL_MAX_DEPTH, or that could return more than GRAPHQL_MAX_ROWS records, are rejected before they run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Data"
+                ],
+                "summary": "GraphQL query",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Query document (GET)",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON-encoded variables (GET)",
+                        "name": "variables",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operation to run when the document has several (GET)",
+                        "name": "operationName",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data and, when a field failed, errors",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Syntax, validation or query cost error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API token",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check the health status of the API and database connections. Same verdict as /readyz in the original healthy/degraded/unhealthy format, with the per-dependency detail under dependencies.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "API is healthy or degraded",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/livez": {
+            "get": {
+                "description": "Answers 200 while the process can serve requests. It never touches a dependency, so a hung database cannot fail it; use it for container health checks and restart policies.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "alive",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/ping": {
+            "get": {
+                "description": "Simple ping endpoint to check if the API is running",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Ping",
+                "responses": {
+                    "200": {
+                        "description": "pong",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Reports the cached result of the background dependency prober: per dependency its status, latency of the last ping and last success time. 503 when a critical dependency is down (status not_ready); 200 with status degraded when only non-critical ones are. Never waits on a database.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "ready or degraded",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReadinessReport"
+                        }
+                    },
+                    "503": {
+                        "description": "not_ready",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReadinessReport"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.ChangeEvent": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "description": "row after the change (last known row for removals)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DataRow"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1042
+                },
+                "occurred_at": {
+                    "description": "when the change was detected",
+                    "type": "string",
+                    "example": "2024-01-15T10:35:00+07:00"
+                },
+                "terminal_id": {
+                    "type": "string",
+                    "example": "ATM-001"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "updated"
+                }
+            }
+        },
+        "models.DataListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DataRow"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "Set on errors",
+                    "type": "string"
+                },
+                "search": {
+                    "type": "string"
+                },
+                "sla_breached": {
+                    "type": "boolean"
+                },
+                "sort_by": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DataResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.DataRow"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "Set on errors",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DataRow": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "integer",
+                    "example": 1000000
+                },
+                "close_time": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T18:00:00+07:00"
+                },
+                "condition": {
+                    "type": "string",
+                    "example": "Critical"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "current_problem": {
+                    "type": "string",
+                    "example": "Card reader error"
+                },
+                "dsp_flm": {
+                    "type": "string",
+                    "example": "FLM-001"
+                },
+                "dsp_slm": {
+                    "type": "string",
+                    "example": "SLM-001"
+                },
+                "export_name": {
+                    "type": "string",
+                    "example": "ATM_Report_Jan2024"
+                },
+                "flm": {
+                    "description": "mm.[FLM]",
+                    "type": "string",
+                    "example": "AVT - BANDUNG"
+                },
+                "flm_name": {
+                    "description": "── Machine dimension fields (LEFT JOIN machine_master.dbo.machine) ──",
+                    "type": "string",
+                    "example": "AVT"
+                },
+                "incident_start_datetime": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T10:30:00+07:00"
+                },
+                "initial_problem": {
+                    "type": "string",
+                    "example": "Cash dispenser jam"
+                },
+                "last_withdrawal": {
+                    "type": "string",
+                    "example": "2024-01-15T09:30:00Z"
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "Off-line"
+                },
+                "mode_history": {
+                    "type": "string",
+                    "example": "Online-\u003eOffline-\u003eOnline"
+                },
+                "net": {
+                    "description": "mm.[Net]",
+                    "type": "string",
+                    "example": "NOSAIRIS"
+                },
+                "open_time": {
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T08:00:00+07:00"
+                },
+                "p_duration": {
+                    "type": "string",
+                    "example": "2h 30m"
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "problem_history": {
+                    "type": "string",
+                    "example": "Card reader issue resolved"
+                },
+                "remarks": {
+                    "type": "string",
+                    "example": "Waiting for technician"
+                },
+                "sla_breached": {
+                    "description": "closed after, or still open past, sla_due_at",
+                    "type": "boolean",
+                    "example": false
+                },
+                "sla_due_at": {
+                    "description": "resolution deadline",
+                    "type": "string",
+                    "example": "2024-01-15T14:30:00+07:00"
+                },
+                "sla_policy": {
+                    "description": "── SLA fields (computed from the matching SLA policy; null when none applies) ──",
+                    "type": "string",
+                    "example": "High priority"
+                },
+                "sla_remaining_minutes": {
+                    "description": "minutes until sla_due_at, negative once breached",
+                    "type": "integer",
+                    "example": 95
+                },
+                "sla_response_breached": {
+                    "description": "still 0.NEW past sla_response_due_at",
+                    "type": "boolean",
+                    "example": false
+                },
+                "sla_response_due_at": {
+                    "type": "string",
+                    "example": "2024-01-15T11:30:00+07:00"
+                },
+                "slm": {
+                    "description": "mm.[SLM]",
+                    "type": "string",
+                    "example": "KGP - WINCOR DW"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "0.NEW"
+                },
+                "terminal_id": {
+                    "description": "── Ticket fields ────────────────────────────────────────────",
+                    "type": "string",
+                    "example": "ATM-001"
+                },
+                "terminal_name": {
+                    "type": "string",
+                    "example": "Main Branch ATM"
+                },
+                "tickets_duration": {
+                    "type": "number",
+                    "example": 150.5
+                },
+                "tickets_no": {
+                    "type": "string",
+                    "example": "TKT-2024-001"
+                }
+            }
+        },
+        "models.DataUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "close_time": {
+                    "description": "RFC 3339 or any accepted ticket time format",
+                    "type": "string",
+                    "example": "2024-01-15T18:00:00+07:00"
+                },
+                "condition": {
+                    "type": "string",
+                    "example": "Normal"
+                },
+                "current_problem": {
+                    "type": "string",
+                    "example": "Card reader fixed"
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "Off-line"
+                },
+                "mode_history": {
+                    "type": "string",
+                    "example": "Online-\u003eOffline-\u003eOnline"
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "problem_history": {
+                    "type": "string",
+                    "example": "Card reader issue resolved"
+                },
+                "remarks": {
+                    "type": "string",
+                    "example": "Technician dispatched"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "2.Kirim FLM"
+                }
+            }
+        },
+        "models.DependencyHealth": {
+            "type": "object",
+            "properties": {
+                "critical": {
+                    "description": "down fails readiness; otherwise it only degrades it",
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "last_checked_at": {
+                    "type": "string"
+                },
+                "last_success_at": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number",
+                    "example": 3.2
+                },
+                "name": {
+                    "type": "string",
+                    "example": "ticket_master"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "models.Envelope": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "meta": {
+                    "$ref": "#/definitions/models.EnvelopeMeta"
+                }
+            }
+        },
+        "models.EnvelopeMeta": {
+            "type": "object",
+            "properties": {
+                "pagination": {
+                    "$ref": "#/definitions/models.Pagination"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Optional detailed error",
+                    "type": "string",
+                    "example": "detailed error information"
+                },
+                "message": {
+                    "description": "Error message",
+                    "type": "string",
+                    "example": "Error message describing what went wrong"
+                },
+                "request_id": {
+                    "description": "X-Request-ID of the failed request",
+                    "type": "string",
+                    "example": "3f1c9a4e-7b2d-4e8a-9c61-0d5f2b7e8a13"
+                },
+                "success": {
+                    "description": "Always false for errors",
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "models.FLMTicketsGroup": {
+            "type": "object",
+            "properties": {
+                "area": {
+                    "type": "string",
+                    "example": "BANDUNG"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "flm": {
+                    "type": "string",
+                    "example": "AVT - BANDUNG"
+                },
+                "tickets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OpenTicket"
+                    }
+                }
+            }
+        },
+        "models.FieldMetadata": {
+            "type": "object",
+            "properties": {
+                "last_updated": {
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "modes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModeInfo"
+                    }
+                },
+                "priorities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriorityInfo"
+                    }
+                },
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatusInfo"
+                    }
+                }
+            }
+        },
+        "models.MetadataResponse": {
+            "type": "object",
+            "properties": {
+                "last_updated": {
+                    "description": "When metadata was last refreshed",
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "message": {
+                    "description": "Response message",
+                    "type": "string",
+                    "example": "Metadata retrieved successfully"
+                },
+                "modes": {
+                    "description": "Available mode values from database",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ModeInfo"
+                    }
+                },
+                "priorities": {
+                    "description": "Available priority values from database",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriorityInfo"
+                    }
+                },
+                "statuses": {
+                    "description": "Available status values from database",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatusInfo"
+                    }
+                },
+                "success": {
+                    "description": "Operation success status",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.ModeInfo": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Mode code from database",
+                    "type": "string",
+                    "example": "Off-line"
+                },
+                "description": {
+                    "description": "Human-readable description",
+                    "type": "string",
+                    "example": "Terminal is offline"
+                },
+                "is_documented": {
+                    "description": "Whether this value has documentation",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.OpenTicket": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "Balance amount",
+                    "type": "integer",
+                    "example": 1000000
+                },
+                "close_time": {
+                    "description": "Ticket close time (nullable)",
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T18:00:00+07:00"
+                },
+                "condition": {
+                    "description": "Condition status (nullable)",
+                    "type": "string",
+                    "example": "Critical"
+                },
+                "count": {
+                    "description": "Count value",
+                    "type": "integer",
+                    "example": 5
+                },
+                "current_problem": {
+                    "description": "Current problem description (nullable)",
+                    "type": "string",
+                    "example": "Card reader error"
+                },
+                "dsp_flm": {
+                    "description": "DSP FLM identifier (nullable)",
+                    "type": "string",
+                    "example": "FLM-001"
+                },
+                "dsp_slm": {
+                    "description": "DSP SLM identifier (nullable)",
+                    "type": "string",
+                    "example": "SLM-001"
+                },
+                "export_name": {
+                    "description": "Export name for reports (nullable)",
+                    "type": "string",
+                    "example": "ATM_Report_Jan2024"
+                },
+                "incident_start_datetime": {
+                    "description": "Incident start timestamp (nullable)",
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T10:30:00+07:00"
+                },
+                "initial_problem": {
+                    "description": "Initial problem description (nullable)",
+                    "type": "string",
+                    "example": "Cash dispenser jam"
+                },
+                "last_withdrawal": {
+                    "description": "Last withdrawal timestamp (nullable)",
+                    "type": "string",
+                    "example": "2024-01-15T09:30:00Z"
+                },
+                "mode": {
+                    "description": "Terminal mode (nullable): Closed, In Service, nan, Off-line, Supervisor",
+                    "type": "string",
+                    "example": "Off-line"
+                },
+                "mode_history": {
+                    "description": "Mode change history (nullable)",
+                    "type": "string",
+                    "example": "Online-\u003eOffline-\u003eOnline"
+                },
+                "open_time": {
+                    "description": "Ticket open time (nullable)",
+                    "type": "string",
+                    "format": "date-time",
+                    "example": "2024-01-15T08:00:00+07:00"
+                },
+                "p_duration": {
+                    "description": "Problem duration (nullable)",
+                    "type": "string",
+                    "example": "2h 30m"
+                },
+                "priority": {
+                    "description": "Priority level (nullable): 1.High, 2.Middle, 3.Low, 4.Minimum",
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "problem_history": {
+                    "description": "Problem history (nullable)",
+                    "type": "string",
+                    "example": "Card reader issue resolved"
+                },
+                "remarks": {
+                    "description": "Remarks/notes (nullable)",
+                    "type": "string",
+                    "example": "Waiting for technician"
+                },
+                "status": {
+                    "description": "Ticket status (nullable): 0.NEW, 1.Req FD ke HD, 2.Kirim FLM, etc.",
+                    "type": "string",
+                    "example": "0.NEW"
+                },
+                "terminal_id": {
+                    "description": "Terminal identifier",
+                    "type": "string",
+                    "example": "ATM-001"
+                },
+                "terminal_name": {
+                    "description": "Terminal name",
+                    "type": "string",
+                    "example": "Main Branch ATM"
+                },
+                "tickets_duration": {
+                    "description": "Ticket duration in minutes (float)",
+                    "type": "number",
+                    "example": 150.5
+                },
+                "tickets_no": {
+                    "description": "Ticket number (nullable)",
+                    "type": "string",
+                    "example": "TKT-2024-001"
+                }
+            }
+        },
+        "models.Pagination": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "page_size": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "total": {
+                    "description": "Rows matching the filters across all pages",
+                    "type": "integer",
+                    "example": 312
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "models.PriorityInfo": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Priority code from database",
+                    "type": "string",
+                    "example": "1.High"
+                },
+                "description": {
+                    "description": "Human-readable description",
+                    "type": "string",
+                    "example": "High priority"
+                },
+                "is_documented": {
+                    "description": "Whether this value has documentation",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "not_found"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "not found"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/v2/data/ATM-001"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "3f1c9a4e-7b2d-4e8a-9c61-0d5f2b7e8a13"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "description": "HTTP status text",
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "models.ReadinessReport": {
+            "type": "object",
+            "properties": {
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DependencyHealth"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ready"
+                }
+            }
+        },
+        "models.StatusInfo": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Status code from database",
+                    "type": "string",
+                    "example": "0.NEW"
+                },
+                "description": {
+                    "description": "Human-readable description",
+                    "type": "string",
+                    "example": "New ticket"
+                },
+                "is_documented": {
+                    "description": "Whether this value has documentation",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "models.TicketsByFLMResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FLMTicketsGroup"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Tickets by FLM retrieved successfully"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "total": {
+                    "description": "Number of FLM groups",
+                    "type": "integer",
+                    "example": 58
+                },
+                "total_tickets": {
+                    "description": "Number of tickets across all groups",
+                    "type": "integer",
+                    "example": 312
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "API token provided by the system administrator. Send in the X-API-Token request header.",
+            "type": "apiKey",
+            "name": "X-API-Token",
+            "in": "header"
+        }
+    }
+}`
+
+// SwaggerInfopublic holds exported Swagger Info so clients can modify it
+var SwaggerInfopublic = &swag.Spec{
+	Version:          "1.0",
+	Host:             "",
+	BasePath:         "/",
+	Schemes:          []string{},
+	Title:            "ATM Monitoring API",
+	Description:      "Public API for retrieving ATM terminal data. Authentication requires an X-API-Token issued by the system administrator.",
+	InfoInstanceName: "public",
+	SwaggerTemplate:  docTemplatepublic,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfopublic.InstanceName(), SwaggerInfopublic)
+}
@@ -0,0 +1,252 @@
+package grpcserver
+
+import (
+	"api-gateway/metrics"
+	"api-gateway/models"
+	"api-gateway/repository"
+	"api-gateway/service"
+	"context"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// tokenMetadataKey carries the API token, like the X-API-Token header on REST.
+const tokenMetadataKey = "x-api-token"
+
+// requestIDMetadataKey carries the request ID both ways, like X-Request-ID on REST.
+const requestIDMetadataKey = "x-request-id"
+
+type contextKey string
+
+const tokenContextKey contextKey = "api_token"
+
+// vendorFilterFromContext returns the vendor filter of the authenticated token:
+// a super-token filter, a scoped filter, or nil for unrestricted tokens.
+func vendorFilterFromContext(ctx context.Context) *repository.VendorFilter {
+	token, _ := ctx.Value(tokenContextKey).(*models.APIToken)
+	if token == nil {
+		return nil
+	}
+	return repository.ResolveVendorFilter(token.FilterColumn, token.FilterValue, token.IsSuperToken)
+}
+
+// authenticator applies the CombinedAuth checks to gRPC calls: token
+// validation (including the IP whitelist), rate limits and usage logging.
+type authenticator struct {
+	tokenService *service.TokenService
+}
+
+// authenticate validates the call's token and rate limits, returning a context
+// carrying the token. Calls rejected by the rate limiter are logged as usage,
+// as on the HTTP path, with their response time measured from startTime.
+func (a *authenticator) authenticate(ctx context.Context, fullMethod string, startTime time.Time) (context.Context, *models.APIToken, error) {
+	if a.tokenService == nil {
+		return nil, nil, status.Error(codes.Unavailable, "token service unavailable")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(tokenMetadataKey)
+	if len(values) == 0 || values[0] == "" {
+		metrics.AuthFailures.WithLabelValues(service.ErrorCode(service.ErrMissingToken)).Inc()
+		return nil, nil, status.Error(codes.Unauthenticated, "please provide the x-api-token metadata key")
+	}
+
+	token, err := a.tokenService.ValidateAPIToken(ctx, values[0], clientIP(ctx), clientCertificate(ctx))
+	if errors.Is(err, service.ErrQueryTimeout) {
+		return nil, nil, status.Error(codes.DeadlineExceeded, "token validation timed out")
+	}
+	if err != nil && service.ErrorStatus(err) >= http.StatusInternalServerError {
+		return nil, nil, status.Errorf(codes.Internal, "token validation failed: %v", err)
+	}
+	if err != nil {
+		metrics.AuthFailures.WithLabelValues(service.ErrorCode(err)).Inc()
+		return nil, nil, status.Errorf(codes.Unauthenticated, "invalid API token: %v", err)
+	}
+
+	rateLimits := map[string]int{
+		"minute": token.RateLimitPerMinute,
+		"hour":   token.RateLimitPerHour,
+		"day":    token.RateLimitPerDay,
+	}
+	allowed, message, err := a.tokenService.CheckRateLimit(ctx, token.ID, rateLimits)
+	if err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "rate limit check failed: %v", err)
+	}
+	if !allowed {
+		err := status.Error(codes.ResourceExhausted, message)
+		a.logUsage(ctx, token.ID, fullMethod, startTime, err)
+		return nil, nil, err
+	}
+
+	return context.WithValue(ctx, tokenContextKey, token), token, nil
+}
+
+// withRequestID accepts the caller's x-request-id (or generates one), stores
+// it in the context and returns it in the response header metadata.
+func withRequestID(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	requestID := service.NewRequestID(firstValue(md, requestIDMetadataKey))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, requestID))
+	return service.WithRequestID(ctx, requestID)
+}
+
+// unary authenticates unary calls and logs their usage.
+func (a *authenticator) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	startTime := time.Now()
+	ctx, token, err := a.authenticate(withRequestID(ctx), info.FullMethod, startTime)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := handler(ctx, req)
+	a.logUsage(ctx, token.ID, info.FullMethod, startTime, err)
+	return resp, err
+}
+
+// authenticatedStream overrides the context of a server stream.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// stream authenticates streaming calls and logs their usage when they end.
+func (a *authenticator) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	startTime := time.Now()
+	ctx, token, err := a.authenticate(withRequestID(ss.Context()), info.FullMethod, startTime)
+	if err != nil {
+		return err
+	}
+
+	err = handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	a.logUsage(ctx, token.ID, info.FullMethod, startTime, err)
+	return err
+}
+
+// logUsage records the call in token_usage_logs the way the HTTP middleware
+// does. The method is logged as GRPC and the status as its HTTP equivalent,
+// so analytics treat both APIs alike.
+func (a *authenticator) logUsage(ctx context.Context, tokenID int, fullMethod string, startTime time.Time, callErr error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	errorMsg := ""
+	if callErr != nil {
+		errorMsg = status.Convert(callErr).Message()
+	}
+
+	log := &models.TokenUsageLog{
+		TokenID:        tokenID,
+		Method:         "GRPC",
+		Endpoint:       fullMethod,
+		FullURL:        fullMethod,
+		StatusCode:     httpStatusFromCode(status.Code(callErr)),
+		ResponseTimeMs: int(time.Since(startTime).Milliseconds()),
+		IPAddress:      clientIP(ctx),
+		UserAgent:      firstValue(md, "user-agent"),
+		RequestID:      service.RequestIDFromContext(ctx),
+		ErrorMessage:   errorMsg,
+		ErrorCode:      errorCodeFromCode(status.Code(callErr)),
+	}
+
+	// Queued for the batch writer to avoid blocking the call
+	a.tokenService.LogTokenUsage(log)
+}
+
+// clientIP returns the peer's IP address without the port.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// clientCertificate returns the verified TLS client certificate of the call,
+// or nil when the connection is plain or no certificate was presented.
+func clientCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 {
+		return nil
+	}
+	return info.State.VerifiedChains[0][0]
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// errorCodeFromCode maps a gRPC status code to the stable error code the REST
+// API records for the same failure.
+func errorCodeFromCode(code codes.Code) string {
+	switch code {
+	case codes.OK, codes.Canceled:
+		return ""
+	case codes.InvalidArgument:
+		return models.ErrCodeInvalidRequest
+	case codes.Unauthenticated:
+		return models.ErrCodeInvalidToken
+	case codes.PermissionDenied:
+		return models.ErrCodeOutOfScope
+	case codes.NotFound:
+		return models.ErrCodeNotFound
+	case codes.ResourceExhausted:
+		return models.ErrCodeRateLimited
+	case codes.Unavailable:
+		return models.ErrCodeServiceUnavailable
+	case codes.DeadlineExceeded:
+		return models.ErrCodeQueryTimeout
+	default:
+		return models.ErrCodeInternal
+	}
+}
+
+// httpStatusFromCode maps a gRPC status code to the HTTP status the REST API
+// returns in the same situation.
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Canceled:
+		return 499 // client closed the stream
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
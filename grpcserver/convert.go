@@ -0,0 +1,108 @@
+package grpcserver
+
+import (
+	"api-gateway/models"
+	"api-gateway/proto/datapb"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// nullString converts a nullable column to a proto3 optional string.
+func nullString(ns models.NullString) *string {
+	if !ns.Valid {
+		return nil
+	}
+	s := ns.String
+	return &s
+}
+
+// ticketTimestamp converts a ticket timestamp, leaving it unset when the column
+// is NULL or unparseable.
+func ticketTimestamp(tt models.TicketTime) *timestamppb.Timestamp {
+	if !tt.Valid {
+		return nil
+	}
+	return timestamppb.New(tt.Time)
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+// dataRowToProto converts a DataRow to its wire message.
+func dataRowToProto(row *models.DataRow) *datapb.DataRow {
+	if row == nil {
+		return nil
+	}
+	msg := &datapb.DataRow{
+		TerminalId:            row.TerminalID,
+		TerminalName:          row.TerminalName,
+		Priority:              nullString(row.Priority),
+		Mode:                  nullString(row.Mode),
+		InitialProblem:        nullString(row.InitialProblem),
+		CurrentProblem:        nullString(row.CurrentProblem),
+		PDuration:             nullString(row.PDuration),
+		IncidentStartDatetime: ticketTimestamp(row.IncidentStartTime),
+		Count:                 int64(row.Count),
+		Status:                nullString(row.Status),
+		Remarks:               nullString(row.Remarks),
+		Balance:               int64(row.Balance),
+		Condition:             nullString(row.Condition),
+		TicketsNo:             nullString(row.TicketsNo),
+		TicketsDuration:       row.TicketsDuration,
+		OpenTime:              ticketTimestamp(row.OpenTime),
+		CloseTime:             ticketTimestamp(row.CloseTime),
+		ProblemHistory:        nullString(row.ProblemHistory),
+		ModeHistory:           nullString(row.ModeHistory),
+		DspFlm:                nullString(row.DSPFLM),
+		DspSlm:                nullString(row.DSPSLM),
+		ExportName:            nullString(row.ExportName),
+		FlmName:               nullString(row.FLMName),
+		Flm:                   nullString(row.FLM),
+		Slm:                   nullString(row.SLM),
+		Net:                   nullString(row.Net),
+		SlaPolicy:             row.SLAPolicy,
+		SlaDueAt:              timestamp(row.SLADueAt),
+		SlaBreached:           row.SLABreached,
+		SlaResponseDueAt:      timestamp(row.SLAResponseDueAt),
+		SlaResponseBreached:   row.SLAResponseBreached,
+	}
+	if row.LastWithdrawal.Valid {
+		msg.LastWithdrawal = timestamppb.New(row.LastWithdrawal.Time)
+	}
+	if row.SLARemainingMinutes != nil {
+		remaining := int64(*row.SLARemainingMinutes)
+		msg.SlaRemainingMinutes = &remaining
+	}
+	return msg
+}
+
+// changeEventToProto converts a stream change event to its wire message.
+func changeEventToProto(ev *models.ChangeEvent) *datapb.ChangeEvent {
+	return &datapb.ChangeEvent{
+		Id:         ev.ID,
+		Type:       ev.Type,
+		TerminalId: ev.TerminalID,
+		OccurredAt: timestamppb.New(ev.OccurredAt),
+		Data:       dataRowToProto(ev.Data),
+	}
+}
+
+// updateRequestFromProto converts an UpdateData request to the REST update body.
+func updateRequestFromProto(req *datapb.UpdateDataRequest) *models.DataUpdateRequest {
+	return &models.DataUpdateRequest{
+		Priority:       req.GetPriority(),
+		Mode:           req.GetMode(),
+		CurrentProblem: req.GetCurrentProblem(),
+		Status:         req.GetStatus(),
+		Remarks:        req.GetRemarks(),
+		Condition:      req.GetCondition(),
+		CloseTime:      req.GetCloseTime(),
+		ProblemHistory: req.GetProblemHistory(),
+		ModeHistory:    req.GetModeHistory(),
+	}
+}
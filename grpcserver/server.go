@@ -0,0 +1,168 @@
+// Package grpcserver serves the /api/v1/data operations over gRPC for
+// internal services (see proto/data.proto).
+package grpcserver
+
+import (
+	"api-gateway/models"
+	"api-gateway/proto/datapb"
+	"api-gateway/repository"
+	"api-gateway/service"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DataServer implements datapb.DataServiceServer on top of DataService and
+// the shared change stream.
+type DataServer struct {
+	datapb.UnimplementedDataServiceServer
+
+	dataService   *service.DataService
+	streamService *service.StreamService
+	logger        *logrus.Logger
+}
+
+// NewServer creates a gRPC server with the data service registered behind
+// token authentication. opts are passed on to grpc.NewServer, e.g. TLS
+// credentials.
+func NewServer(dataService *service.DataService, streamService *service.StreamService, tokenService *service.TokenService, logger *logrus.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	auth := &authenticator{tokenService: tokenService}
+	server := grpc.NewServer(append(opts,
+		grpc.ChainUnaryInterceptor(auth.unary),
+		grpc.ChainStreamInterceptor(auth.stream),
+	)...)
+	datapb.RegisterDataServiceServer(server, &DataServer{
+		dataService:   dataService,
+		streamService: streamService,
+		logger:        logger,
+	})
+	return server
+}
+
+// dataError maps a DataService error to a gRPC status, matching the HTTP codes
+// of the REST handlers.
+func (s *DataServer) dataError(ctx context.Context, err error, action string) error {
+	msg := err.Error()
+	switch {
+	case errors.Is(err, service.ErrOutOfScope):
+		return status.Error(codes.PermissionDenied, msg)
+	case errors.Is(err, service.ErrNotFound):
+		return status.Error(codes.NotFound, msg)
+	case errors.Is(err, service.ErrNoFieldsToUpdate) || errors.Is(err, service.ErrInvalidInput):
+		return status.Error(codes.InvalidArgument, msg)
+	case errors.Is(err, service.ErrQueryTimeout):
+		s.logger.WithContext(ctx).Warnf("Timed out trying to %s over gRPC: %v", action, err)
+		return status.Error(codes.DeadlineExceeded, "timed out trying to "+action)
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, msg)
+	default:
+		s.logger.WithContext(ctx).Errorf("Error trying to %s over gRPC: %v", action, err)
+		return status.Error(codes.Internal, "failed to "+action)
+	}
+}
+
+// ListData streams the rows matching the request, one message per row.
+func (s *DataServer) ListData(req *datapb.ListDataRequest, stream datapb.DataService_ListDataServer) error {
+	pageSize := int(req.GetPageSize())
+	if pageSize > 500 {
+		pageSize = 500
+	}
+	if pageSize < 1 {
+		pageSize = 100
+	}
+
+	sortBy := req.GetSortBy()
+	if sortBy == "" {
+		sortBy = "incident_start_datetime"
+	}
+	sortOrder := req.GetSortOrder()
+	if sortOrder != "asc" && sortOrder != "desc" {
+		sortOrder = "desc"
+	}
+
+	params := repository.QueryParams{
+		Page:      int(req.GetPage()),
+		PageSize:  pageSize,
+		SortBy:    sortBy,
+		SortOrder: sortOrder,
+		Search:    strings.TrimSpace(req.GetSearch()),
+		Status:    strings.TrimSpace(req.GetStatus()),
+		Mode:      strings.TrimSpace(req.GetMode()),
+		Priority:  strings.TrimSpace(req.GetPriority()),
+	}
+
+	rows, _, err := s.dataService.GetAll(stream.Context(), vendorFilterFromContext(stream.Context()), params, req.SlaBreached)
+	if err != nil {
+		return s.dataError(stream.Context(), err, "fetch data")
+	}
+	for _, row := range rows {
+		if err := stream.Send(dataRowToProto(row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetData returns one row by terminal ID.
+func (s *DataServer) GetData(ctx context.Context, req *datapb.GetDataRequest) (*datapb.DataRow, error) {
+	if req.GetTerminalId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "terminal_id is required")
+	}
+	row, err := s.dataService.GetByTerminalID(ctx, req.GetTerminalId(), vendorFilterFromContext(ctx))
+	if err != nil {
+		// Out-of-scope terminals are indistinguishable from missing ones, as on REST
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return dataRowToProto(row), nil
+}
+
+// UpdateData changes ticket fields and returns the updated row.
+func (s *DataServer) UpdateData(ctx context.Context, req *datapb.UpdateDataRequest) (*datapb.DataRow, error) {
+	if req.GetTerminalId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "terminal_id is required")
+	}
+	row, err := s.dataService.Update(ctx, req.GetTerminalId(), updateRequestFromProto(req), vendorFilterFromContext(ctx))
+	if err != nil {
+		return nil, s.dataError(ctx, err, "update data")
+	}
+	return dataRowToProto(row), nil
+}
+
+// WatchData streams ticket changes from the shared change detector, resuming
+// after last_event_id when the events are still held.
+func (s *DataServer) WatchData(req *datapb.WatchDataRequest, stream datapb.DataService_WatchDataServer) error {
+	ctx := stream.Context()
+	sub, backlog, reset := s.streamService.Subscribe(vendorFilterFromContext(ctx), req.GetLastEventId())
+	defer s.streamService.Unsubscribe(sub)
+
+	if reset {
+		if err := stream.Send(&datapb.ChangeEvent{Type: models.ChangeReset}); err != nil {
+			return err
+		}
+	}
+	for _, ev := range backlog {
+		if err := stream.Send(changeEventToProto(ev)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-sub.Events:
+			if !ok {
+				// Dropped for falling behind, or shutting down; the client resumes with last_event_id
+				return status.Error(codes.Unavailable, "stream closed (subscriber fell behind or server shutting down); reconnect with last_event_id")
+			}
+			if err := stream.Send(changeEventToProto(ev)); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"api-gateway/models"
+	"api-gateway/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// CloudSyncHandler exposes the cloud app sync status, queue and reconciliation
+// runs to admins.
+type CloudSyncHandler struct {
+	service *service.CloudSyncService
+	logger  *logrus.Logger
+}
+
+// NewCloudSyncHandler creates a new CloudSyncHandler instance.
+func NewCloudSyncHandler(service *service.CloudSyncService, logger *logrus.Logger) *CloudSyncHandler {
+	return &CloudSyncHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// respondCloudSyncError maps cloud sync service errors to HTTP responses.
+func (h *CloudSyncHandler) respondCloudSyncError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrCloudSyncDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    err.Error(),
+		})
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    err.Error(),
+		})
+	default:
+		h.logger.WithContext(c.Request.Context()).Errorf("Error trying to %s: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to " + action,
+			"error":      err.Error(),
+		})
+	}
+}
+
+// GetStatus handles GET /api/v1/admin/cloud-sync/status
+// @Summary Cloud Sync Status
+// @Description Whether cloud app sync is configured, queue counts, the most recent success and failure, and the latest reconciliation run
+// @Tags Cloud Sync
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.CloudSyncStatusResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/cloud-sync/status [get]
+func (h *CloudSyncHandler) GetStatus(c *gin.Context) {
+	status, err := h.service.GetStatus()
+	if err != nil {
+		h.respondCloudSyncError(c, err, "get cloud sync status")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CloudSyncStatusResponse{
+		Success: true,
+		Message: "Cloud sync status retrieved successfully",
+		Data:    status,
+	})
+}
+
+// ListQueue handles GET /api/v1/admin/cloud-sync/queue
+// @Summary List Cloud Sync Queue
+// @Description Recent queued ticket snapshots, newest first
+// @Tags Cloud Sync
+// @Accept json
+// @Produce json
+// @Param status query string false "pending, synced, failed or superseded"
+// @Param limit query int false "Max rows (default: 100, max: 1000)"
+// @Success 200 {object} models.CloudSyncItemListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/cloud-sync/queue [get]
+func (h *CloudSyncHandler) ListQueue(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", models.CloudSyncPending, models.CloudSyncSynced, models.CloudSyncFailed, models.CloudSyncSuperseded:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "status must be pending, synced, failed or superseded",
+		})
+		return
+	}
+
+	items, err := h.service.GetItems(status, listLimit(c))
+	if err != nil {
+		h.respondCloudSyncError(c, err, "list cloud sync queue")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CloudSyncItemListResponse{
+		Success: true,
+		Message: "Cloud sync queue retrieved successfully",
+		Data:    items,
+		Total:   len(items),
+	})
+}
+
+// ListFailures handles GET /api/v1/admin/cloud-sync/failures
+// @Summary List Cloud Sync Failures
+// @Description Ticket snapshots that exhausted their retry attempts, newest first
+// @Tags Cloud Sync
+// @Accept json
+// @Produce json
+// @Param limit query int false "Max rows (default: 100, max: 1000)"
+// @Success 200 {object} models.CloudSyncItemListResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/cloud-sync/failures [get]
+func (h *CloudSyncHandler) ListFailures(c *gin.Context) {
+	items, err := h.service.GetFailures(listLimit(c))
+	if err != nil {
+		h.respondCloudSyncError(c, err, "list cloud sync failures")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CloudSyncItemListResponse{
+		Success: true,
+		Message: "Cloud sync failures retrieved successfully",
+		Data:    items,
+		Total:   len(items),
+	})
+}
+
+// RetryItem handles POST /api/v1/admin/cloud-sync/queue/:id/retry
+// @Summary Retry Cloud Sync Item
+// @Description Put a failed snapshot back in the queue with a fresh set of attempts
+// @Tags Cloud Sync
+// @Accept json
+// @Produce json
+// @Param id path int true "Queue item ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Cloud sync not configured"
+// @Router /api/v1/admin/cloud-sync/queue/{id}/retry [post]
+func (h *CloudSyncHandler) RetryItem(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid queue item ID",
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	if err := h.service.RetryItem(c.Request.Context(), id, adminID); err != nil {
+		h.respondCloudSyncError(c, err, "retry cloud sync item")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Cloud sync item requeued",
+	})
+}
+
+// Reconcile handles POST /api/v1/admin/cloud-sync/reconcile
+// @Summary Run Cloud Sync Reconciliation
+// @Description Compare open_ticket with the cloud app now. Missing or differing tickets are queued again; tickets only the cloud app holds are reported.
+// @Tags Cloud Sync
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.CloudSyncReconciliationResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Cloud sync not configured"
+// @Router /api/v1/admin/cloud-sync/reconcile [post]
+func (h *CloudSyncHandler) Reconcile(c *gin.Context) {
+	adminID := c.GetInt("admin_id")
+
+	rec, err := h.service.Reconcile(c.Request.Context(), &adminID)
+	if err != nil {
+		h.respondCloudSyncError(c, err, "reconcile cloud sync")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CloudSyncReconciliationResponse{
+		Success: true,
+		Message: "Cloud sync reconciliation completed",
+		Data:    rec,
+	})
+}
+
+// ListReconciliations handles GET /api/v1/admin/cloud-sync/reconciliations
+// @Summary List Cloud Sync Reconciliations
+// @Description Recent reconciliation runs (scheduled and manual), newest first
+// @Tags Cloud Sync
+// @Accept json
+// @Produce json
+// @Param limit query int false "Max rows (default: 100, max: 1000)"
+// @Success 200 {object} models.CloudSyncReconciliationListResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/cloud-sync/reconciliations [get]
+func (h *CloudSyncHandler) ListReconciliations(c *gin.Context) {
+	recs, err := h.service.GetReconciliations(listLimit(c))
+	if err != nil {
+		h.respondCloudSyncError(c, err, "list cloud sync reconciliations")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CloudSyncReconciliationListResponse{
+		Success: true,
+		Message: "Cloud sync reconciliations retrieved successfully",
+		Data:    recs,
+		Total:   len(recs),
+	})
+}
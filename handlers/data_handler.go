@@ -1,9 +1,13 @@
 package handlers
 
 import (
+	"api-gateway/middleware"
 	"api-gateway/models"
 	"api-gateway/repository"
 	"api-gateway/service"
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
@@ -47,6 +51,23 @@ func vendorFilterFromContext(c *gin.Context) *repository.VendorFilter {
 	return repository.ResolveVendorFilter(col, val, false)
 }
 
+// legacyTimeFormat reports whether the client opted into ticket timestamps
+// exactly as stored (legacy_time_format=true) instead of RFC 3339.
+func legacyTimeFormat(c *gin.Context) bool {
+	legacy, _ := strconv.ParseBool(c.Query("legacy_time_format"))
+	return legacy
+}
+
+// v1ErrorStatus keeps the status a v1 endpoint has always returned for a
+// failure, except that database timeouts and client disconnects get 504 and
+// 499 as on v2.
+func v1ErrorStatus(err error, status int) int {
+	if errors.Is(err, service.ErrQueryTimeout) || errors.Is(err, context.Canceled) {
+		return service.ErrorStatus(err)
+	}
+	return status
+}
+
 // GetAll handles GET /api/v1/data
 // @Summary Get all data
 // @Description Retrieve joined ticket+machine rows with pagination, sorting, and filtering. Vendor-scoped tokens only see rows matching their filter. Admin/Internal tokens see all rows.
@@ -62,11 +83,16 @@ func vendorFilterFromContext(c *gin.Context) *repository.VendorFilter {
 // @Param status query string false "Filter by exact status value (e.g. 0.NEW)"
 // @Param mode query string false "Filter by exact mode value (e.g. Off-line)"
 // @Param priority query string false "Filter by exact priority value (e.g. 1.High)"
+// @Param sla_breached query bool false "Filter by computed SLA breach state (true or false)"
+// @Param legacy_time_format query bool false "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339"
 // @Success 200 {object} models.DataListResponse "Data retrieved successfully"
+// @Failure 400 {object} models.ErrorResponse "Invalid sla_breached value"
 // @Failure 401 {object} models.ErrorResponse "Missing or invalid API token"
 // @Failure 429 {object} models.ErrorResponse "Rate limit exceeded"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /data [get]
+// @Failure 504 {object} models.ErrorResponse "Database query timed out"
+// @Deprecated
+// @Router /api/v1/data [get]
 func (h *DataHandler) GetAll(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "0"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "100"))
@@ -95,17 +121,40 @@ func (h *DataHandler) GetAll(c *gin.Context) {
 		Priority:  strings.TrimSpace(c.Query("priority")),
 	}
 
+	var slaBreached *bool
+	if v := strings.TrimSpace(c.Query("sla_breached")); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			middleware.RecordError(c, fmt.Errorf("%w: sla_breached: %v", service.ErrInvalidInput, err))
+			c.JSON(http.StatusBadRequest, models.DataListResponse{
+				Success:   false,
+				RequestID: c.GetString("request_id"),
+				Message:   "sla_breached must be true or false",
+			})
+			return
+		}
+		slaBreached = &b
+	}
+
 	filter := vendorFilterFromContext(c)
-	rows, total, err := h.service.GetAll(filter, params)
+	rows, total, err := h.service.GetAll(c.Request.Context(), filter, params, slaBreached)
 	if err != nil {
-		h.logger.Errorf("Error fetching data: %v", err)
-		c.JSON(http.StatusInternalServerError, models.DataListResponse{
-			Success: false,
-			Message: "Failed to fetch data",
+		h.logger.WithContext(c.Request.Context()).Errorf("Error fetching data: %v", err)
+		middleware.RecordError(c, err)
+		c.JSON(v1ErrorStatus(err, http.StatusInternalServerError), models.DataListResponse{
+			Success:   false,
+			RequestID: c.GetString("request_id"),
+			Message:   "Failed to fetch data",
 		})
 		return
 	}
 
+	if legacyTimeFormat(c) {
+		for _, row := range rows {
+			row.UseLegacyTimeFormat()
+		}
+	}
+
 	resp := models.DataListResponse{
 		Success:   true,
 		Message:   "Data retrieved successfully",
@@ -117,6 +166,8 @@ func (h *DataHandler) GetAll(c *gin.Context) {
 		Status:    params.Status,
 		Mode:      params.Mode,
 		Priority:  params.Priority,
+
+		SLABreached: slaBreached,
 	}
 
 	if page > 0 {
@@ -140,23 +191,31 @@ func (h *DataHandler) GetAll(c *gin.Context) {
 // @Produce json
 // @Security ApiKeyAuth
 // @Param terminal_id path string true "Terminal ID"
+// @Param legacy_time_format query bool false "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339"
 // @Success 200 {object} models.DataResponse "Data retrieved successfully"
 // @Failure 404 {object} models.ErrorResponse "Not found"
-// @Router /data/{terminal_id} [get]
+// @Deprecated
+// @Router /api/v1/data/{terminal_id} [get]
 func (h *DataHandler) GetByID(c *gin.Context) {
 	terminalID := c.Param("terminal_id")
 	filter := vendorFilterFromContext(c)
 
-	row, err := h.service.GetByTerminalID(terminalID, filter)
+	row, err := h.service.GetByTerminalID(c.Request.Context(), terminalID, filter)
 	if err != nil {
-		h.logger.Errorf("Error fetching data row: %v", err)
-		c.JSON(http.StatusNotFound, models.DataResponse{
-			Success: false,
-			Message: "Not found",
+		h.logger.WithContext(c.Request.Context()).Errorf("Error fetching data row: %v", err)
+		middleware.RecordError(c, err)
+		c.JSON(v1ErrorStatus(err, http.StatusNotFound), models.DataResponse{
+			Success:   false,
+			RequestID: c.GetString("request_id"),
+			Message:   "Not found",
 		})
 		return
 	}
 
+	if legacyTimeFormat(c) {
+		row.UseLegacyTimeFormat()
+	}
+
 	c.JSON(http.StatusOK, models.DataResponse{
 		Success: true,
 		Message: "Data retrieved successfully",
@@ -173,48 +232,54 @@ func (h *DataHandler) GetByID(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Param terminal_id path string true "Terminal ID"
 // @Param body body models.DataUpdateRequest true "Fields to update"
+// @Param Idempotency-Key header string false "Unique key per intended update; a retry with the same key and body replays the stored response"
+// @Param legacy_time_format query bool false "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339"
 // @Success 200 {object} models.DataResponse "Updated successfully"
 // @Failure 400 {object} models.ErrorResponse "Invalid request"
 // @Failure 403 {object} models.ErrorResponse "Outside vendor scope"
+// @Failure 409 {object} models.ErrorResponse "A request with this Idempotency-Key is still in progress"
+// @Failure 422 {object} models.ErrorResponse "Idempotency-Key reused with a different request"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /data/{terminal_id} [put]
+// @Failure 504 {object} models.ErrorResponse "Database query timed out"
+// @Deprecated
+// @Router /api/v1/data/{terminal_id} [put]
 func (h *DataHandler) Update(c *gin.Context) {
 	terminalID := c.Param("terminal_id")
 	filter := vendorFilterFromContext(c)
 
 	var req models.DataUpdateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.Errorf("Invalid request body: %v", err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Invalid request body: %v", err)
+		middleware.RecordError(c, fmt.Errorf("%w: %v", service.ErrInvalidInput, err))
 		c.JSON(http.StatusBadRequest, models.DataResponse{
-			Success: false,
-			Message: "Invalid request data: " + err.Error(),
+			Success:   false,
+			RequestID: c.GetString("request_id"),
+			Message:   "Invalid request data: " + err.Error(),
 		})
 		return
 	}
 
-	row, err := h.service.Update(terminalID, &req, filter)
+	row, err := h.service.Update(c.Request.Context(), terminalID, &req, filter)
 	if err != nil {
-		h.logger.Errorf("Error updating data row: %v", err)
-		statusCode := http.StatusInternalServerError
+		h.logger.WithContext(c.Request.Context()).Errorf("Error updating data row: %v", err)
+		middleware.RecordError(c, err)
+		statusCode := service.ErrorStatus(err)
 		msg := "Failed to update"
-		errMsg := err.Error()
-		if errMsg == "not found or not accessible for this vendor" {
-			statusCode = http.StatusForbidden
-			msg = errMsg
-		} else if errMsg == "not found" {
-			statusCode = http.StatusNotFound
-			msg = errMsg
-		} else if errMsg == "no fields to update" {
-			statusCode = http.StatusBadRequest
-			msg = errMsg
+		if service.ErrorCode(err) != models.ErrCodeInternal {
+			msg = err.Error()
 		}
 		c.JSON(statusCode, models.DataResponse{
-			Success: false,
-			Message: msg,
+			Success:   false,
+			RequestID: c.GetString("request_id"),
+			Message:   msg,
 		})
 		return
 	}
 
+	if legacyTimeFormat(c) {
+		row.UseLegacyTimeFormat()
+	}
+
 	c.JSON(http.StatusOK, models.DataResponse{
 		Success: true,
 		Message: "Updated successfully",
@@ -222,6 +287,57 @@ func (h *DataHandler) Update(c *gin.Context) {
 	})
 }
 
+// GetByFLM handles GET /api/v1/data/by-flm
+// @Summary Get tickets grouped by FLM
+// @Description Retrieve open tickets grouped by machine FLM branch with the service area and per-group counts. Groups are sorted by count (largest first). Vendor-scoped tokens only see rows matching their filter.
+// @Tags Data
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Filter by exact status value (e.g. 0.NEW)"
+// @Param priority query string false "Filter by exact priority value (e.g. 1.High)"
+// @Param legacy_time_format query bool false "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339"
+// @Success 200 {object} models.TicketsByFLMResponse "Tickets by FLM retrieved successfully"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API token"
+// @Failure 429 {object} models.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 504 {object} models.ErrorResponse "Database query timed out"
+// @Deprecated
+// @Router /api/v1/data/by-flm [get]
+func (h *DataHandler) GetByFLM(c *gin.Context) {
+	filter := vendorFilterFromContext(c)
+	status := strings.TrimSpace(c.Query("status"))
+	priority := strings.TrimSpace(c.Query("priority"))
+
+	groups, totalTickets, err := h.service.GetTicketsByFLM(c.Request.Context(), filter, status, priority)
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Error fetching tickets by FLM: %v", err)
+		middleware.RecordError(c, err)
+		c.JSON(v1ErrorStatus(err, http.StatusInternalServerError), models.ErrorResponse{
+			Success:   false,
+			RequestID: c.GetString("request_id"),
+			Message:   "Failed to fetch tickets by FLM",
+		})
+		return
+	}
+
+	if legacyTimeFormat(c) {
+		for _, group := range groups {
+			for _, ticket := range group.Tickets {
+				ticket.UseLegacyTimeFormat()
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, models.TicketsByFLMResponse{
+		Success:      true,
+		Message:      "Tickets by FLM retrieved successfully",
+		Data:         groups,
+		Total:        len(groups),
+		TotalTickets: totalTickets,
+	})
+}
+
 // GetMetadata handles GET /api/v1/data/metadata
 // @Summary Get field metadata
 // @Description Retrieve all valid values for status, mode, and priority fields. Cached for 1 hour.
@@ -231,15 +347,19 @@ func (h *DataHandler) Update(c *gin.Context) {
 // @Security ApiKeyAuth
 // @Success 200 {object} models.MetadataResponse "Metadata retrieved successfully"
 // @Failure 500 {object} models.ErrorResponse "Internal server error"
-// @Router /data/metadata [get]
+// @Failure 504 {object} models.ErrorResponse "Database query timed out"
+// @Deprecated
+// @Router /api/v1/data/metadata [get]
 func (h *DataHandler) GetMetadata(c *gin.Context) {
-	metadata, err := h.service.GetMetadata()
+	metadata, err := h.service.GetMetadata(c.Request.Context())
 	if err != nil {
-		h.logger.Errorf("Error fetching metadata: %v", err)
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Success: false,
-			Message: "Failed to fetch metadata",
-			Error:   err.Error(),
+		h.logger.WithContext(c.Request.Context()).Errorf("Error fetching metadata: %v", err)
+		middleware.RecordError(c, err)
+		c.JSON(v1ErrorStatus(err, http.StatusInternalServerError), models.ErrorResponse{
+			Success:   false,
+			RequestID: c.GetString("request_id"),
+			Message:   "Failed to fetch metadata",
+			Error:     err.Error(),
 		})
 		return
 	}
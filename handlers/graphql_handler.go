@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"api-gateway/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/sirupsen/logrus"
+)
+
+// maxGraphQLQueryBytes caps the query document size.
+const maxGraphQLQueryBytes = 32 << 10
+
+var (
+	errMachineDBUnavailable = errors.New("machine database is not configured")
+	errTokenDBUnavailable   = errors.New("token database is not configured")
+)
+
+// GraphQLHandler serves the read-only GraphQL API over tickets, machines,
+// metadata and stats. It sits behind the same token auth, vendor scoping and
+// rate limits as the REST endpoints.
+type GraphQLHandler struct {
+	schema         graphql.Schema
+	machineService *service.MachineService
+	maxDepth       int
+	maxRows        int
+	logger         *logrus.Logger
+}
+
+// NewGraphQLHandler creates a new GraphQLHandler instance. machineService and
+// statsService may be nil when their databases are not configured.
+func NewGraphQLHandler(
+	dataService *service.DataService,
+	machineService *service.MachineService,
+	statsService *service.StatsService,
+	maxDepth, maxRows int,
+	logger *logrus.Logger,
+) (*GraphQLHandler, error) {
+	schema, err := newGraphQLSchema(dataService, machineService, statsService)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build GraphQL schema: %w", err)
+	}
+	return &GraphQLHandler{
+		schema:         schema,
+		machineService: machineService,
+		maxDepth:       maxDepth,
+		maxRows:        maxRows,
+		logger:         logger,
+	}, nil
+}
+
+// graphQLRequest is a GraphQL-over-HTTP request body.
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// respondGraphQLError writes a request-level error in the GraphQL response format.
+func respondGraphQLError(c *gin.Context, status int, errs ...gqlerrors.FormattedError) {
+	c.JSON(status, &graphql.Result{Errors: tagGraphQLErrors(c, errs)})
+}
+
+// tagGraphQLErrors adds the request ID to the extensions of every error.
+func tagGraphQLErrors(c *gin.Context, errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	requestID := c.GetString("request_id")
+	if requestID == "" {
+		return errs
+	}
+	for i := range errs {
+		if errs[i].Extensions == nil {
+			errs[i].Extensions = map[string]interface{}{}
+		}
+		errs[i].Extensions["request_id"] = requestID
+	}
+	return errs
+}
+
+// Query handles GET and POST /api/v1/graphql
+// @Summary GraphQL query
+// @Description Read-only GraphQL API over open tickets (DataRow), machine master records, metadata and aggregated stats. Vendor-scoped tokens only see rows inside their filter. Queries deeper than GRAPHQL_MAX_DEPTH, or that could return more than GRAPHQL_MAX_ROWS records, are rejected before they run.
+// @Tags Data
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param query query string false "Query document (GET)"
+// @Param variables query string false "JSON-encoded variables (GET)"
+// @Param operationName query string false "Operation to run when the document has several (GET)"
+// @Success 200 {object} map[string]interface{} "data and, when a field failed, errors"
+// @Failure 400 {object} map[string]interface{} "Syntax, validation or query cost error"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API token"
+// @Failure 429 {object} models.ErrorResponse "Rate limit exceeded"
+// @Router /api/v1/graphql [post]
+// @Router /api/v2/graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req graphQLRequest
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if v := c.Query("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				respondGraphQLError(c, http.StatusBadRequest, gqlerrors.NewFormattedError("variables must be a JSON object"))
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		respondGraphQLError(c, http.StatusBadRequest, gqlerrors.NewFormattedError("Invalid request body: "+err.Error()))
+		return
+	}
+
+	if req.Query == "" {
+		respondGraphQLError(c, http.StatusBadRequest, gqlerrors.NewFormattedError("query is required"))
+		return
+	}
+	if len(req.Query) > maxGraphQLQueryBytes {
+		respondGraphQLError(c, http.StatusBadRequest, gqlerrors.NewFormattedError(
+			fmt.Sprintf("query is larger than %d bytes", maxGraphQLQueryBytes)))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		respondGraphQLError(c, http.StatusBadRequest, gqlerrors.FormatError(err))
+		return
+	}
+	if result := graphql.ValidateDocument(&h.schema, doc, nil); !result.IsValid {
+		respondGraphQLError(c, http.StatusBadRequest, result.Errors...)
+		return
+	}
+	if err := checkGraphQLCost(doc, req.OperationName, req.Variables, h.maxDepth, h.maxRows); err != nil {
+		respondGraphQLError(c, http.StatusBadRequest, gqlerrors.NewFormattedError(err.Error()))
+		return
+	}
+
+	filter := vendorFilterFromContext(c)
+	ctx := context.WithValue(c.Request.Context(), graphQLVendorFilterKey, filter)
+	if h.machineService != nil {
+		ctx = context.WithValue(ctx, graphQLMachineLoaderKey, newMachineLoader(h.machineService, filter))
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       ctx,
+	})
+	if result.HasErrors() {
+		h.logger.WithContext(c.Request.Context()).Warnf("GraphQL query returned errors: %v", result.Errors)
+		result.Errors = tagGraphQLErrors(c, result.Errors)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
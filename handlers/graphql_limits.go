@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// graphQLRowFields are the fields whose selections return ticket, terminal or
+// machine records. Each one costs as many rows as it can return.
+var graphQLRowFields = map[string]bool{
+	"items":              true, // DataPage.items
+	"ticket":             true,
+	"machine":            true, // Query.machine and DataRow.machine
+	"critical_terminals": true,
+}
+
+// graphQLListArgs are the arguments that size a list, by field name.
+var graphQLListArgs = map[string]string{
+	"data":               "page_size",
+	"critical_terminals": "limit",
+}
+
+// graphQLCost walks one operation before it runs and rejects it when it is
+// nested deeper than maxDepth or could return more than maxRows records.
+// Rows multiply down the tree: data(page_size: 500) { items { machine } }
+// costs 1000, and aliased copies of a field are counted separately, so a
+// query cannot get around pagination by asking for many pages at once.
+type graphQLCost struct {
+	doc       *ast.Document
+	variables map[string]interface{}
+	defaults  map[string]ast.Value
+	maxDepth  int
+	maxRows   int
+
+	rows int
+}
+
+// checkGraphQLCost applies the depth and row limits to the operation that will
+// be executed. doc must already have passed validation.
+func checkGraphQLCost(doc *ast.Document, operationName string, variables map[string]interface{}, maxDepth, maxRows int) error {
+	var op *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		if d, ok := def.(*ast.OperationDefinition); ok {
+			if operationName == "" || (d.Name != nil && d.Name.Value == operationName) {
+				op = d
+				break
+			}
+		}
+	}
+	if op == nil {
+		return fmt.Errorf("unknown operation %q", operationName)
+	}
+
+	cost := &graphQLCost{
+		doc:       doc,
+		variables: variables,
+		defaults:  make(map[string]ast.Value),
+		maxDepth:  maxDepth,
+		maxRows:   maxRows,
+	}
+	for _, vd := range op.VariableDefinitions {
+		if vd.Variable != nil && vd.Variable.Name != nil && vd.DefaultValue != nil {
+			cost.defaults[vd.Variable.Name.Value] = vd.DefaultValue
+		}
+	}
+	return cost.walk(op.SelectionSet, 0, 1)
+}
+
+// walk visits a selection set at the given depth; multiplier is how many
+// times the enclosing object can occur in the result.
+func (q *graphQLCost) walk(set *ast.SelectionSet, depth, multiplier int) error {
+	if set == nil {
+		return nil
+	}
+	for _, sel := range set.Selections {
+		switch s := sel.(type) {
+		case *ast.Field:
+			name := s.Name.Value
+			if strings.HasPrefix(name, "__") {
+				continue // introspection is schema-sized, not data-sized
+			}
+			if depth+1 > q.maxDepth {
+				return fmt.Errorf("query is nested deeper than the maximum depth of %d", q.maxDepth)
+			}
+
+			m := multiplier
+			if arg, ok := graphQLListArgs[name]; ok {
+				m *= graphQLPageSize(q.intArg(s, arg))
+			}
+			if graphQLRowFields[name] {
+				q.rows += m
+			}
+			if q.rows > q.maxRows {
+				return fmt.Errorf("query could return %d rows, more than the maximum of %d; request smaller pages", q.rows, q.maxRows)
+			}
+
+			if err := q.walk(s.SelectionSet, depth+1, m); err != nil {
+				return err
+			}
+		case *ast.InlineFragment:
+			if err := q.walk(s.SelectionSet, depth, multiplier); err != nil {
+				return err
+			}
+		case *ast.FragmentSpread:
+			if frag := q.fragment(s.Name.Value); frag != nil {
+				if err := q.walk(frag.SelectionSet, depth, multiplier); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// fragment looks up a named fragment definition.
+func (q *graphQLCost) fragment(name string) *ast.FragmentDefinition {
+	for _, def := range q.doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok && f.Name != nil && f.Name.Value == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// intArg returns an integer argument of a field, literal or variable, or 0 when
+// it is absent (graphQLPageSize then applies the default).
+func (q *graphQLCost) intArg(field *ast.Field, name string) int {
+	for _, arg := range field.Arguments {
+		if arg.Name == nil || arg.Name.Value != name {
+			continue
+		}
+		value := arg.Value
+		if v, ok := value.(*ast.Variable); ok {
+			if raw, ok := q.variables[v.Name.Value]; ok {
+				switch n := raw.(type) {
+				case float64:
+					return int(n)
+				case int:
+					return n
+				}
+				return 0
+			}
+			value = q.defaults[v.Name.Value]
+		}
+		if v, ok := value.(*ast.IntValue); ok {
+			n, _ := strconv.Atoi(v.Value)
+			return n
+		}
+	}
+	return 0
+}
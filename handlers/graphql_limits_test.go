@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+func parseGraphQL(t *testing.T, query string) *ast.Document {
+	t.Helper()
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		t.Fatalf("parse %q: %v", query, err)
+	}
+	return doc
+}
+
+// Each case passes with exactly its depth and row limits, and fails with one
+// less of either.
+func TestCheckGraphQLCost(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]interface{}
+		depth     int
+		rows      int
+	}{
+		{
+			name:  "data without page_size uses the default page",
+			query: `{ data { items { terminal_id } } }`,
+			depth: 3, rows: 100,
+		},
+		{
+			name:  "page_size is clamped to the maximum",
+			query: `{ data(page_size: 5000) { items { terminal_id } } }`,
+			depth: 3, rows: 500,
+		},
+		{
+			name:  "page_size below one falls back to the default",
+			query: `{ data(page_size: 0) { items { terminal_id } } }`,
+			depth: 3, rows: 100,
+		},
+		{
+			name:  "nested machine multiplies with page_size",
+			query: `{ data(page_size: 500) { items { terminal_id machine { store } } } }`,
+			depth: 4, rows: 1000,
+		},
+		{
+			name: "aliased copies are counted separately",
+			query: `{
+				first: data(page: 1, page_size: 300) { items { terminal_id } }
+				second: data(page: 2, page_size: 300) { items { terminal_id } }
+				third: data(page: 3, page_size: 300) { total }
+			}`,
+			depth: 3, rows: 600,
+		},
+		{
+			name: "named fragments are expanded at the spread",
+			query: `
+				{ data(page_size: 200) { ...page } }
+				fragment page on DataPage { items { terminal_id machine { store } } }`,
+			depth: 4, rows: 400,
+		},
+		{
+			name:  "inline fragments are walked",
+			query: `{ data(page_size: 50) { ... on DataPage { items { terminal_id } } } }`,
+			depth: 3, rows: 50,
+		},
+		{
+			name:      "page_size from a variable",
+			query:     `query ($n: Int) { data(page_size: $n) { items { terminal_id } } }`,
+			variables: map[string]interface{}{"n": float64(250)},
+			depth:     3, rows: 250,
+		},
+		{
+			name:  "page_size from a variable's default",
+			query: `query ($n: Int = 300) { data(page_size: $n) { items { terminal_id } } }`,
+			depth: 3, rows: 300,
+		},
+		{
+			name:      "a supplied variable overrides its default",
+			query:     `query ($n: Int = 300) { data(page_size: $n) { items { terminal_id } } }`,
+			variables: map[string]interface{}{"n": float64(20)},
+			depth:     3, rows: 20,
+		},
+		{
+			name:  "ticket and its machine",
+			query: `{ ticket(terminal_id: "ATM-001") { terminal_id machine { store } } }`,
+			depth: 3, rows: 2,
+		},
+		{
+			name:  "critical_terminals is sized by limit",
+			query: `{ critical_terminals(limit: 10) { terminal_id } }`,
+			depth: 2, rows: 10,
+		},
+		{
+			name:  "machine by terminal ID",
+			query: `{ machine(terminal_id: "ATM-001") { store } }`,
+			depth: 2, rows: 1,
+		},
+		{
+			name: "only the selected operation is counted",
+			query: `
+				query Big { data(page_size: 500) { items { terminal_id machine { store } } } }
+				query Small { machine(terminal_id: "ATM-001") { store } }`,
+			operation: "Small",
+			depth:     2, rows: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseGraphQL(t, tt.query)
+
+			if err := checkGraphQLCost(doc, tt.operation, tt.variables, tt.depth, tt.rows); err != nil {
+				t.Errorf("rejected at depth %d, rows %d: %v", tt.depth, tt.rows, err)
+			}
+			err := checkGraphQLCost(doc, tt.operation, tt.variables, tt.depth-1, tt.rows)
+			if err == nil || !strings.Contains(err.Error(), "depth") {
+				t.Errorf("max depth %d: got %v, want a depth error", tt.depth-1, err)
+			}
+			err = checkGraphQLCost(doc, tt.operation, tt.variables, tt.depth, tt.rows-1)
+			if err == nil || !strings.Contains(err.Error(), "rows") {
+				t.Errorf("max rows %d: got %v, want a row error", tt.rows-1, err)
+			}
+		})
+	}
+}
+
+func TestCheckGraphQLCostIgnoresIntrospection(t *testing.T) {
+	doc := parseGraphQL(t, `{ __schema { types { name fields { name type { name } } } } }`)
+	if err := checkGraphQLCost(doc, "", nil, 1, 0); err != nil {
+		t.Errorf("introspection rejected: %v", err)
+	}
+}
+
+func TestCheckGraphQLCostUnknownOperation(t *testing.T) {
+	doc := parseGraphQL(t, `query Small { machine(terminal_id: "ATM-001") { store } }`)
+	if err := checkGraphQLCost(doc, "Missing", nil, 8, 1000); err == nil {
+		t.Error("unknown operation accepted")
+	}
+}
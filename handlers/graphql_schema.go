@@ -0,0 +1,476 @@
+package handlers
+
+import (
+	"api-gateway/models"
+	"api-gateway/repository"
+	"api-gateway/service"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// graphQLContextKey keys the per-request values resolvers read from the context.
+type graphQLContextKey string
+
+const (
+	graphQLVendorFilterKey  graphQLContextKey = "vendor_filter"
+	graphQLMachineLoaderKey graphQLContextKey = "machine_loader"
+)
+
+// graphQLVendorFilter returns the vendor filter of the token that sent the query.
+func graphQLVendorFilter(ctx context.Context) *repository.VendorFilter {
+	filter, _ := ctx.Value(graphQLVendorFilterKey).(*repository.VendorFilter)
+	return filter
+}
+
+// machineLoader batches the DataRow.machine lookups of one query into a single
+// machine_master read. Each row registers its terminal ID and returns a thunk;
+// the executor runs the thunks after the whole list has been resolved, so the
+// first thunk loads every pending ID at once.
+type machineLoader struct {
+	service *service.MachineService
+	filter  *repository.VendorFilter
+
+	mu      sync.Mutex
+	pending []string
+	loaded  map[string]*models.ATMI
+}
+
+func newMachineLoader(svc *service.MachineService, filter *repository.VendorFilter) *machineLoader {
+	return &machineLoader{
+		service: svc,
+		filter:  filter,
+		loaded:  make(map[string]*models.ATMI),
+	}
+}
+
+// load queues terminalID and returns a thunk resolving to its machine record
+// (nil when the terminal has none, or it is outside the vendor scope). The
+// batch is read under the context of the thunk that runs it.
+func (l *machineLoader) load(ctx context.Context, terminalID string) func() (interface{}, error) {
+	l.mu.Lock()
+	l.pending = append(l.pending, terminalID)
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			ids := l.pending
+			l.pending = nil
+			machines, err := l.service.GetByTerminalIDs(ctx, ids, l.filter)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range ids {
+				l.loaded[id] = machines[id]
+			}
+		}
+		if m := l.loaded[terminalID]; m != nil {
+			return m, nil
+		}
+		return nil, nil
+	}
+}
+
+// dataPage is the GraphQL shape of one page of GET /api/v1/data.
+type dataPage struct {
+	Items      []*models.DataRow `json:"items"`
+	Total      int               `json:"total"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// graphQLPageSize clamps a list size argument the same way GET /api/v1/data
+// clamps page_size. The cost limiter uses it too, so what it counts is what
+// the resolvers return.
+func graphQLPageSize(n int) int {
+	if n < 1 {
+		return defaultGraphQLPageSize
+	}
+	if n > maxGraphQLPageSize {
+		return maxGraphQLPageSize
+	}
+	return n
+}
+
+const (
+	defaultGraphQLPageSize = 100
+	maxGraphQLPageSize     = 500
+)
+
+// nullStringField resolves a nullable ticket column to a string or null.
+func nullStringField(get func(*models.DataRow) models.NullString) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			row, ok := p.Source.(*models.DataRow)
+			if !ok {
+				return nil, nil
+			}
+			if v := get(row); v.Valid {
+				return v.String, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+// ticketTimeField resolves a ticket timestamp to RFC 3339 in the ticket zone,
+// or null when the column is NULL or unparseable (as on the REST endpoint).
+func ticketTimeField(get func(*models.DataRow) models.TicketTime) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			row, ok := p.Source.(*models.DataRow)
+			if !ok {
+				return nil, nil
+			}
+			if v := get(row); v.Valid {
+				return v.Time.In(models.TicketLocation()).Format(time.RFC3339), nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+// timeField resolves a *time.Time (or nullable time) returned by get to RFC 3339.
+func timeField(get func(interface{}) *time.Time) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			if t := get(p.Source); t != nil {
+				return t.Format(time.RFC3339), nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+// newGraphQLSchema builds the read-only schema served at /api/v1/graphql.
+// statsService and machineService may be nil (token DB or machine DB not
+// configured); their fields then resolve to an error.
+func newGraphQLSchema(dataService *service.DataService, machineService *service.MachineService, statsService *service.StatsService) (graphql.Schema, error) {
+	machineType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Machine",
+		Description: "Machine master record (machine_master.dbo.atmi)",
+		Fields: graphql.Fields{
+			"terminal_id": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"store":       &graphql.Field{Type: graphql.String},
+			"store_code":  &graphql.Field{Type: graphql.String},
+			"store_name":  &graphql.Field{Type: graphql.String},
+			"date_of_activation": timeField(func(src interface{}) *time.Time {
+				if m, ok := src.(*models.ATMI); ok && m.DateOfActivation.Valid {
+					return &m.DateOfActivation.Time
+				}
+				return nil
+			}),
+			"status":       &graphql.Field{Type: graphql.String},
+			"std":          &graphql.Field{Type: graphql.Int},
+			"gps":          &graphql.Field{Type: graphql.String},
+			"lat":          &graphql.Field{Type: graphql.Float},
+			"lon":          &graphql.Field{Type: graphql.Float},
+			"province":     &graphql.Field{Type: graphql.String},
+			"city_regency": &graphql.Field{Type: graphql.String},
+			"district":     &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	dataRowType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "DataRow",
+		Description: "Open ticket joined with its machine dimension columns, as returned by GET /api/v1/data",
+		Fields: graphql.Fields{
+			"terminal_id":     &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"terminal_name":   &graphql.Field{Type: graphql.String},
+			"priority":        nullStringField(func(r *models.DataRow) models.NullString { return r.Priority }),
+			"mode":            nullStringField(func(r *models.DataRow) models.NullString { return r.Mode }),
+			"initial_problem": nullStringField(func(r *models.DataRow) models.NullString { return r.InitialProblem }),
+			"current_problem": nullStringField(func(r *models.DataRow) models.NullString { return r.CurrentProblem }),
+			"p_duration":      nullStringField(func(r *models.DataRow) models.NullString { return r.PDuration }),
+			"incident_start_datetime": ticketTimeField(func(r *models.DataRow) models.TicketTime {
+				return r.IncidentStartTime
+			}),
+			"count":            &graphql.Field{Type: graphql.Int},
+			"status":           nullStringField(func(r *models.DataRow) models.NullString { return r.Status }),
+			"remarks":          nullStringField(func(r *models.DataRow) models.NullString { return r.Remarks }),
+			"balance":          &graphql.Field{Type: graphql.Int},
+			"condition":        nullStringField(func(r *models.DataRow) models.NullString { return r.Condition }),
+			"tickets_no":       nullStringField(func(r *models.DataRow) models.NullString { return r.TicketsNo }),
+			"tickets_duration": &graphql.Field{Type: graphql.Float},
+			"open_time":        ticketTimeField(func(r *models.DataRow) models.TicketTime { return r.OpenTime }),
+			"close_time":       ticketTimeField(func(r *models.DataRow) models.TicketTime { return r.CloseTime }),
+			"problem_history":  nullStringField(func(r *models.DataRow) models.NullString { return r.ProblemHistory }),
+			"mode_history":     nullStringField(func(r *models.DataRow) models.NullString { return r.ModeHistory }),
+			"dsp_flm":          nullStringField(func(r *models.DataRow) models.NullString { return r.DSPFLM }),
+			"dsp_slm":          nullStringField(func(r *models.DataRow) models.NullString { return r.DSPSLM }),
+			"last_withdrawal": timeField(func(src interface{}) *time.Time {
+				if r, ok := src.(*models.DataRow); ok && r.LastWithdrawal.Valid {
+					return &r.LastWithdrawal.Time
+				}
+				return nil
+			}),
+			"export_name": nullStringField(func(r *models.DataRow) models.NullString { return r.ExportName }),
+			"flm_name":    nullStringField(func(r *models.DataRow) models.NullString { return r.FLMName }),
+			"flm":         nullStringField(func(r *models.DataRow) models.NullString { return r.FLM }),
+			"slm":         nullStringField(func(r *models.DataRow) models.NullString { return r.SLM }),
+			"net":         nullStringField(func(r *models.DataRow) models.NullString { return r.Net }),
+
+			"sla_policy": &graphql.Field{Type: graphql.String},
+			"sla_due_at": timeField(func(src interface{}) *time.Time {
+				if r, ok := src.(*models.DataRow); ok {
+					return r.SLADueAt
+				}
+				return nil
+			}),
+			"sla_remaining_minutes": &graphql.Field{Type: graphql.Int},
+			"sla_breached":          &graphql.Field{Type: graphql.Boolean},
+			"sla_response_due_at": timeField(func(src interface{}) *time.Time {
+				if r, ok := src.(*models.DataRow); ok {
+					return r.SLAResponseDueAt
+				}
+				return nil
+			}),
+			"sla_response_breached": &graphql.Field{Type: graphql.Boolean},
+
+			"machine": &graphql.Field{
+				Type:        machineType,
+				Description: "Machine master record of the terminal (null when none exists)",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					row, ok := p.Source.(*models.DataRow)
+					if !ok {
+						return nil, nil
+					}
+					loader, _ := p.Context.Value(graphQLMachineLoaderKey).(*machineLoader)
+					if loader == nil {
+						return nil, errMachineDBUnavailable
+					}
+					return loader.load(p.Context, row.TerminalID), nil
+				},
+			},
+		},
+	})
+
+	dataPageType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "DataPage",
+		Fields: graphql.Fields{
+			"items":       &graphql.Field{Type: graphql.NewList(graphql.NewNonNull(dataRowType))},
+			"total":       &graphql.Field{Type: graphql.Int},
+			"page":        &graphql.Field{Type: graphql.Int},
+			"page_size":   &graphql.Field{Type: graphql.Int},
+			"total_pages": &graphql.Field{Type: graphql.Int},
+		},
+	})
+
+	valueInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ValueInfo",
+		Description: "A status, mode or priority value with its documented meaning",
+		Fields: graphql.Fields{
+			"code":          &graphql.Field{Type: graphql.String},
+			"description":   &graphql.Field{Type: graphql.String},
+			"is_documented": &graphql.Field{Type: graphql.Boolean},
+		},
+	})
+
+	metadataType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Metadata",
+		Fields: graphql.Fields{
+			"statuses":     &graphql.Field{Type: graphql.NewList(valueInfoType)},
+			"modes":        &graphql.Field{Type: graphql.NewList(valueInfoType)},
+			"priorities":   &graphql.Field{Type: graphql.NewList(valueInfoType)},
+			"last_updated": &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	countType := func(name, key string) *graphql.Object {
+		return graphql.NewObject(graphql.ObjectConfig{
+			Name: name,
+			Fields: graphql.Fields{
+				key:     &graphql.Field{Type: graphql.String},
+				"count": &graphql.Field{Type: graphql.Int},
+			},
+		})
+	}
+
+	statsType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "TicketStats",
+		Description: "Open tickets in the token's scope, aggregated",
+		Fields: graphql.Fields{
+			"total_count":          &graphql.Field{Type: graphql.Int},
+			"by_status":            &graphql.Field{Type: graphql.NewList(countType("StatusCount", "status"))},
+			"by_priority":          &graphql.Field{Type: graphql.NewList(countType("PriorityCount", "priority"))},
+			"by_mode":              &graphql.Field{Type: graphql.NewList(countType("ModeCount", "mode"))},
+			"avg_duration_minutes": &graphql.Field{Type: graphql.Float},
+			"sla_breached_count":   &graphql.Field{Type: graphql.Int},
+		},
+	})
+
+	criticalTerminalType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "CriticalTerminal",
+		Description: "Open ticket matching at least one active criticality rule",
+		Fields: graphql.Fields{
+			"terminal_id":      &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"terminal_name":    &graphql.Field{Type: graphql.String},
+			"location":         &graphql.Field{Type: graphql.String},
+			"status":           &graphql.Field{Type: graphql.String},
+			"ticket_status":    &graphql.Field{Type: graphql.String},
+			"priority":         &graphql.Field{Type: graphql.String},
+			"duration_minutes": &graphql.Field{Type: graphql.Float},
+			"problem":          &graphql.Field{Type: graphql.String},
+			"flm":              &graphql.Field{Type: graphql.String},
+			"slm":              &graphql.Field{Type: graphql.String},
+			"gps":              &graphql.Field{Type: graphql.String},
+			"matched_rules":    &graphql.Field{Type: graphql.NewList(graphql.String)},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"data": &graphql.Field{
+				Type:        graphql.NewNonNull(dataPageType),
+				Description: "One page of open tickets, filtered and sorted like GET /api/v1/data",
+				Args: graphql.FieldConfigArgument{
+					"page":         &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+					"page_size":    &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultGraphQLPageSize, Description: "Items per page (max 500)"},
+					"sort_by":      &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "incident_start_datetime"},
+					"sort_order":   &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "desc"},
+					"search":       &graphql.ArgumentConfig{Type: graphql.String},
+					"status":       &graphql.ArgumentConfig{Type: graphql.String},
+					"mode":         &graphql.ArgumentConfig{Type: graphql.String},
+					"priority":     &graphql.ArgumentConfig{Type: graphql.String},
+					"sla_breached": &graphql.ArgumentConfig{Type: graphql.Boolean},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					page, _ := p.Args["page"].(int)
+					if page < 1 {
+						page = 1
+					}
+					pageSize, _ := p.Args["page_size"].(int)
+					pageSize = graphQLPageSize(pageSize)
+
+					sortOrder, _ := p.Args["sort_order"].(string)
+					if sortOrder != "asc" && sortOrder != "desc" {
+						sortOrder = "desc"
+					}
+					params := repository.QueryParams{
+						Page:      page,
+						PageSize:  pageSize,
+						SortOrder: sortOrder,
+					}
+					params.SortBy, _ = p.Args["sort_by"].(string)
+					params.Search = graphQLStringArg(p.Args, "search")
+					params.Status = graphQLStringArg(p.Args, "status")
+					params.Mode = graphQLStringArg(p.Args, "mode")
+					params.Priority = graphQLStringArg(p.Args, "priority")
+
+					var slaBreached *bool
+					if b, ok := p.Args["sla_breached"].(bool); ok {
+						slaBreached = &b
+					}
+
+					rows, total, err := dataService.GetAll(p.Context, graphQLVendorFilter(p.Context), params, slaBreached)
+					if err != nil {
+						return nil, err
+					}
+					totalPages := total / pageSize
+					if total%pageSize > 0 {
+						totalPages++
+					}
+					return &dataPage{
+						Items:      rows,
+						Total:      total,
+						Page:       page,
+						PageSize:   pageSize,
+						TotalPages: totalPages,
+					}, nil
+				},
+			},
+			"ticket": &graphql.Field{
+				Type:        dataRowType,
+				Description: "One open ticket by terminal ID (null when absent or outside the token's scope)",
+				Args: graphql.FieldConfigArgument{
+					"terminal_id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					row, err := dataService.GetByTerminalID(p.Context, p.Args["terminal_id"].(string), graphQLVendorFilter(p.Context))
+					if err != nil {
+						if errors.Is(err, service.ErrNotFound) {
+							return nil, nil
+						}
+						return nil, err
+					}
+					return row, nil
+				},
+			},
+			"machine": &graphql.Field{
+				Type:        machineType,
+				Description: "One machine master record by terminal ID (null when absent or outside the token's scope)",
+				Args: graphql.FieldConfigArgument{
+					"terminal_id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if machineService == nil {
+						return nil, errMachineDBUnavailable
+					}
+					m, err := machineService.GetByTerminalID(p.Context, p.Args["terminal_id"].(string), graphQLVendorFilter(p.Context))
+					if err != nil {
+						if errors.Is(err, service.ErrNotFound) {
+							return nil, nil
+						}
+						return nil, err
+					}
+					return m, nil
+				},
+			},
+			"metadata": &graphql.Field{
+				Type:        metadataType,
+				Description: "Distinct status, mode and priority values (same as GET /api/v1/data/metadata)",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return dataService.GetMetadata(p.Context)
+				},
+			},
+			"stats": &graphql.Field{
+				Type:        statsType,
+				Description: "Open ticket counts by status, priority and mode in the token's scope",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return dataService.GetStats(p.Context, graphQLVendorFilter(p.Context))
+				},
+			},
+			"critical_terminals": &graphql.Field{
+				Type:        graphql.NewList(graphql.NewNonNull(criticalTerminalType)),
+				Description: "Longest-running critical terminals (same as GET /api/v1/stats/critical)",
+				Args: graphql.FieldConfigArgument{
+					"limit": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: defaultGraphQLPageSize, Description: "Max terminals (max 500)"},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if statsService == nil {
+						return nil, errTokenDBUnavailable
+					}
+					terminals, err := statsService.GetCriticalTerminals(p.Context, graphQLVendorFilter(p.Context))
+					if err != nil {
+						return nil, err
+					}
+					limit, _ := p.Args["limit"].(int)
+					if limit = graphQLPageSize(limit); len(terminals) > limit {
+						terminals = terminals[:limit]
+					}
+					return terminals, nil
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// graphQLStringArg returns a trimmed optional string argument.
+func graphQLStringArg(args map[string]interface{}, name string) string {
+	s, _ := args[name].(string)
+	return strings.TrimSpace(s)
+}
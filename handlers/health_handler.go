@@ -1,60 +1,113 @@
 package handlers
 
 import (
-	"api-gateway/database"
+	"api-gateway/models"
+	"api-gateway/service"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
+// legacyHealthKeys maps dependency names to the keys of the /health "services" object
+var legacyHealthKeys = map[string]string{
+	"ticket_master":    "ticket_database",
+	"machine_master":   "machine_database",
+	"token_management": "token_database",
+}
+
 // HealthHandler handles health check requests
 type HealthHandler struct {
-	dbManager *database.DBManager
-	logger    *logrus.Logger
+	healthService *service.HealthService
+	logger        *logrus.Logger
 }
 
 // NewHealthHandler creates a new health handler instance
-func NewHealthHandler(dbManager *database.DBManager, logger *logrus.Logger) *HealthHandler {
+func NewHealthHandler(healthService *service.HealthService, logger *logrus.Logger) *HealthHandler {
 	return &HealthHandler{
-		dbManager: dbManager,
-		logger:    logger,
+		healthService: healthService,
+		logger:        logger,
 	}
 }
 
-// Check handles GET /health - performs real-time health check on the API and databases
+// Live handles GET /livez - the process is up and serving HTTP
+// @Summary Liveness probe
+// @Description Answers 200 while the process can serve requests. It never touches a dependency, so a hung database cannot fail it; use it for container health checks and restart policies.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} map[string]string "alive"
+// @Router /livez [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "alive"})
+}
+
+// Ready handles GET /readyz - whether the gateway should receive traffic
+// @Summary Readiness probe
+// @Description Reports the cached result of the background dependency prober: per dependency its status, latency of the last ping and last success time. 503 when a critical dependency is down (status not_ready); 200 with status degraded when only non-critical ones are. Never waits on a database.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.ReadinessReport "ready or degraded"
+// @Failure 503 {object} models.ReadinessReport "not_ready"
+// @Router /readyz [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.healthService.Readiness()
+	c.JSON(readinessStatusCode(report), report)
+}
+
+// Check handles GET /health - readiness in the original response format
 // @Summary Health check
-// @Description Check the health status of the API and database connections
+// @Description Check the health status of the API and database connections. Same verdict as /readyz in the original healthy/degraded/unhealthy format, with the per-dependency detail under dependencies.
 // @Tags Health
 // @Accept json
 // @Produce json
-// @Success 200 {object} map[string]interface{} "API is healthy"
+// @Success 200 {object} map[string]interface{} "API is healthy or degraded"
 // @Failure 503 {object} map[string]interface{} "Service unavailable"
 // @Router /health [get]
 func (h *HealthHandler) Check(c *gin.Context) {
-	health := h.dbManager.HealthCheck()
+	report := h.healthService.Readiness()
 
-	// Determine overall status
-	allConnected := health.TicketDB == "connected" &&
-		health.MachineDB == "connected" &&
-		(health.TokenDB == "connected" || health.TokenDB == "not configured")
+	services := gin.H{}
+	for _, dep := range report.Dependencies {
+		key, ok := legacyHealthKeys[dep.Name]
+		if !ok {
+			continue
+		}
+		switch dep.Status {
+		case models.DependencyUp:
+			services[key] = "connected"
+		case models.DependencyNotConfigured:
+			services[key] = "not configured"
+		case models.DependencyUnknown:
+			services[key] = "not checked yet"
+		default:
+			services[key] = "disconnected: " + dep.Error
+		}
+	}
 
-	if !allConnected {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"status":   "unhealthy",
-			"message":  "One or more databases are unavailable",
-			"services": health,
-		})
-		return
+	status, message := "healthy", "API Gateway is running"
+	switch report.Status {
+	case models.ReadinessDegraded:
+		status, message = "degraded", "A non-critical dependency is unavailable"
+	case models.ReadinessNotReady:
+		status, message = "unhealthy", "One or more critical dependencies are unavailable"
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":   "healthy",
-		"message":  "API Gateway is running",
-		"services": health,
+	c.JSON(readinessStatusCode(report), gin.H{
+		"status":       status,
+		"message":      message,
+		"services":     services,
+		"dependencies": report.Dependencies,
 	})
 }
 
+// readinessStatusCode is 503 when the gateway is not ready and 200 otherwise.
+func readinessStatusCode(report *models.ReadinessReport) int {
+	if report.Status == models.ReadinessNotReady {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
 // Ping handles GET /ping - simple ping endpoint
 // @Summary Ping
 // @Description Simple ping endpoint to check if the API is running
@@ -0,0 +1,427 @@
+package handlers
+
+import (
+	"api-gateway/models"
+	"api-gateway/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// SLAHandler handles admin management of SLA policies and calendars.
+type SLAHandler struct {
+	service *service.SLAService
+	logger  *logrus.Logger
+}
+
+// NewSLAHandler creates a new SLAHandler instance.
+func NewSLAHandler(service *service.SLAService, logger *logrus.Logger) *SLAHandler {
+	return &SLAHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// respondSLAError maps SLA service errors to HTTP responses.
+func (h *SLAHandler) respondSLAError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    err.Error(),
+		})
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    err.Error(),
+		})
+	default:
+		h.logger.WithContext(c.Request.Context()).Errorf("Error trying to %s: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to " + action,
+		})
+	}
+}
+
+// ============================================================================
+// SLA Calendar Endpoints (admin)
+// ============================================================================
+
+// ListCalendars handles GET /api/v1/admin/sla/calendars
+// @Summary List SLA Calendars
+// @Description Get all business-hours calendars SLA policies can be counted in
+// @Tags SLA
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/sla/calendars [get]
+func (h *SLAHandler) ListCalendars(c *gin.Context) {
+	calendars, err := h.service.GetAllCalendars(c.Request.Context())
+	if err != nil {
+		h.respondSLAError(c, err, "list SLA calendars")
+		return
+	}
+
+	if calendars == nil {
+		calendars = []*models.SLACalendar{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SLA calendars retrieved successfully",
+		"data":    calendars,
+		"total":   len(calendars),
+	})
+}
+
+// GetCalendar handles GET /api/v1/admin/sla/calendars/:id
+// @Summary Get SLA Calendar
+// @Description Get a single SLA calendar
+// @Tags SLA
+// @Accept json
+// @Produce json
+// @Param id path int true "Calendar ID"
+// @Success 200 {object} models.SLACalendarResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/admin/sla/calendars/{id} [get]
+func (h *SLAHandler) GetCalendar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid calendar ID",
+		})
+		return
+	}
+
+	cal, err := h.service.GetCalendarByID(c.Request.Context(), id)
+	if err != nil {
+		h.respondSLAError(c, err, "get SLA calendar")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    cal,
+	})
+}
+
+// CreateCalendar handles POST /api/v1/admin/sla/calendars
+// @Summary Create SLA Calendar
+// @Description Create a business-hours calendar. timezone is an IANA zone name, work_days are 0 (Sunday) to 6 (Saturday), holidays are YYYY-MM-DD dates.
+// @Tags SLA
+// @Accept json
+// @Produce json
+// @Param calendar body models.SLACalendarRequest true "Calendar Details"
+// @Success 201 {object} models.SLACalendarResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/sla/calendars [post]
+func (h *SLAHandler) CreateCalendar(c *gin.Context) {
+	var req models.SLACalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	cal, err := h.service.CreateCalendar(c.Request.Context(), &req, adminID)
+	if err != nil {
+		h.respondSLAError(c, err, "create SLA calendar")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "SLA calendar created successfully",
+		"data":    cal,
+	})
+}
+
+// UpdateCalendar handles PUT /api/v1/admin/sla/calendars/:id
+// @Summary Update SLA Calendar
+// @Description Replace all fields of an SLA calendar
+// @Tags SLA
+// @Accept json
+// @Produce json
+// @Param id path int true "Calendar ID"
+// @Param calendar body models.SLACalendarRequest true "Calendar Details"
+// @Success 200 {object} models.SLACalendarResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/sla/calendars/{id} [put]
+func (h *SLAHandler) UpdateCalendar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid calendar ID",
+		})
+		return
+	}
+
+	var req models.SLACalendarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	cal, err := h.service.UpdateCalendar(c.Request.Context(), id, &req, adminID)
+	if err != nil {
+		h.respondSLAError(c, err, "update SLA calendar")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SLA calendar updated successfully",
+		"data":    cal,
+	})
+}
+
+// DeleteCalendar handles DELETE /api/v1/admin/sla/calendars/:id
+// @Summary Delete SLA Calendar
+// @Description Permanently delete an SLA calendar. Fails while a policy still uses it.
+// @Tags SLA
+// @Accept json
+// @Produce json
+// @Param id path int true "Calendar ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/sla/calendars/{id} [delete]
+func (h *SLAHandler) DeleteCalendar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid calendar ID",
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	if err := h.service.DeleteCalendar(c.Request.Context(), id, adminID); err != nil {
+		h.respondSLAError(c, err, "delete SLA calendar")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SLA calendar deleted successfully",
+	})
+}
+
+// ============================================================================
+// SLA Policy Endpoints (admin)
+// ============================================================================
+
+// ListPolicies handles GET /api/v1/admin/sla/policies
+// @Summary List SLA Policies
+// @Description Get all SLA policies used to compute sla_due_at / sla_breached on data rows
+// @Tags SLA
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/sla/policies [get]
+func (h *SLAHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.service.GetAllPolicies(c.Request.Context())
+	if err != nil {
+		h.respondSLAError(c, err, "list SLA policies")
+		return
+	}
+
+	if policies == nil {
+		policies = []*models.SLAPolicy{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SLA policies retrieved successfully",
+		"data":    policies,
+		"total":   len(policies),
+	})
+}
+
+// GetPolicy handles GET /api/v1/admin/sla/policies/:id
+// @Summary Get SLA Policy
+// @Description Get a single SLA policy
+// @Tags SLA
+// @Accept json
+// @Produce json
+// @Param id path int true "Policy ID"
+// @Success 200 {object} models.SLAPolicyResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/admin/sla/policies/{id} [get]
+func (h *SLAHandler) GetPolicy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid policy ID",
+		})
+		return
+	}
+
+	policy, err := h.service.GetPolicyByID(c.Request.Context(), id)
+	if err != nil {
+		h.respondSLAError(c, err, "get SLA policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    policy,
+	})
+}
+
+// CreatePolicy handles POST /api/v1/admin/sla/policies
+// @Summary Create SLA Policy
+// @Description Create an SLA policy for a ticket priority, optionally narrowed to one FLM vendor. Omit calendar_id to count targets around the clock.
+// @Tags SLA
+// @Accept json
+// @Produce json
+// @Param policy body models.SLAPolicyRequest true "Policy Details"
+// @Success 201 {object} models.SLAPolicyResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/sla/policies [post]
+func (h *SLAHandler) CreatePolicy(c *gin.Context) {
+	var req models.SLAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	policy, err := h.service.CreatePolicy(c.Request.Context(), &req, adminID)
+	if err != nil {
+		h.respondSLAError(c, err, "create SLA policy")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "SLA policy created successfully",
+		"data":    policy,
+	})
+}
+
+// UpdatePolicy handles PUT /api/v1/admin/sla/policies/:id
+// @Summary Update SLA Policy
+// @Description Replace all fields of an SLA policy
+// @Tags SLA
+// @Accept json
+// @Produce json
+// @Param id path int true "Policy ID"
+// @Param policy body models.SLAPolicyRequest true "Policy Details"
+// @Success 200 {object} models.SLAPolicyResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/sla/policies/{id} [put]
+func (h *SLAHandler) UpdatePolicy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid policy ID",
+		})
+		return
+	}
+
+	var req models.SLAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	policy, err := h.service.UpdatePolicy(c.Request.Context(), id, &req, adminID)
+	if err != nil {
+		h.respondSLAError(c, err, "update SLA policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SLA policy updated successfully",
+		"data":    policy,
+	})
+}
+
+// DeletePolicy handles DELETE /api/v1/admin/sla/policies/:id
+// @Summary Delete SLA Policy
+// @Description Permanently delete an SLA policy
+// @Tags SLA
+// @Accept json
+// @Produce json
+// @Param id path int true "Policy ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/sla/policies/{id} [delete]
+func (h *SLAHandler) DeletePolicy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid policy ID",
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	if err := h.service.DeletePolicy(c.Request.Context(), id, adminID); err != nil {
+		h.respondSLAError(c, err, "delete SLA policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "SLA policy deleted successfully",
+	})
+}
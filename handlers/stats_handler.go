@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"api-gateway/middleware"
+	"api-gateway/models"
+	"api-gateway/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// StatsHandler handles HTTP requests for the /api/v1/stats endpoints and the
+// admin criticality rule management endpoints.
+type StatsHandler struct {
+	service *service.StatsService
+	logger  *logrus.Logger
+}
+
+// NewStatsHandler creates a new StatsHandler instance.
+func NewStatsHandler(service *service.StatsService, logger *logrus.Logger) *StatsHandler {
+	return &StatsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetCritical handles GET /api/v1/stats/critical
+// @Summary Get critical terminals
+// @Description List open tickets that match at least one active criticality rule, longest-running first. Each row includes the terminal location and GPS from atmi. Vendor-scoped tokens only see terminals in their scope.
+// @Tags Stats
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.CriticalTerminalsResponse "Critical terminals retrieved successfully"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API token"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Failure 504 {object} models.ErrorResponse "Database query timed out"
+// @Deprecated
+// @Router /api/v1/stats/critical [get]
+func (h *StatsHandler) GetCritical(c *gin.Context) {
+	filter := vendorFilterFromContext(c)
+
+	terminals, err := h.service.GetCriticalTerminals(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Error fetching critical terminals: %v", err)
+		middleware.RecordError(c, err)
+		c.JSON(v1ErrorStatus(err, http.StatusInternalServerError), models.ErrorResponse{
+			Success:   false,
+			RequestID: c.GetString("request_id"),
+			Message:   "Failed to fetch critical terminals",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CriticalTerminalsResponse{
+		Success: true,
+		Message: "Critical terminals retrieved successfully",
+		Data:    terminals,
+		Total:   len(terminals),
+	})
+}
+
+// ============================================================================
+// Criticality Rule Endpoints (admin)
+// ============================================================================
+
+// ListRules handles GET /api/v1/admin/criticality-rules
+// @Summary List Criticality Rules
+// @Description Get all criticality rules used by GET /api/v1/stats/critical
+// @Tags Criticality Rules
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.CriticalityRuleListResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /api/v1/admin/criticality-rules [get]
+func (h *StatsHandler) ListRules(c *gin.Context) {
+	rules, err := h.service.GetAllRules(c.Request.Context())
+	if err != nil {
+		middleware.RecordError(c, err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Error listing criticality rules: %v", err)
+		c.JSON(v1ErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to list criticality rules",
+		})
+		return
+	}
+
+	if rules == nil {
+		rules = []*models.CriticalityRule{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Criticality rules retrieved successfully",
+		"data":    rules,
+		"total":   len(rules),
+	})
+}
+
+// GetRule handles GET /api/v1/admin/criticality-rules/:id
+// @Summary Get Criticality Rule
+// @Description Get a single criticality rule
+// @Tags Criticality Rules
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} models.CriticalityRuleResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /api/v1/admin/criticality-rules/{id} [get]
+func (h *StatsHandler) GetRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid rule ID",
+		})
+		return
+	}
+
+	rule, err := h.service.GetRuleByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success":    false,
+				"request_id": c.GetString("request_id"),
+				"message":    "Criticality rule not found",
+			})
+			return
+		}
+		middleware.RecordError(c, err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Error fetching criticality rule: %v", err)
+		c.JSON(v1ErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to fetch criticality rule",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// CreateRule handles POST /api/v1/admin/criticality-rules
+// @Summary Create Criticality Rule
+// @Description Create a criticality rule. At least one condition (priority, mode, status, min_tickets_duration, max_balance, region) is required.
+// @Tags Criticality Rules
+// @Accept json
+// @Produce json
+// @Param rule body models.CriticalityRuleRequest true "Rule Details"
+// @Success 201 {object} models.CriticalityRuleResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /api/v1/admin/criticality-rules [post]
+func (h *StatsHandler) CreateRule(c *gin.Context) {
+	var req models.CriticalityRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	rule, err := h.service.CreateRule(c.Request.Context(), &req, adminID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success":    false,
+				"request_id": c.GetString("request_id"),
+				"message":    "At least one rule condition is required",
+			})
+			return
+		}
+		middleware.RecordError(c, err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Error creating criticality rule: %v", err)
+		c.JSON(v1ErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to create criticality rule",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Criticality rule created successfully",
+		"data":    rule,
+	})
+}
+
+// UpdateRule handles PUT /api/v1/admin/criticality-rules/:id
+// @Summary Update Criticality Rule
+// @Description Replace all fields of a criticality rule
+// @Tags Criticality Rules
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Param rule body models.CriticalityRuleRequest true "Rule Details"
+// @Success 200 {object} models.CriticalityRuleResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /api/v1/admin/criticality-rules/{id} [put]
+func (h *StatsHandler) UpdateRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid rule ID",
+		})
+		return
+	}
+
+	var req models.CriticalityRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	rule, err := h.service.UpdateRule(c.Request.Context(), id, &req, adminID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success":    false,
+				"request_id": c.GetString("request_id"),
+				"message":    "At least one rule condition is required",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success":    false,
+				"request_id": c.GetString("request_id"),
+				"message":    "Criticality rule not found",
+			})
+			return
+		}
+		middleware.RecordError(c, err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Error updating criticality rule: %v", err)
+		c.JSON(v1ErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to update criticality rule",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Criticality rule updated successfully",
+		"data":    rule,
+	})
+}
+
+// DeleteRule handles DELETE /api/v1/admin/criticality-rules/:id
+// @Summary Delete Criticality Rule
+// @Description Permanently delete a criticality rule
+// @Tags Criticality Rules
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 504 {object} models.ErrorResponse
+// @Router /api/v1/admin/criticality-rules/{id} [delete]
+func (h *StatsHandler) DeleteRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid rule ID",
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	if err := h.service.DeleteRule(c.Request.Context(), id, adminID); err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success":    false,
+				"request_id": c.GetString("request_id"),
+				"message":    "Criticality rule not found",
+			})
+			return
+		}
+		middleware.RecordError(c, err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Error deleting criticality rule: %v", err)
+		c.JSON(v1ErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to delete criticality rule",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Criticality rule deleted successfully",
+	})
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"api-gateway/models"
+	"api-gateway/service"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// streamHeartbeat keeps idle connections open through proxies and load balancers.
+const streamHeartbeat = 15 * time.Second
+
+// StreamHandler serves the Server-Sent Events feed of ticket changes.
+type StreamHandler struct {
+	service *service.StreamService
+	logger  *logrus.Logger
+}
+
+// NewStreamHandler creates a new StreamHandler instance.
+func NewStreamHandler(service *service.StreamService, logger *logrus.Logger) *StreamHandler {
+	return &StreamHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Stream handles GET /api/v1/data/stream
+// @Summary Stream ticket changes
+// @Description Server-Sent Events feed of created, updated and closed tickets. Each event's data is a ChangeEvent; vendor-scoped tokens only receive events for rows inside their filter. Reconnect with the Last-Event-ID header (or last_event_id query parameter) to resume; a "reset" event means the missed events are no longer available and the client should refetch GET /data.
+// @Tags Data
+// @Produce text/event-stream
+// @Security ApiKeyAuth
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "Same as the Last-Event-ID header, for clients that cannot set headers"
+// @Param legacy_time_format query bool false "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339"
+// @Success 200 {object} models.ChangeEvent "Event stream"
+// @Failure 401 {object} models.ErrorResponse "Missing or invalid API token"
+// @Failure 429 {object} models.ErrorResponse "Rate limit exceeded"
+// @Router /api/v1/data/stream [get]
+// @Router /api/v2/data/stream [get]
+func (h *StreamHandler) Stream(c *gin.Context) {
+	filter := vendorFilterFromContext(c)
+	legacy := legacyTimeFormat(c)
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	sub, backlog, reset := h.service.Subscribe(filter, lastEventID)
+	defer h.service.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // disable nginx response buffering
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", 5000)
+	if reset {
+		fmt.Fprint(w, "event: reset\ndata: {\"message\":\"Missed events are no longer available; refetch /api/v1/data\"}\n\n")
+	}
+	for _, ev := range backlog {
+		if err := h.writeEvent(c.Request.Context(), w, ev, legacy); err != nil {
+			return
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case ev, ok := <-sub.Events:
+			if !ok {
+				// Dropped for falling behind, or shutting down; the client
+				// reconnects with Last-Event-ID
+				return
+			}
+			if err := h.writeEvent(c.Request.Context(), w, ev, legacy); err != nil {
+				return
+			}
+			w.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			w.Flush()
+		}
+	}
+}
+
+// writeEvent writes one change event in SSE wire format.
+func (h *StreamHandler) writeEvent(ctx context.Context, w io.Writer, ev *models.ChangeEvent, legacy bool) error {
+	if legacy && ev.Data != nil {
+		// Events are shared between subscribers; format a copy
+		row := *ev.Data
+		row.UseLegacyTimeFormat()
+		copied := *ev
+		copied.Data = &row
+		ev = &copied
+	}
+
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		h.logger.WithContext(ctx).Errorf("Failed to encode stream event %d: %v", ev.ID, err)
+		return nil
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, payload)
+	return err
+}
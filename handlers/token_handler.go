@@ -3,6 +3,7 @@ package handlers
 import (
 	"api-gateway/models"
 	"api-gateway/service"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -39,13 +40,14 @@ func NewTokenHandler(service *service.TokenService, logger *logrus.Logger) *Toke
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.LoginResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/auth/login [post]
+// @Router /api/v1/admin/auth/login [post]
 func (h *TokenHandler) Login(c *gin.Context) {
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid request data: " + err.Error(),
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid request data: " + err.Error(),
 		})
 		return
 	}
@@ -53,19 +55,20 @@ func (h *TokenHandler) Login(c *gin.Context) {
 	ipAddress := c.ClientIP()
 	userAgent := c.Request.UserAgent()
 
-	resp, err := h.service.Login(req.Username, req.Password, ipAddress, userAgent)
+	resp, err := h.service.Login(c.Request.Context(), req.Username, req.Password, ipAddress, userAgent)
 	if err != nil {
-		h.logger.Errorf("Login error: %v", err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Login error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Login failed",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Login failed",
 		})
 		return
 	}
 
 	if resp.Success {
-		// Set session cookie
-		c.SetCookie("session_token", resp.SessionToken, 86400, "/", "", false, true)
+		// Set session cookie, Secure over HTTPS
+		c.SetCookie("session_token", resp.SessionToken, 86400, "/", "", c.Request.TLS != nil, true)
 	}
 
 	status := http.StatusOK
@@ -83,7 +86,7 @@ func (h *TokenHandler) Login(c *gin.Context) {
 // @Produce json
 // @Param X-Session-Token header string false "Session Token"
 // @Success 200 {object} map[string]interface{}
-// @Router /admin/auth/logout [post]
+// @Router /api/v1/admin/auth/logout [post]
 func (h *TokenHandler) Logout(c *gin.Context) {
 	sessionToken := c.GetHeader("X-Session-Token")
 	if sessionToken == "" {
@@ -91,11 +94,11 @@ func (h *TokenHandler) Logout(c *gin.Context) {
 	}
 
 	if sessionToken != "" {
-		_ = h.service.Logout(sessionToken)
+		_ = h.service.Logout(c.Request.Context(), sessionToken)
 	}
 
 	// Clear cookie
-	c.SetCookie("session_token", "", -1, "/", "", false, true)
+	c.SetCookie("session_token", "", -1, "/", "", c.Request.TLS != nil, true)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -110,7 +113,7 @@ func (h *TokenHandler) Logout(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]interface{}
-// @Router /admin/auth/me [get]
+// @Router /api/v1/admin/auth/me [get]
 func (h *TokenHandler) GetCurrentUser(c *gin.Context) {
 	adminID, _ := c.Get("admin_id")
 	adminUsername, _ := c.Get("admin_username")
@@ -138,14 +141,15 @@ func (h *TokenHandler) GetCurrentUser(c *gin.Context) {
 // @Produce json
 // @Success 200 {object} models.TokenListResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/tokens [get]
+// @Router /api/v1/admin/tokens [get]
 func (h *TokenHandler) ListTokens(c *gin.Context) {
-	tokens, err := h.service.GetAllTokens()
+	tokens, err := h.service.GetAllTokens(c.Request.Context())
 	if err != nil {
-		h.logger.Errorf("Error listing tokens: %v", err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Error listing tokens: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to list tokens",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to list tokens",
 		})
 		return
 	}
@@ -172,25 +176,27 @@ func (h *TokenHandler) ListTokens(c *gin.Context) {
 // @Success 201 {object} models.CreateTokenResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/tokens [post]
+// @Router /api/v1/admin/tokens [post]
 func (h *TokenHandler) CreateToken(c *gin.Context) {
 	var req models.CreateTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid request data: " + err.Error(),
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid request data: " + err.Error(),
 		})
 		return
 	}
 
 	adminID := c.GetInt("admin_id")
 
-	token, err := h.service.CreateAPIToken(&req, adminID)
+	token, err := h.service.CreateAPIToken(c.Request.Context(), &req, adminID)
 	if err != nil {
-		h.logger.Errorf("Error creating token: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to create token: " + err.Error(),
+		h.logger.WithContext(c.Request.Context()).Errorf("Error creating token: %v", err)
+		c.JSON(service.ErrorStatus(err), gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to create token: " + err.Error(),
 		})
 		return
 	}
@@ -213,22 +219,24 @@ func (h *TokenHandler) CreateToken(c *gin.Context) {
 // @Success 200 {object} models.TokenResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
-// @Router /admin/tokens/{id} [get]
+// @Router /api/v1/admin/tokens/{id} [get]
 func (h *TokenHandler) GetToken(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid token ID",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid token ID",
 		})
 		return
 	}
 
-	token, err := h.service.GetTokenByID(id)
+	token, err := h.service.GetTokenByID(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
-			"success": false,
-			"message": "Token not found",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Token not found",
 		})
 		return
 	}
@@ -250,13 +258,14 @@ func (h *TokenHandler) GetToken(c *gin.Context) {
 // @Success 200 {object} models.TokenResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/tokens/{id} [put]
+// @Router /api/v1/admin/tokens/{id} [put]
 func (h *TokenHandler) UpdateToken(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid token ID",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid token ID",
 		})
 		return
 	}
@@ -264,20 +273,26 @@ func (h *TokenHandler) UpdateToken(c *gin.Context) {
 	var req models.UpdateTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid request data: " + err.Error(),
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid request data: " + err.Error(),
 		})
 		return
 	}
 
 	adminID := c.GetInt("admin_id")
 
-	token, err := h.service.UpdateToken(id, &req, adminID)
+	token, err := h.service.UpdateToken(c.Request.Context(), id, &req, adminID)
 	if err != nil {
-		h.logger.Errorf("Error updating token: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to update token",
+		h.logger.WithContext(c.Request.Context()).Errorf("Error updating token: %v", err)
+		status, message := service.ErrorStatus(err), "Failed to update token"
+		if errors.Is(err, service.ErrInvalidInput) {
+			message += ": " + err.Error()
+		}
+		c.JSON(status, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    message,
 		})
 		return
 	}
@@ -299,25 +314,27 @@ func (h *TokenHandler) UpdateToken(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/tokens/{id} [delete]
+// @Router /api/v1/admin/tokens/{id} [delete]
 func (h *TokenHandler) DeleteToken(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid token ID",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid token ID",
 		})
 		return
 	}
 
 	adminID := c.GetInt("admin_id")
 
-	err = h.service.DeleteToken(id, adminID)
+	err = h.service.DeleteToken(c.Request.Context(), id, adminID)
 	if err != nil {
-		h.logger.Errorf("Error deleting token: %v", err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Error deleting token: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to delete token",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to delete token",
 		})
 		return
 	}
@@ -338,24 +355,26 @@ func (h *TokenHandler) DeleteToken(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/tokens/{id}/disable [patch]
+// @Router /api/v1/admin/tokens/{id}/disable [patch]
 func (h *TokenHandler) DisableToken(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid token ID",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid token ID",
 		})
 		return
 	}
 
 	adminID := c.GetInt("admin_id")
 
-	err = h.service.DisableToken(id, adminID)
+	err = h.service.DisableToken(c.Request.Context(), id, adminID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to disable token",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to disable token",
 		})
 		return
 	}
@@ -376,24 +395,26 @@ func (h *TokenHandler) DisableToken(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/tokens/{id}/enable [patch]
+// @Router /api/v1/admin/tokens/{id}/enable [patch]
 func (h *TokenHandler) EnableToken(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid token ID",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid token ID",
 		})
 		return
 	}
 
 	adminID := c.GetInt("admin_id")
 
-	err = h.service.EnableToken(id, adminID)
+	err = h.service.EnableToken(c.Request.Context(), id, adminID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to enable token",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to enable token",
 		})
 		return
 	}
@@ -416,14 +437,15 @@ func (h *TokenHandler) EnableToken(c *gin.Context) {
 // @Produce json
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/analytics/dashboard [get]
+// @Router /api/v1/admin/analytics/dashboard [get]
 func (h *TokenHandler) GetDashboardStats(c *gin.Context) {
-	stats, err := h.service.GetDashboardStats()
+	stats, err := h.service.GetDashboardStats(c.Request.Context())
 	if err != nil {
-		h.logger.Errorf("Error getting dashboard stats: %v", err)
+		h.logger.WithContext(c.Request.Context()).Errorf("Error getting dashboard stats: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to get dashboard stats",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to get dashboard stats",
 		})
 		return
 	}
@@ -445,13 +467,14 @@ func (h *TokenHandler) GetDashboardStats(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/analytics/tokens/{id} [get]
+// @Router /api/v1/admin/analytics/tokens/{id} [get]
 func (h *TokenHandler) GetTokenAnalytics(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid token ID",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid token ID",
 		})
 		return
 	}
@@ -463,11 +486,12 @@ func (h *TokenHandler) GetTokenAnalytics(c *gin.Context) {
 		}
 	}
 
-	analytics, err := h.service.GetTokenAnalytics(id, days)
+	analytics, err := h.service.GetTokenAnalytics(c.Request.Context(), id, days)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to get token analytics",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to get token analytics",
 		})
 		return
 	}
@@ -488,7 +512,7 @@ func (h *TokenHandler) GetTokenAnalytics(c *gin.Context) {
 // @Param limit query int false "Limit results (default 20)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/analytics/endpoints [get]
+// @Router /api/v1/admin/analytics/endpoints [get]
 func (h *TokenHandler) GetEndpointStats(c *gin.Context) {
 	days := 7
 	if d := c.Query("days"); d != "" {
@@ -504,11 +528,12 @@ func (h *TokenHandler) GetEndpointStats(c *gin.Context) {
 		}
 	}
 
-	stats, err := h.service.GetEndpointStats(days, limit)
+	stats, err := h.service.GetEndpointStats(c.Request.Context(), days, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to get endpoint stats",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to get endpoint stats",
 		})
 		return
 	}
@@ -533,7 +558,7 @@ func (h *TokenHandler) GetEndpointStats(c *gin.Context) {
 // @Param token_id query int false "Filter by Token ID"
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/analytics/daily [get]
+// @Router /api/v1/admin/analytics/daily [get]
 func (h *TokenHandler) GetDailyUsage(c *gin.Context) {
 	days := 30
 	if d := c.Query("days"); d != "" {
@@ -549,11 +574,12 @@ func (h *TokenHandler) GetDailyUsage(c *gin.Context) {
 		}
 	}
 
-	usage, err := h.service.GetDailyUsage(tokenID, days)
+	usage, err := h.service.GetDailyUsage(c.Request.Context(), tokenID, days)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to get daily usage",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to get daily usage",
 		})
 		return
 	}
@@ -568,6 +594,90 @@ func (h *TokenHandler) GetDailyUsage(c *gin.Context) {
 	})
 }
 
+// GetErrorStats handles GET /api/v1/admin/analytics/errors
+// @Summary Get Error Stats
+// @Description Get failed request counts grouped by stable error code (e.g. rate_limited, out_of_scope)
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param days query int false "Number of days (default 7)"
+// @Param token_id query int false "Filter by Token ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/analytics/errors [get]
+func (h *TokenHandler) GetErrorStats(c *gin.Context) {
+	days := 7
+	if d := c.Query("days"); d != "" {
+		if parsed, err := strconv.Atoi(d); err == nil && parsed > 0 {
+			days = parsed
+		}
+	}
+
+	var tokenID *int
+	if t := c.Query("token_id"); t != "" {
+		if parsed, err := strconv.Atoi(t); err == nil {
+			tokenID = &parsed
+		}
+	}
+
+	stats, err := h.service.GetErrorStats(c.Request.Context(), tokenID, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to get error stats",
+		})
+		return
+	}
+
+	if stats == nil {
+		stats = []*models.ErrorCodeStats{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stats,
+	})
+}
+
+// GetAPIVersionUsage handles GET /api/v1/admin/analytics/api-versions
+// @Summary Get API Version Usage
+// @Description Get v1 and v2 request counts per token, so clients still calling the deprecated v1 API can be found. Tokens with v1 traffic are listed first.
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param days query int false "Number of days (default 30)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/analytics/api-versions [get]
+func (h *TokenHandler) GetAPIVersionUsage(c *gin.Context) {
+	days := 30
+	if d := c.Query("days"); d != "" {
+		if parsed, err := strconv.Atoi(d); err == nil && parsed > 0 {
+			days = parsed
+		}
+	}
+
+	usage, err := h.service.GetAPIVersionUsage(c.Request.Context(), days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to get API version usage",
+		})
+		return
+	}
+
+	if usage == nil {
+		usage = []*models.APIVersionUsage{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    usage,
+	})
+}
+
 // GetTokenUsageLogs handles GET /api/v1/admin/tokens/:id/logs
 // @Summary Get Token Logs
 // @Description Get access logs for a specific token
@@ -579,13 +689,14 @@ func (h *TokenHandler) GetDailyUsage(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/tokens/{id}/logs [get]
+// @Router /api/v1/admin/tokens/{id}/logs [get]
 func (h *TokenHandler) GetTokenUsageLogs(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"message": "Invalid token ID",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid token ID",
 		})
 		return
 	}
@@ -597,11 +708,12 @@ func (h *TokenHandler) GetTokenUsageLogs(c *gin.Context) {
 		}
 	}
 
-	logs, err := h.service.GetUsageLogsByTokenID(id, limit)
+	logs, err := h.service.GetUsageLogsByTokenID(c.Request.Context(), id, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to get usage logs",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to get usage logs",
 		})
 		return
 	}
@@ -626,7 +738,7 @@ func (h *TokenHandler) GetTokenUsageLogs(c *gin.Context) {
 // @Param limit query int false "Limit results (default 100)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} models.ErrorResponse
-// @Router /admin/audit-logs [get]
+// @Router /api/v1/admin/audit-logs [get]
 func (h *TokenHandler) GetAuditLogs(c *gin.Context) {
 	limit := 100
 	if l := c.Query("limit"); l != "" {
@@ -635,11 +747,12 @@ func (h *TokenHandler) GetAuditLogs(c *gin.Context) {
 		}
 	}
 
-	logs, err := h.service.GetAuditLogs(limit)
+	logs, err := h.service.GetAuditLogs(c.Request.Context(), limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"message": "Failed to get audit logs",
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to get audit logs",
 		})
 		return
 	}
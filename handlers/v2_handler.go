@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"api-gateway/middleware"
+	"api-gateway/models"
+	"api-gateway/repository"
+	"api-gateway/service"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// V2Handler serves the /api/v2 data and stats endpoints. They share the v1
+// services but answer with models.Envelope (data plus pagination meta) on
+// success and an RFC 7807 models.Problem on failure.
+type V2Handler struct {
+	dataService  *service.DataService
+	statsService *service.StatsService
+	logger       *logrus.Logger
+}
+
+// NewV2Handler creates a new V2Handler instance. statsService may be nil when
+// the token database is not configured.
+func NewV2Handler(dataService *service.DataService, statsService *service.StatsService, logger *logrus.Logger) *V2Handler {
+	return &V2Handler{
+		dataService:  dataService,
+		statsService: statsService,
+		logger:       logger,
+	}
+}
+
+// respondV2 writes a successful envelope.
+func respondV2(c *gin.Context, status int, data interface{}, meta *models.EnvelopeMeta) {
+	c.JSON(status, models.Envelope{Data: data, Meta: meta})
+}
+
+// respondV2Error writes a problem document for err; see middleware.AbortWithProblem.
+func respondV2Error(c *gin.Context, err error, detail string) {
+	middleware.AbortWithProblem(c, err, detail)
+}
+
+// ListData handles GET /api/v2/data
+// @Summary List data
+// @Description Joined ticket+machine rows, one page at a time. Same filters and sorting as v1 GET /data, but results are always paginated and the filters are not echoed back.
+// @Tags Data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param page query int false "Page number (default: 1)" minimum(1)
+// @Param page_size query int false "Items per page (default: 100, max: 500)" minimum(1) maximum(500)
+// @Param sort_by query string false "Sort field, as on v1"
+// @Param sort_order query string false "Sort direction: asc or desc (default: desc)"
+// @Param search query string false "Search by terminal_id or terminal_name (partial match)"
+// @Param status query string false "Filter by exact status value (e.g. 0.NEW)"
+// @Param mode query string false "Filter by exact mode value (e.g. Off-line)"
+// @Param priority query string false "Filter by exact priority value (e.g. 1.High)"
+// @Param sla_breached query bool false "Filter by computed SLA breach state (true or false)"
+// @Param legacy_time_format query bool false "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339"
+// @Success 200 {object} models.Envelope{data=[]models.DataRow,meta=models.EnvelopeMeta} "Page of rows"
+// @Failure 400 {object} models.Problem "invalid_request"
+// @Failure 401 {object} models.Problem "missing_token, invalid_token, token_revoked, token_expired or token_disabled"
+// @Failure 429 {object} models.Problem "rate_limited"
+// @Failure 500 {object} models.Problem "internal_error"
+// @Failure 504 {object} models.Problem "query_timeout"
+// @Router /api/v2/data [get]
+func (h *V2Handler) ListData(c *gin.Context) {
+	page := 1
+	if v := c.Query("page"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			respondV2Error(c, service.ErrInvalidInput, "page must be a positive integer")
+			return
+		}
+		page = n
+	}
+	pageSize := 100
+	if v := c.Query("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 500 {
+			respondV2Error(c, service.ErrInvalidInput, "page_size must be between 1 and 500")
+			return
+		}
+		pageSize = n
+	}
+
+	sortOrder := c.DefaultQuery("sort_order", "desc")
+	if sortOrder != "asc" && sortOrder != "desc" {
+		respondV2Error(c, service.ErrInvalidInput, "sort_order must be asc or desc")
+		return
+	}
+
+	var slaBreached *bool
+	if v := strings.TrimSpace(c.Query("sla_breached")); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			respondV2Error(c, service.ErrInvalidInput, "sla_breached must be true or false")
+			return
+		}
+		slaBreached = &b
+	}
+
+	params := repository.QueryParams{
+		Page:      page,
+		PageSize:  pageSize,
+		SortBy:    c.DefaultQuery("sort_by", "incident_start_datetime"),
+		SortOrder: sortOrder,
+		Search:    strings.TrimSpace(c.Query("search")),
+		Status:    strings.TrimSpace(c.Query("status")),
+		Mode:      strings.TrimSpace(c.Query("mode")),
+		Priority:  strings.TrimSpace(c.Query("priority")),
+	}
+
+	rows, total, err := h.dataService.GetAll(c.Request.Context(), vendorFilterFromContext(c), params, slaBreached)
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Error fetching data: %v", err)
+		respondV2Error(c, err, "Failed to fetch data")
+		return
+	}
+	if rows == nil {
+		rows = []*models.DataRow{}
+	}
+
+	if legacyTimeFormat(c) {
+		for _, row := range rows {
+			row.UseLegacyTimeFormat()
+		}
+	}
+
+	respondV2(c, http.StatusOK, rows, &models.EnvelopeMeta{
+		Pagination: models.NewPagination(page, pageSize, total),
+	})
+}
+
+// GetData handles GET /api/v2/data/:terminal_id
+// @Summary Get data by terminal ID
+// @Description A single joined row. Terminals outside a vendor token's scope are reported as not_found.
+// @Tags Data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param terminal_id path string true "Terminal ID"
+// @Param legacy_time_format query bool false "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339"
+// @Success 200 {object} models.Envelope{data=models.DataRow} "Row"
+// @Failure 404 {object} models.Problem "not_found"
+// @Router /api/v2/data/{terminal_id} [get]
+func (h *V2Handler) GetData(c *gin.Context) {
+	row, err := h.dataService.GetByTerminalID(c.Request.Context(), c.Param("terminal_id"), vendorFilterFromContext(c))
+	if err != nil {
+		if errors.Is(err, service.ErrNotFound) {
+			respondV2Error(c, err, "Terminal not found")
+			return
+		}
+		h.logger.WithContext(c.Request.Context()).Errorf("Error fetching data row: %v", err)
+		respondV2Error(c, err, "Failed to fetch data")
+		return
+	}
+
+	if legacyTimeFormat(c) {
+		row.UseLegacyTimeFormat()
+	}
+	respondV2(c, http.StatusOK, row, nil)
+}
+
+// UpdateData handles PUT /api/v2/data/:terminal_id
+// @Summary Update ticket fields
+// @Description Update ticket fields for a terminal and return the updated row. Vendor tokens get out_of_scope (403) for terminals outside their scope.
+// @Tags Data
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param terminal_id path string true "Terminal ID"
+// @Param body body models.DataUpdateRequest true "Fields to update"
+// @Param Idempotency-Key header string false "Unique key per intended update; a retry with the same key and body replays the stored response"
+// @Param legacy_time_format query bool false "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339"
+// @Success 200 {object} models.Envelope{data=models.DataRow} "Updated row"
+// @Failure 400 {object} models.Problem "invalid_request or no_fields_to_update"
+// @Failure 403 {object} models.Problem "out_of_scope"
+// @Failure 404 {object} models.Problem "not_found"
+// @Failure 409 {object} models.Problem "idempotency_in_progress"
+// @Failure 422 {object} models.Problem "idempotency_key_reused"
+// @Failure 500 {object} models.Problem "internal_error"
+// @Failure 504 {object} models.Problem "query_timeout"
+// @Router /api/v2/data/{terminal_id} [put]
+func (h *V2Handler) UpdateData(c *gin.Context) {
+	var req models.DataUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondV2Error(c, fmt.Errorf("%w: %v", service.ErrInvalidInput, err), "Invalid request body: "+err.Error())
+		return
+	}
+
+	row, err := h.dataService.Update(c.Request.Context(), c.Param("terminal_id"), &req, vendorFilterFromContext(c))
+	if err != nil {
+		if service.ErrorCode(err) == models.ErrCodeInternal {
+			h.logger.WithContext(c.Request.Context()).Errorf("Error updating data row: %v", err)
+			respondV2Error(c, err, "Failed to update")
+			return
+		}
+		respondV2Error(c, err, err.Error())
+		return
+	}
+
+	if legacyTimeFormat(c) {
+		row.UseLegacyTimeFormat()
+	}
+	respondV2(c, http.StatusOK, row, nil)
+}
+
+// GetByFLM handles GET /api/v2/data/by-flm
+// @Summary Get tickets grouped by FLM
+// @Description Open tickets grouped by machine FLM branch, largest group first.
+// @Tags Data
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Filter by exact status value (e.g. 0.NEW)"
+// @Param priority query string false "Filter by exact priority value (e.g. 1.High)"
+// @Param legacy_time_format query bool false "Emit incident_start_datetime, open_time and close_time exactly as stored instead of RFC 3339"
+// @Success 200 {object} models.Envelope{data=[]models.FLMTicketsGroup} "Groups"
+// @Failure 500 {object} models.Problem "internal_error"
+// @Failure 504 {object} models.Problem "query_timeout"
+// @Router /api/v2/data/by-flm [get]
+func (h *V2Handler) GetByFLM(c *gin.Context) {
+	groups, _, err := h.dataService.GetTicketsByFLM(c.Request.Context(), vendorFilterFromContext(c),
+		strings.TrimSpace(c.Query("status")), strings.TrimSpace(c.Query("priority")))
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Error fetching tickets by FLM: %v", err)
+		respondV2Error(c, err, "Failed to fetch tickets by FLM")
+		return
+	}
+	if groups == nil {
+		groups = []models.FLMTicketsGroup{}
+	}
+
+	if legacyTimeFormat(c) {
+		for _, group := range groups {
+			for _, ticket := range group.Tickets {
+				ticket.UseLegacyTimeFormat()
+			}
+		}
+	}
+	respondV2(c, http.StatusOK, groups, nil)
+}
+
+// GetMetadata handles GET /api/v2/data/metadata
+// @Summary Get field metadata
+// @Description All valid values for the status, mode and priority fields. Cached for 1 hour.
+// @Tags Data
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Envelope{data=models.FieldMetadata} "Metadata"
+// @Failure 500 {object} models.Problem "internal_error"
+// @Failure 504 {object} models.Problem "query_timeout"
+// @Router /api/v2/data/metadata [get]
+func (h *V2Handler) GetMetadata(c *gin.Context) {
+	metadata, err := h.dataService.GetMetadata(c.Request.Context())
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Error fetching metadata: %v", err)
+		respondV2Error(c, err, "Failed to fetch metadata")
+		return
+	}
+	respondV2(c, http.StatusOK, models.FieldMetadata{
+		Statuses:    metadata.Statuses,
+		Modes:       metadata.Modes,
+		Priorities:  metadata.Priorities,
+		LastUpdated: metadata.LastUpdated,
+	}, nil)
+}
+
+// GetCritical handles GET /api/v2/stats/critical
+// @Summary Get critical terminals
+// @Description Open tickets matching at least one active criticality rule, longest-running first.
+// @Tags Stats
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} models.Envelope{data=[]models.CriticalTerminal} "Critical terminals"
+// @Failure 500 {object} models.Problem "internal_error"
+// @Failure 504 {object} models.Problem "query_timeout"
+// @Failure 503 {object} models.Problem "service_unavailable"
+// @Router /api/v2/stats/critical [get]
+func (h *V2Handler) GetCritical(c *gin.Context) {
+	if h.statsService == nil {
+		respondV2Error(c, service.ErrTokenDBUnavailable, "Criticality rules are not configured")
+		return
+	}
+
+	terminals, err := h.statsService.GetCriticalTerminals(c.Request.Context(), vendorFilterFromContext(c))
+	if err != nil {
+		h.logger.WithContext(c.Request.Context()).Errorf("Error fetching critical terminals: %v", err)
+		respondV2Error(c, err, "Failed to fetch critical terminals")
+		return
+	}
+	if terminals == nil {
+		terminals = []models.CriticalTerminal{}
+	}
+	respondV2(c, http.StatusOK, terminals, nil)
+}
@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"api-gateway/models"
+	"api-gateway/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// WebhookHandler handles admin management of token webhook subscriptions
+// and the delivery outbox.
+type WebhookHandler struct {
+	service *service.WebhookService
+	logger  *logrus.Logger
+}
+
+// NewWebhookHandler creates a new WebhookHandler instance.
+func NewWebhookHandler(service *service.WebhookService, logger *logrus.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// respondWebhookError maps webhook service errors to HTTP responses.
+func (h *WebhookHandler) respondWebhookError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, service.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    err.Error(),
+		})
+	case errors.Is(err, service.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    err.Error(),
+		})
+	default:
+		h.logger.WithContext(c.Request.Context()).Errorf("Error trying to %s: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Failed to " + action,
+		})
+	}
+}
+
+// webhookPathIDs parses the :id (token) and :webhook_id path parameters.
+func webhookPathIDs(c *gin.Context) (tokenID, webhookID int, ok bool) {
+	tokenID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid token ID",
+		})
+		return 0, 0, false
+	}
+	if c.Param("webhook_id") == "" {
+		return tokenID, 0, true
+	}
+	webhookID, err = strconv.Atoi(c.Param("webhook_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid webhook ID",
+		})
+		return 0, 0, false
+	}
+	return tokenID, webhookID, true
+}
+
+// ListWebhooks handles GET /api/v1/admin/tokens/:id/webhooks
+// @Summary List Token Webhooks
+// @Description Get the webhook subscriptions of an API token
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Token ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/tokens/{id}/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	tokenID, _, ok := webhookPathIDs(c)
+	if !ok {
+		return
+	}
+
+	subs, err := h.service.GetSubscriptions(c.Request.Context(), tokenID)
+	if err != nil {
+		h.respondWebhookError(c, err, "list webhooks")
+		return
+	}
+
+	if subs == nil {
+		subs = []*models.WebhookSubscription{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Webhooks retrieved successfully",
+		"data":    subs,
+		"total":   len(subs),
+	})
+}
+
+// GetWebhook handles GET /api/v1/admin/tokens/:id/webhooks/:webhook_id
+// @Summary Get Token Webhook
+// @Description Get a single webhook subscription (the secret is never returned)
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Token ID"
+// @Param webhook_id path int true "Webhook ID"
+// @Success 200 {object} models.WebhookSubscriptionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/admin/tokens/{id}/webhooks/{webhook_id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	tokenID, webhookID, ok := webhookPathIDs(c)
+	if !ok {
+		return
+	}
+
+	sub, err := h.service.GetSubscription(tokenID, webhookID)
+	if err != nil {
+		h.respondWebhookError(c, err, "get webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WebhookSubscriptionResponse{
+		Success: true,
+		Message: "Webhook retrieved successfully",
+		Data:    sub,
+	})
+}
+
+// CreateWebhook handles POST /api/v1/admin/tokens/:id/webhooks
+// @Summary Create Token Webhook
+// @Description Subscribe a URL to ticket events (ticket.created, ticket.updated, ticket.closed; empty event_types = all). Only terminals inside the token's vendor scope are delivered. A signing secret is generated when none is given and is returned only in this response.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Token ID"
+// @Param webhook body models.WebhookSubscriptionRequest true "Webhook Details"
+// @Success 201 {object} models.WebhookSubscriptionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/tokens/{id}/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	tokenID, _, ok := webhookPathIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	sub, secret, err := h.service.CreateSubscription(c.Request.Context(), tokenID, &req, adminID)
+	if err != nil {
+		h.respondWebhookError(c, err, "create webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.WebhookSubscriptionResponse{
+		Success: true,
+		Message: "Webhook created successfully. Store the secret securely - it won't be shown again!",
+		Data:    sub,
+		Secret:  secret,
+	})
+}
+
+// UpdateWebhook handles PUT /api/v1/admin/tokens/:id/webhooks/:webhook_id
+// @Summary Update Token Webhook
+// @Description Replace a webhook's URL, event filter and status. Supplying a secret rotates it; omitting it keeps the current one.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Token ID"
+// @Param webhook_id path int true "Webhook ID"
+// @Param webhook body models.WebhookSubscriptionRequest true "Webhook Details"
+// @Success 200 {object} models.WebhookSubscriptionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/tokens/{id}/webhooks/{webhook_id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	tokenID, webhookID, ok := webhookPathIDs(c)
+	if !ok {
+		return
+	}
+
+	var req models.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid request data: " + err.Error(),
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	sub, err := h.service.UpdateSubscription(c.Request.Context(), tokenID, webhookID, &req, adminID)
+	if err != nil {
+		h.respondWebhookError(c, err, "update webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WebhookSubscriptionResponse{
+		Success: true,
+		Message: "Webhook updated successfully",
+		Data:    sub,
+	})
+}
+
+// DeleteWebhook handles DELETE /api/v1/admin/tokens/:id/webhooks/:webhook_id
+// @Summary Delete Token Webhook
+// @Description Permanently delete a webhook subscription and its queued deliveries
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Token ID"
+// @Param webhook_id path int true "Webhook ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/tokens/{id}/webhooks/{webhook_id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	tokenID, webhookID, ok := webhookPathIDs(c)
+	if !ok {
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	if err := h.service.DeleteSubscription(c.Request.Context(), tokenID, webhookID, adminID); err != nil {
+		h.respondWebhookError(c, err, "delete webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// ListDeliveries handles GET /api/v1/admin/tokens/:id/webhooks/:webhook_id/deliveries
+// @Summary List Webhook Deliveries
+// @Description Recent outbox rows of one webhook, newest first
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Token ID"
+// @Param webhook_id path int true "Webhook ID"
+// @Param status query string false "pending, delivered or dead"
+// @Param limit query int false "Max rows (default: 100, max: 1000)"
+// @Success 200 {object} models.WebhookDeliveryListResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/admin/tokens/{id}/webhooks/{webhook_id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	tokenID, webhookID, ok := webhookPathIDs(c)
+	if !ok {
+		return
+	}
+
+	status := c.Query("status")
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "status must be pending, delivered or dead",
+		})
+		return
+	}
+
+	deliveries, err := h.service.GetDeliveries(tokenID, webhookID, status, listLimit(c))
+	if err != nil {
+		h.respondWebhookError(c, err, "list webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WebhookDeliveryListResponse{
+		Success: true,
+		Message: "Webhook deliveries retrieved successfully",
+		Data:    deliveries,
+		Total:   len(deliveries),
+	})
+}
+
+// ListDeadLetters handles GET /api/v1/admin/webhooks/dead-letters
+// @Summary List Dead-Letter Deliveries
+// @Description Webhook deliveries that exhausted their retry attempts, across all tokens, newest first
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param limit query int false "Max rows (default: 100, max: 1000)"
+// @Success 200 {object} models.WebhookDeliveryListResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks/dead-letters [get]
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	deliveries, err := h.service.GetDeadLetters(listLimit(c))
+	if err != nil {
+		h.respondWebhookError(c, err, "list dead-letter deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.WebhookDeliveryListResponse{
+		Success: true,
+		Message: "Dead-letter deliveries retrieved successfully",
+		Data:    deliveries,
+		Total:   len(deliveries),
+	})
+}
+
+// RetryDelivery handles POST /api/v1/admin/webhooks/deliveries/:delivery_id/retry
+// @Summary Retry Webhook Delivery
+// @Description Put an undelivered (typically dead) delivery back in the queue with a fresh set of attempts
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param delivery_id path int true "Delivery ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /api/v1/admin/webhooks/deliveries/{delivery_id}/retry [post]
+func (h *WebhookHandler) RetryDelivery(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"request_id": c.GetString("request_id"),
+			"message":    "Invalid delivery ID",
+		})
+		return
+	}
+
+	adminID := c.GetInt("admin_id")
+
+	if err := h.service.RetryDelivery(c.Request.Context(), id, adminID); err != nil {
+		h.respondWebhookError(c, err, "retry webhook delivery")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Webhook delivery requeued",
+	})
+}
+
+// listLimit reads the limit query parameter (default 100, max 1000).
+func listLimit(c *gin.Context) int {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit < 1 {
+		limit = 100
+	}
+	if limit > 1000 {
+		limit = 1000
+	}
+	return limit
+}
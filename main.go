@@ -1,26 +1,61 @@
 package main
 
 import (
+	"api-gateway/certs"
 	"api-gateway/config"
 	"api-gateway/database"
+	"api-gateway/grpcserver"
 	"api-gateway/handlers"
+	"api-gateway/metrics"
 	"api-gateway/middleware"
+	"api-gateway/models"
 	"api-gateway/repository"
 	"api-gateway/routes"
 	"api-gateway/service"
+	"api-gateway/tracing"
+	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
+	_ "time/tzdata" // zone database for hosts without one (Windows, scratch images)
 
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
+//go:generate go tool swag init -g main.go -o docs --outputTypes go,json --exclude proto,templates,dist
+//go:generate go tool swag init -g docs/doc.go -o docs --outputTypes go,json --exclude proto,templates,dist --instanceName public --tags Data,Health
+
+// @title API Gateway for On-Premise to Cloud Communication
+// @version 1.0
+// @description This API Gateway provides a single unified endpoint /api/v1/data that joins ticket_master.dbo.open_ticket with machine_master.dbo.machine and applies vendor-scoped access control from the API token.
+// @description Admin endpoints (/admin/...) use the session from /admin/auth/login, sent as the session_token cookie or the X-Session-Token header.
+// @termsOfService http://swagger.io/terms/
+// @contact.name API Support
+// @contact.url http://www.example.com/support
+// @contact.email support@example.com
+// @license.name Proprietary
+// @license.url http://www.example.com/license
+// @BasePath /
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Token
+// @description API token for authentication. Send in the X-API-Token header. Required for all /api/v1/data endpoints.
 func main() {
 	logger := logrus.New()
 	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.AddHook(middleware.RequestIDHook{})
 	logger.SetOutput(os.Stdout)
 	logger.SetLevel(logrus.InfoLevel)
 
@@ -30,70 +65,423 @@ func main() {
 	if err != nil {
 		logger.Fatalf("Failed to load configuration: %v", err)
 	}
+	if cfg.File != "" {
+		logger.Infof("Configuration file %s loaded (environment variables take precedence)", cfg.File)
+	}
+	logLevel, _ := logrus.ParseLevel(cfg.Runtime.LogLevel) // checked by config.Load
+	logger.SetLevel(logLevel)
+	logger.WithField("config", cfg).Debug("Effective configuration (secrets redacted)")
 
 	gin.SetMode(cfg.Server.GinMode)
 
+	ticketLoc, err := time.LoadLocation(cfg.Server.TimeZone)
+	if err != nil {
+		logger.Fatalf("Invalid TIME_ZONE %q: %v", cfg.Server.TimeZone, err)
+	}
+	models.SetTicketLocation(ticketLoc)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		logger.Fatalf("Failed to set up tracing: %v", err)
+	}
+	if cfg.Tracing.Exporter != tracing.ExporterNone {
+		logger.Infof("Tracing enabled (exporter %s, sample ratio %g)", cfg.Tracing.Exporter, cfg.Tracing.SampleRatio)
+	}
+
 	dbManager := database.NewDBManager(
-		cfg.TicketDB.GetDSN(),
-		cfg.MachineDB.GetDSN(),
-		cfg.TokenDB.GetDSN(),
+		cfg.TicketDB,
+		cfg.MachineDB,
+		cfg.TokenDB,
 		logger,
 	)
-	defer dbManager.Close()
 
 	// Initialize unified data repository (uses ticket_master; cross-db JOIN to machine_master)
-	dataRepo := repository.NewDataRepository(dbManager.TicketDB, logger)
-	dataService := service.NewDataService(dataRepo, logger)
-	dataHandler := handlers.NewDataHandler(dataService, logger)
+	queryTimeouts := repository.Timeouts{
+		Read:  cfg.Query.ReadTimeout,
+		List:  cfg.Query.ListTimeout,
+		Write: cfg.Query.WriteTimeout,
+	}
+	dataRepo := repository.NewDataRepository(dbManager.TicketDB, queryTimeouts, logger)
 
-	healthHandler := handlers.NewHealthHandler(dbManager, logger)
+	// Dependency prober behind /readyz and /health; started with the other workers
+	critical := func(name string) bool { return slices.Contains(cfg.Health.Critical, name) }
+	healthService := service.NewHealthService([]service.HealthDependency{
+		{Name: "ticket_master", Critical: critical("ticket_master"), Ping: database.Pinger(dbManager.TicketDB)},
+		{Name: "machine_master", Critical: critical("machine_master"), Ping: database.Pinger(dbManager.MachineDB)},
+		{Name: "token_management", Critical: critical("token_management"), Ping: database.Pinger(dbManager.TokenDB)},
+	}, cfg.Health.Interval, cfg.Health.Timeout, logger)
+	for _, name := range cfg.Health.Critical {
+		if !slices.Contains([]string{"ticket_master", "machine_master", "token_management"}, name) {
+			logger.Warnf("Unknown dependency %q in HEALTH_CRITICAL_DEPENDENCIES", name)
+		}
+	}
+	healthHandler := handlers.NewHealthHandler(healthService, logger)
 
 	// Token management (optional — requires token DB)
 	var tokenHandler *handlers.TokenHandler
+	var statsHandler *handlers.StatsHandler
+	var slaHandler *handlers.SLAHandler
+	var webhookHandler *handlers.WebhookHandler
+	var cloudSyncHandler *handlers.CloudSyncHandler
 	var tokenService *service.TokenService
+	var usageLogService *service.UsageLogService
+	var slaService *service.SLAService
+	var webhookService *service.WebhookService
+	var cloudSyncService *service.CloudSyncService
+	var statsService *service.StatsService
+	var idempotencyService *service.IdempotencyService
+	var cloudClient *service.CloudAppClient
 
 	if dbManager.TokenDB != nil {
-		tokenRepo := repository.NewTokenRepository(dbManager.TokenDB, logger)
-		tokenService = service.NewTokenService(tokenRepo, logger)
+		tokenRepo := repository.NewTokenRepository(dbManager.TokenDB, queryTimeouts, logger)
+		usageLogService = service.NewUsageLogService(tokenRepo, cfg.UsageLog.QueueSize, cfg.UsageLog.BatchSize,
+			cfg.UsageLog.FlushInterval, cfg.UsageLog.SampleRate, logger)
+		var rateLimiter service.RateLimiter = service.NewMemoryRateLimiter()
+		if cfg.RateLimit.Backend == service.RateLimitBackendDatabase {
+			rateLimiter = service.NewDBRateLimiter(tokenRepo)
+		}
+		logger.Infof("Rate limits kept in %s", cfg.RateLimit.Backend)
+		tokenService = service.NewTokenService(tokenRepo, usageLogService, rateLimiter, logger)
 		tokenHandler = handlers.NewTokenHandler(tokenService, logger)
+
+		// Criticality rules live in the token DB; the feed itself reads ticket_master
+		criticalityRepo := repository.NewCriticalityRepository(dbManager.TokenDB, queryTimeouts, logger)
+		statsService = service.NewStatsService(dataRepo, criticalityRepo, tokenRepo, logger)
+		statsHandler = handlers.NewStatsHandler(statsService, logger)
+
+		// SLA policies/calendars also live in the token DB
+		slaRepo := repository.NewSLARepository(dbManager.TokenDB, queryTimeouts, logger)
+		slaService = service.NewSLAService(slaRepo, tokenRepo, logger)
+		slaHandler = handlers.NewSLAHandler(slaService, logger)
+
+		// Webhook subscriptions and their delivery outbox belong to tokens
+		webhookRepo := repository.NewWebhookRepository(dbManager.TokenDB, logger)
+		webhookService = service.NewWebhookService(webhookRepo, tokenRepo, cfg.Webhook.PollInterval, cfg.Webhook.Timeout, cfg.Webhook.MaxAttempts, logger)
+		webhookHandler = handlers.NewWebhookHandler(webhookService, logger)
+
+		// Cloud app sync queue; pushing and reconciliation need CLOUD_APP_URL
+		if cfg.CloudApp.URL != "" {
+			cloudClient, err = service.NewCloudAppClient(cfg.CloudApp.URL, cfg.CloudApp.APIKey.Value(), cfg.CloudApp.Timeout)
+			if err != nil {
+				logger.Fatalf("Invalid cloud app configuration: %v", err)
+			}
+		} else {
+			logger.Info("Cloud app sync disabled (CLOUD_APP_URL not set)")
+		}
+		cloudSyncRepo := repository.NewCloudSyncRepository(dbManager.TokenDB, logger)
+		cloudSyncService = service.NewCloudSyncService(cloudSyncRepo, dataRepo, tokenRepo, cloudClient,
+			cfg.CloudApp.PollInterval, cfg.CloudApp.ReconcileInterval, cfg.CloudApp.MaxAttempts, logger)
+		cloudSyncHandler = handlers.NewCloudSyncHandler(cloudSyncService, logger)
+
+		// Idempotency-Key responses are stored per token
+		idempotencyRepo := repository.NewIdempotencyRepository(dbManager.TokenDB, queryTimeouts, logger)
+		idempotencyService = service.NewIdempotencyService(idempotencyRepo, cfg.Idempotency.TTL, cfg.Idempotency.StaleAfter, logger)
 		logger.Info("Token management system initialized")
 	} else {
 		logger.Warn("Token management system not available (no database connection)")
 	}
 
+	// SLA fields stay null and no webhooks or cloud syncs are sent when the token DB is unavailable
+	dataService := service.NewDataService(dataRepo, slaService, webhookService, cloudSyncService, logger)
+	dataService.SetMetadataCacheTTL(cfg.Runtime.MetadataCacheTTL)
+	if tokenService != nil {
+		limits := cfg.Runtime.RateLimits
+		tokenService.SetDefaultRateLimits(limits.PerMinute, limits.PerHour, limits.PerDay)
+	}
+	dataHandler := handlers.NewDataHandler(dataService, logger)
+	v2Handler := handlers.NewV2Handler(dataService, statsService, logger)
+
+	// GraphQL reads machine records straight from machine_master when it is reachable
+	var machineService *service.MachineService
+	if dbManager.MachineDB != nil {
+		machineRepo := repository.NewMachineRepository(dbManager.MachineDB, queryTimeouts, logger)
+		machineService = service.NewMachineService(machineRepo, logger)
+	}
+	graphqlHandler, err := handlers.NewGraphQLHandler(dataService, machineService, statsService,
+		cfg.GraphQL.MaxDepth, cfg.GraphQL.MaxRows, logger)
+	if err != nil {
+		logger.Fatalf("Failed to initialize GraphQL: %v", err)
+	}
+
+	// One shared change detector feeds every /api/v1/data/stream subscriber
+	streamCtx, stopStream := context.WithCancel(context.Background())
+	streamService := service.NewStreamService(dataRepo, slaService, webhookService, cfg.Stream.PollInterval, cfg.Stream.HistorySize, logger)
+	streamHandler := handlers.NewStreamHandler(streamService, logger)
+
+	// Background workers share streamCtx; shutdown cancels it and waits for them
+	var workers sync.WaitGroup
+	startWorker := func(run func(context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(streamCtx)
+		}()
+	}
+	startWorker(streamService.Run)
+	startWorker(healthService.Run)
+	if webhookService != nil {
+		startWorker(webhookService.Run)
+	}
+	if cloudSyncService != nil {
+		startWorker(cloudSyncService.Run)
+	}
+	if idempotencyService != nil {
+		startWorker(idempotencyService.Run)
+	}
+
+	// The usage log writer is not one of the workers: it keeps running until the
+	// requests finishing during shutdown are logged, and DrainUsageLogs stops it
+	if usageLogService != nil {
+		go usageLogService.Run()
+	}
+
+	// HTTPS and gRPC over TLS when TLS_CERT_FILE is set; the certificate and
+	// client CAs are reloaded when their files change
+	var certReloader *certs.Reloader
+	if cfg.TLS.Enabled() {
+		certReloader, err = certs.NewReloader(cfg.TLS, logger)
+		if err != nil {
+			logger.Fatalf("Failed to load TLS certificate: %v", err)
+		}
+		startWorker(certReloader.Run)
+	}
+
 	router := gin.New()
+	router.Use(otelgin.Middleware(tracing.ServiceName))
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Metrics())
 	router.Use(gin.Recovery())
 	router.Use(middleware.Logger(logger))
-	router.Use(gzip.Gzip(gzip.DefaultCompression))
+	router.Use(gzip.Gzip(gzip.DefaultCompression, gzip.WithExcludedPaths([]string{"/api/v1/data/stream", "/api/v2/data/stream"})))
+	corsPolicy := middleware.NewCORSPolicy(cfg.Runtime.CORSOrigins)
+	router.Use(corsPolicy.Handler())
 
 	routes.SetupRoutes(
 		router,
 		dataHandler,
+		streamHandler,
 		healthHandler,
 		tokenHandler,
+		statsHandler,
+		slaHandler,
+		webhookHandler,
+		cloudSyncHandler,
+		graphqlHandler,
+		v2Handler,
 		tokenService,
-		cfg.Security.APIKey,
+		idempotencyService,
+		cfg.Security.APIKey.Value(),
+		cfg.API,
 	)
 
-	go func() {
-		quit := make(chan os.Signal, 1)
-		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-		<-quit
-		logger.Info("Shutting down API Gateway...")
-		if err := dbManager.Close(); err != nil {
-			logger.Errorf("Error during shutdown: %v", err)
+	// Prometheus metrics: alone on an internal port when METRICS_PORT is set,
+	// otherwise on the main port behind METRICS_TOKEN
+	metrics.RegisterDB(dbManager.TicketDB, "ticket_master")
+	metrics.RegisterDB(dbManager.MachineDB, "machine_master")
+	metrics.RegisterDB(dbManager.TokenDB, "token_management")
+	metricsToken := middleware.NewMetricsToken(cfg.Metrics.Token.Value())
+	var metricsServer *http.Server
+	switch {
+	case cfg.Metrics.Port != "":
+		metricsRouter := gin.New()
+		metricsRouter.Use(gin.Recovery())
+		if cfg.Metrics.Token != "" {
+			metricsRouter.Use(middleware.MetricsAuth(metricsToken))
 		}
-		os.Exit(0)
-	}()
+		metricsRouter.GET("/metrics", gin.WrapH(metrics.Handler()))
+		metricsServer = &http.Server{Addr: ":" + cfg.Metrics.Port, Handler: metricsRouter}
+		go func() {
+			logger.Infof("Metrics listening on :%s/metrics", cfg.Metrics.Port)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Errorf("Metrics server stopped: %v", err)
+			}
+		}()
+	case cfg.Metrics.Token != "":
+		router.GET("/metrics", middleware.MetricsAuth(metricsToken), gin.WrapH(metrics.Handler()))
+	default:
+		logger.Warn("Metrics disabled (set METRICS_PORT or METRICS_TOKEN to expose /metrics)")
+	}
+
+	// gRPC data service for internal consumers, on its own port
+	var grpcServer *grpc.Server
+	if cfg.Server.GRPCPort != "" {
+		lis, err := net.Listen("tcp", ":"+cfg.Server.GRPCPort)
+		if err != nil {
+			logger.Fatalf("Failed to listen on gRPC port %s: %v", cfg.Server.GRPCPort, err)
+		}
+		var opts []grpc.ServerOption
+		if certReloader != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(certReloader.TLSConfig("h2"))))
+		}
+		grpcServer = grpcserver.NewServer(dataService, streamService, tokenService, logger, opts...)
+		go func() {
+			logger.Infof("gRPC data service listening on :%s", cfg.Server.GRPCPort)
+			if err := grpcServer.Serve(lis); err != nil {
+				logger.Errorf("gRPC server stopped: %v", err)
+			}
+		}()
+	}
 
+	// No WriteTimeout: it would cut off /api/v1/data/stream connections
 	address := fmt.Sprintf(":%s", cfg.Server.Port)
-	logger.Infof("API Gateway listening on %s", address)
-	logger.Infof("Environment: %s", cfg.Server.GinMode)
-	if tokenHandler != nil {
-		logger.Infof("Admin Dashboard: http://localhost:%s/admin", cfg.Server.Port)
+	server := &http.Server{
+		Addr:              address,
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	if certReloader != nil {
+		server.TLSConfig = certReloader.TLSConfig("h2", "http/1.1")
 	}
+	// Shutdown only waits for idle connections; end the SSE streams so theirs become idle
+	server.RegisterOnShutdown(streamService.CloseSubscriptions)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logger.Infof("API Gateway listening on %s", address)
+		logger.Infof("Environment: %s", cfg.Server.GinMode)
+		scheme := "http"
+		if certReloader != nil {
+			scheme = "https"
+		}
+		if tokenHandler != nil {
+			logger.Infof("Admin Dashboard: %s://localhost:%s/admin", scheme, cfg.Server.Port)
+		}
+		var err error
+		if certReloader != nil {
+			// The certificate comes from server.TLSConfig
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			serverErr <- err
+		}
+	}()
+
+	// SIGHUP re-reads the environment, config file and secret files and applies
+	// the runtime settings and rotated secrets. A config that fails to load or
+	// validate leaves everything as is.
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			reloaded, err := config.Load()
+			if err != nil {
+				logger.Errorf("Configuration reload failed, keeping the current settings: %v", err)
+				continue
+			}
+			if changed := config.RestartRequired(cfg, reloaded); len(changed) > 0 {
+				logger.Warnf("Configuration changes to %s need a restart and were not applied", strings.Join(changed, ", "))
+			}
+			rt := reloaded.Runtime
+			logLevel, _ := logrus.ParseLevel(rt.LogLevel)
+			logger.SetLevel(logLevel)
+			corsPolicy.SetOrigins(rt.CORSOrigins)
+			dataService.SetMetadataCacheTTL(rt.MetadataCacheTTL)
+			if tokenService != nil {
+				tokenService.SetDefaultRateLimits(rt.RateLimits.PerMinute, rt.RateLimits.PerHour, rt.RateLimits.PerDay)
+			}
+			if err := dbManager.UpdatePasswords(reloaded.TicketDB, reloaded.MachineDB, reloaded.TokenDB); err != nil {
+				logger.Errorf("Database password rotation failed: %v", err)
+			}
+			if cloudClient != nil {
+				cloudClient.SetAPIKey(reloaded.CloudApp.APIKey.Value())
+			}
+			metricsToken.Set(reloaded.Metrics.Token.Value())
+			if certReloader != nil {
+				if err := certReloader.Reload(); err != nil {
+					logger.Errorf("TLS certificate reload failed, keeping the current certificate: %v", err)
+				}
+			}
+			logger.WithField("config", reloaded).Debug("Effective configuration (secrets redacted)")
+			logger.WithFields(logrus.Fields{
+				"log_level":          rt.LogLevel,
+				"cors_origins":       rt.CORSOrigins,
+				"rate_limits":        rt.RateLimits,
+				"metadata_cache_ttl": rt.MetadataCacheTTL.String(),
+			}).Info("Configuration reloaded")
+		}
+	}()
 
-	if err := router.Run(address); err != nil {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serverErr:
 		logger.Fatalf("Failed to start server: %v", err)
+	case sig := <-quit:
+		logger.Infof("Received %s, shutting down API Gateway (deadline %s)...", sig, cfg.Server.ShutdownTimeout)
+	}
+	signal.Stop(quit)
+	signal.Stop(reload)
+
+	// Stop accepting connections and let in-flight requests and RPCs finish,
+	// then stop the workers, drain usage-log writes, flush traces and only
+	// then close the pools. Everything shares one deadline.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	var servers sync.WaitGroup
+	servers.Add(1)
+	go func() {
+		defer servers.Done()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Errorf("HTTP requests still running at the shutdown deadline: %v", err)
+		}
+	}()
+	if grpcServer != nil {
+		servers.Add(1)
+		go func() {
+			defer servers.Done()
+			stopped := make(chan struct{})
+			go func() {
+				grpcServer.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-shutdownCtx.Done():
+				logger.Error("gRPC calls still running at the shutdown deadline")
+				grpcServer.Stop()
+			}
+		}()
+	}
+	if metricsServer != nil {
+		servers.Add(1)
+		go func() {
+			defer servers.Done()
+			_ = metricsServer.Shutdown(shutdownCtx)
+		}()
+	}
+	servers.Wait()
+
+	stopStream()
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		logger.Error("Background workers still running at the shutdown deadline")
+	}
+
+	if tokenService != nil {
+		if err := tokenService.DrainUsageLogs(shutdownCtx); err != nil {
+			logger.Errorf("Usage logs lost on shutdown: %v", err)
+		}
+	}
+
+	// Traces get their own short window so the spans of a late drain are not lost
+	traceCtx, cancelTraces := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(traceCtx); err != nil {
+		logger.Errorf("Error flushing traces: %v", err)
+	}
+	cancelTraces()
+
+	if err := dbManager.Close(); err != nil {
+		logger.Errorf("Error during shutdown: %v", err)
 	}
+	logger.Info("API Gateway stopped")
 }
@@ -0,0 +1,121 @@
+// Package metrics holds the gateway's Prometheus collectors. Everything is
+// registered on Registry, which GET /metrics serves in the Prometheus text
+// format alongside the Go runtime and process collectors.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "api_gateway"
+
+// Registry is the registry served at /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts finished HTTP requests. route is the gin route
+	// template (e.g. /api/v2/data/:terminal_id) so terminal IDs do not create
+	// series; token_environment is empty for unauthenticated requests.
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route template, method, status and token environment.",
+	}, []string{"route", "method", "status", "token_environment"})
+
+	// HTTPRequestDuration observes HTTP request latency with the labels of HTTPRequests.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route template, method, status and token environment.",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"route", "method", "status", "token_environment"})
+
+	// RateLimitRejections counts requests refused by a token's rate limits.
+	RateLimitRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limit_rejections_total",
+		Help:      "Requests rejected by token rate limits, by window (minute, hour or day).",
+	}, []string{"window"})
+
+	// AuthFailures counts failed token authentications. reason is the stable
+	// error code (missing_token, invalid_token, token_expired, ...).
+	AuthFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_failures_total",
+		Help:      "Failed API token authentications by reason.",
+	}, []string{"reason"})
+
+	// UsageLogWriteFailures counts token_usage_logs rows that could not be written.
+	UsageLogWriteFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "usage_log_write_failures_total",
+		Help:      "Token usage log rows that failed to be written.",
+	})
+
+	// UsageLogsDropped counts usage log rows that were never written: reason is
+	// sampled (left out by USAGE_LOG_SAMPLE_RATE) or queue_full (the writer fell
+	// behind). Token request counters still include them.
+	UsageLogsDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "usage_logs_dropped_total",
+		Help:      "Token usage log rows not written, by reason (sampled or queue_full).",
+	}, []string{"reason"})
+
+	// UsageLogQueueDepth is the number of usage log rows waiting to be written.
+	UsageLogQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "usage_log_queue_depth",
+		Help:      "Token usage log rows queued for the batch writer.",
+	})
+
+	// MetadataCacheLookups counts metadata cache lookups by result (hit or
+	// miss); the hit ratio is hits over all lookups.
+	MetadataCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "metadata_cache_lookups_total",
+		Help:      "Field metadata cache lookups by result (hit or miss).",
+	}, []string{"result"})
+
+	// DependencyUp is 1 while the health prober's last ping of a dependency
+	// succeeded and 0 after it failed.
+	DependencyUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "dependency_up",
+		Help:      "Whether the last health probe of a dependency succeeded (1) or failed (0).",
+	}, []string{"dependency"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		RateLimitRejections,
+		AuthFailures,
+		UsageLogWriteFailures,
+		UsageLogsDropped,
+		UsageLogQueueDepth,
+		MetadataCacheLookups,
+		DependencyUp,
+	)
+}
+
+// RegisterDB exports the connection pool statistics (sql.DB.Stats) of db as
+// go_sql_* series labelled db_name=name. A nil db is skipped.
+func RegisterDB(db *sql.DB, name string) {
+	if db == nil {
+		return
+	}
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves Registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
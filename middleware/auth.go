@@ -1,13 +1,24 @@
 package middleware
 
 import (
+	"api-gateway/metrics"
 	"api-gateway/service"
+	"crypto/x509"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// clientCertificate returns the verified TLS client certificate of r, or nil
+// when the connection is plain or no certificate was presented.
+func clientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
 // APIKeyAuth validates the API key in request headers
 // This middleware protects endpoints from unauthorized access
 // The API key should be sent in the "X-API-Key" header
@@ -25,9 +36,10 @@ func APIKeyAuth(expectedKey string) gin.HandlerFunc {
 		// Validate API key
 		if apiKey == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Missing API key",
-				"message": "Please provide X-API-Key header",
+				"success":    false,
+				"request_id": c.GetString("request_id"),
+				"error":      "Missing API key",
+				"message":    "Please provide X-API-Key header",
 			})
 			c.Abort()
 			return
@@ -35,9 +47,10 @@ func APIKeyAuth(expectedKey string) gin.HandlerFunc {
 
 		if apiKey != expectedKey {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Invalid API key",
-				"message": "The provided API key is not valid",
+				"success":    false,
+				"request_id": c.GetString("request_id"),
+				"error":      "Invalid API key",
+				"message":    "The provided API key is not valid",
 			})
 			c.Abort()
 			return
@@ -52,40 +65,40 @@ func APIKeyAuth(expectedKey string) gin.HandlerFunc {
 // Accepts X-API-Token header with tokens created via the admin dashboard.
 func CombinedAuth(tokenService *service.TokenService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		startTime := time.Now()
 		if tokenService == nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"success": false,
-				"error":   "Token service unavailable",
-				"message": "Token management system is not configured",
-			})
-			c.Abort()
+			abortWithError(c, http.StatusServiceUnavailable, service.ErrTokenDBUnavailable,
+				"Token service unavailable", "Token management system is not configured")
 			return
 		}
 
 		// Extract token from header
 		apiToken := c.GetHeader("X-API-Token")
 		if apiToken == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   "Missing authentication",
-				"message": "Please provide X-API-Token header",
-			})
-			c.Abort()
+			metrics.AuthFailures.WithLabelValues(service.ErrorCode(service.ErrMissingToken)).Inc()
+			abortWithError(c, http.StatusUnauthorized, service.ErrMissingToken,
+				"Missing authentication", "Please provide X-API-Token header")
 			return
 		}
 
 		// Validate token
-		token, err := tokenService.ValidateAPIToken(apiToken, c.ClientIP())
+		token, err := tokenService.ValidateAPIToken(c.Request.Context(), apiToken, c.ClientIP(), clientCertificate(c.Request))
+		if err != nil && service.ErrorStatus(err) >= http.StatusInternalServerError {
+			// The lookup failed (e.g. timed out); the token may well be valid
+			abortWithError(c, service.ErrorStatus(err), err,
+				err.Error(), "Token validation failed")
+			return
+		}
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"error":   err.Error(),
-				"message": "Invalid API token",
-			})
-			c.Abort()
+			metrics.AuthFailures.WithLabelValues(service.ErrorCode(err)).Inc()
+			abortWithError(c, http.StatusUnauthorized, err,
+				err.Error(), "Invalid API token")
 			return
 		}
 
+		// Labels the request metrics, including a 429 below
+		c.Set("token_environment", token.Environment)
+
 		// Check rate limits
 		rateLimits := map[string]int{
 			"minute": token.RateLimitPerMinute,
@@ -93,24 +106,17 @@ func CombinedAuth(tokenService *service.TokenService) gin.HandlerFunc {
 			"day":    token.RateLimitPerDay,
 		}
 
-		allowed, message, err := tokenService.CheckRateLimit(token.ID, rateLimits)
+		allowed, message, err := tokenService.CheckRateLimit(c.Request.Context(), token.ID, rateLimits)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"success": false,
-				"error":   err.Error(),
-				"message": "Rate limit check failed",
-			})
-			c.Abort()
+			abortWithError(c, service.ErrorStatus(err), err,
+				err.Error(), "Rate limit check failed")
 			return
 		}
 
 		if !allowed {
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"success": false,
-				"error":   "Please slow down your requests",
-				"message": message,
-			})
-			c.Abort()
+			abortWithError(c, http.StatusTooManyRequests, service.ErrRateLimited,
+				"Please slow down your requests", message)
+			logUsage(tokenService, token.ID, c, startTime, http.StatusTooManyRequests, message)
 			return
 		}
 
@@ -126,7 +132,6 @@ func CombinedAuth(tokenService *service.TokenService) gin.HandlerFunc {
 		c.Set("token_filter_value", token.FilterValue)
 
 		// Process request
-		startTime := time.Now()
 		c.Next()
 
 		// Log usage after request completes
@@ -1,18 +1,45 @@
 package middleware
 
 import (
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
+// CORSPolicy is the CORS middleware of the API. Its allowed origins come from
+// CORS_ALLOWED_ORIGINS and can be replaced while serving, on config reload.
+type CORSPolicy struct {
+	handler atomic.Pointer[gin.HandlerFunc]
+}
+
+// NewCORSPolicy creates a CORS policy allowing origins ("*" allows any)
+func NewCORSPolicy(origins []string) *CORSPolicy {
+	p := &CORSPolicy{}
+	p.SetOrigins(origins)
+	return p
+}
+
+// SetOrigins replaces the allowed origins for requests from now on
+func (p *CORSPolicy) SetOrigins(origins []string) {
+	handler := CORS(origins)
+	p.handler.Store(&handler)
+}
+
+// Handler returns the middleware; it always applies the latest origins
+func (p *CORSPolicy) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		(*p.handler.Load())(c)
+	}
+}
+
 // CORS returns a CORS middleware with secure defaults
 // Allows the cloud app to make cross-origin requests to this API
-func CORS() gin.HandlerFunc {
+func CORS(origins []string) gin.HandlerFunc {
 	config := cors.Config{
-		// Allow all origins in development, restrict in production
-		AllowOrigins: []string{"*"}, // TODO: Replace with actual cloud app URL in production
+		// Only the configured origins; "*" allows any, which suits development only
+		AllowOrigins: origins,
 
 		// Allow common HTTP methods
 		AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
@@ -26,10 +53,13 @@ func CORS() gin.HandlerFunc {
 			"X-API-Key",
 			"X-API-Token",
 			"X-Session-Token",
+			"X-Request-ID",
+			"traceparent",
+			"tracestate",
 		},
 
 		// Expose custom headers to the client
-		ExposeHeaders: []string{"Content-Length"},
+		ExposeHeaders: []string{"Content-Length", "X-Request-ID"},
 
 		// Allow credentials (cookies, authorization headers)
 		AllowCredentials: true,
@@ -0,0 +1,123 @@
+package middleware
+
+import (
+	"api-gateway/service"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxIdempotencyKeyLength matches idempotency_keys.idempotency_key
+const maxIdempotencyKeyLength = 255
+
+// Idempotency honours the Idempotency-Key header on mutating routes. The first
+// request with a key runs normally and its response is stored; a retry with
+// the same key and the same method, URL and body gets the stored response back
+// with an Idempotent-Replayed header instead of running again. Reusing a key
+// for a different request is rejected with 422, and retrying while the first
+// request is still running with 409.
+//
+// The request is cancelled once its key could be taken over by a retry, so a
+// slow update cannot be applied twice.
+//
+// Requests without the header, or when svc is nil, pass straight through. It
+// must run after CombinedAuth, since keys are scoped to the calling token.
+func Idempotency(svc *service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if svc == nil || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			abortWithError(c, http.StatusBadRequest, service.ErrInvalidInput,
+				"Invalid Idempotency-Key",
+				fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abortWithError(c, http.StatusBadRequest, service.ErrInvalidInput,
+				"Invalid request body", err.Error())
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		path := c.Request.URL.RequestURI()
+		rec, replay, err := svc.Begin(c.Request.Context(), c.GetInt("token_id"), key, c.Request.Method, path,
+			requestHash(c.Request.Method, path, body))
+		if err != nil {
+			status := service.ErrorStatus(err)
+			abortWithError(c, status, err, err.Error(), "Idempotency-Key could not be used")
+			return
+		}
+
+		if replay {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(rec.ResponseStatus, rec.ResponseContentType, rec.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		ctx, cancel := context.WithDeadline(c.Request.Context(), svc.Deadline(rec))
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		finished := false
+		defer func() {
+			// Free the key if the handler panicked so the client can retry
+			if !finished {
+				svc.Release(c.Request.Context(), rec)
+			}
+		}()
+
+		c.Next()
+		finished = true
+
+		// Server errors are not stored: the retry should get a fresh attempt
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			svc.Release(c.Request.Context(), rec)
+			return
+		}
+		svc.Complete(c.Request.Context(), rec, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+	}
+}
+
+// requestHash fingerprints a request so a reused key can be told apart from a
+// genuine retry.
+func requestHash(method, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bodyRecorder copies everything the handler writes so it can be stored.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
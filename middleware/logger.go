@@ -8,7 +8,7 @@ import (
 )
 
 // Logger creates a middleware that logs HTTP requests
-// Logs method, path, status code, latency, and client IP
+// Logs method, path, status code, latency, client IP and request ID
 func Logger(logger *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Start timer
@@ -33,7 +33,7 @@ func Logger(logger *logrus.Logger) gin.HandlerFunc {
 			"path":    path,
 			"ip":      clientIP,
 			"latency": latency,
-		})
+		}).WithContext(c.Request.Context())
 
 		// Log based on status code
 		switch {
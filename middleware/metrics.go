@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"api-gateway/metrics"
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records the count and latency of every request by route template,
+// method, status and the environment of the calling token (empty when the
+// route is not token-authenticated or authentication failed).
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startTime := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched" // keep unknown paths from creating series
+		}
+		labels := []string{route, c.Request.Method, strconv.Itoa(c.Writer.Status()), c.GetString("token_environment")}
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(labels...).Observe(time.Since(startTime).Seconds())
+	}
+}
+
+// MetricsToken is the bearer token MetricsAuth expects. Set replaces it, e.g.
+// when METRICS_TOKEN_FILE changes and the config is reloaded.
+type MetricsToken struct {
+	expected atomic.Pointer[[]byte]
+}
+
+// NewMetricsToken creates a MetricsToken holding token
+func NewMetricsToken(token string) *MetricsToken {
+	t := &MetricsToken{}
+	t.Set(token)
+	return t
+}
+
+// Set replaces the token for requests from now on
+func (t *MetricsToken) Set(token string) {
+	expected := []byte("Bearer " + token)
+	t.expected.Store(&expected)
+}
+
+// MetricsAuth protects /metrics with a bearer token (Authorization: Bearer
+// <token>), for when it is served on the public port.
+func MetricsAuth(token *MetricsToken) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), *token.expected.Load()) != 1 {
+			c.Header("WWW-Authenticate", `Bearer realm="metrics"`)
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"api-gateway/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RecordError stores err's stable code and message on the context, where
+// the usage logger picks them up for token_usage_logs.
+func RecordError(c *gin.Context, err error) {
+	c.Set("error_code", service.ErrorCode(err))
+	c.Set("error_message", err.Error())
+}
+
+// AbortWithProblem ends the request with an RFC 7807 problem document whose
+// status and code are derived from err (see service.ErrorCode). detail is
+// shown to the client; for errors that match no sentinel it should not expose
+// the underlying cause.
+func AbortWithProblem(c *gin.Context, err error, detail string) {
+	RecordError(c, err)
+	status := service.ErrorStatus(err)
+	title := http.StatusText(status)
+	if status == service.StatusClientClosedRequest {
+		title = "Client Closed Request"
+	}
+	c.Header("Content-Type", models.ProblemContentType)
+	c.AbortWithStatusJSON(status, models.Problem{
+		Type:      "about:blank",
+		Title:     title,
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      service.ErrorCode(err),
+		RequestID: c.GetString("request_id"),
+	})
+}
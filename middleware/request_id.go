@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"api-gateway/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID accepts the client's X-Request-ID (or generates one when it is
+// missing or unusable) and echoes it in the response headers. The ID is stored
+// under the "request_id" context key and in the request context, where the
+// usage logger, audit logging and RequestIDHook pick it up. Only the tracing
+// middleware may run before it, so every log line and error body written by
+// later middleware and handlers can carry the ID.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := service.NewRequestID(c.GetHeader(RequestIDHeader))
+		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(service.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// RequestIDHook adds a request_id field to log entries created with
+// WithContext on a request's context.
+type RequestIDHook struct{}
+
+// Levels returns every level; the request ID is useful on all of them.
+func (RequestIDHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire sets request_id when the entry's context carries one.
+func (RequestIDHook) Fire(entry *logrus.Entry) error {
+	if id := service.RequestIDFromContext(entry.Context); id != "" {
+		entry.Data["request_id"] = id
+	}
+	return nil
+}
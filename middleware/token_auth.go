@@ -8,7 +8,6 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 // TokenAuthMiddleware validates API tokens and logs usage
@@ -20,9 +19,10 @@ func TokenAuthMiddleware(tokenService *service.TokenService) gin.HandlerFunc {
 		tokenValue := c.GetHeader("X-API-Token")
 		if tokenValue == "" {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Success: false,
-				Message: "Missing API token",
-				Error:   "X-API-Token header is required",
+				Success:   false,
+				RequestID: c.GetString("request_id"),
+				Message:   "Missing API token",
+				Error:     "X-API-Token header is required",
 			})
 			c.Abort()
 			return
@@ -32,12 +32,13 @@ func TokenAuthMiddleware(tokenService *service.TokenService) gin.HandlerFunc {
 		clientIP := c.ClientIP()
 
 		// Validate token
-		token, err := tokenService.ValidateAPIToken(tokenValue, clientIP)
+		token, err := tokenService.ValidateAPIToken(c.Request.Context(), tokenValue, clientIP, clientCertificate(c.Request))
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
-				Success: false,
-				Message: "Invalid API token",
-				Error:   err.Error(),
+				Success:   false,
+				RequestID: c.GetString("request_id"),
+				Message:   "Invalid API token",
+				Error:     err.Error(),
 			})
 			c.Abort()
 
@@ -53,12 +54,13 @@ func TokenAuthMiddleware(tokenService *service.TokenService) gin.HandlerFunc {
 			"day":    token.RateLimitPerDay,
 		}
 
-		allowed, message, err := tokenService.CheckRateLimit(token.ID, rateLimits)
+		allowed, message, err := tokenService.CheckRateLimit(c.Request.Context(), token.ID, rateLimits)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Success: false,
-				Message: "Rate limit check failed",
-				Error:   err.Error(),
+				Success:   false,
+				RequestID: c.GetString("request_id"),
+				Message:   "Rate limit check failed",
+				Error:     err.Error(),
 			})
 			c.Abort()
 			return
@@ -66,9 +68,10 @@ func TokenAuthMiddleware(tokenService *service.TokenService) gin.HandlerFunc {
 
 		if !allowed {
 			c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
-				Success: false,
-				Message: message,
-				Error:   "Please slow down your requests",
+				Success:   false,
+				RequestID: c.GetString("request_id"),
+				Message:   message,
+				Error:     "Please slow down your requests",
 			})
 			c.Abort()
 
@@ -108,19 +111,21 @@ func AdminAuthMiddleware(tokenService *service.TokenService) gin.HandlerFunc {
 
 		if sessionToken == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Authentication required",
+				"success":    false,
+				"request_id": c.GetString("request_id"),
+				"message":    "Authentication required",
 			})
 			c.Abort()
 			return
 		}
 
 		// Validate session
-		admin, err := tokenService.ValidateSession(sessionToken)
+		admin, err := tokenService.ValidateSession(c.Request.Context(), sessionToken)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Invalid or expired session",
+				"success":    false,
+				"request_id": c.GetString("request_id"),
+				"message":    "Invalid or expired session",
 			})
 			c.Abort()
 			return
@@ -141,8 +146,9 @@ func RequireRole(allowedRoles ...string) gin.HandlerFunc {
 		adminRole, exists := c.Get("admin_role")
 		if !exists {
 			c.JSON(http.StatusForbidden, gin.H{
-				"success": false,
-				"message": "Access denied",
+				"success":    false,
+				"request_id": c.GetString("request_id"),
+				"message":    "Access denied",
 			})
 			c.Abort()
 			return
@@ -159,8 +165,9 @@ func RequireRole(allowedRoles ...string) gin.HandlerFunc {
 
 		if !allowed {
 			c.JSON(http.StatusForbidden, gin.H{
-				"success": false,
-				"message": "Insufficient permissions",
+				"success":    false,
+				"request_id": c.GetString("request_id"),
+				"message":    "Insufficient permissions",
 			})
 			c.Abort()
 			return
@@ -172,10 +179,10 @@ func RequireRole(allowedRoles ...string) gin.HandlerFunc {
 
 // logUsage creates a usage log entry
 func logUsage(tokenService *service.TokenService, tokenID int, c *gin.Context, startTime time.Time, statusCode int, errorMsg string) {
-	// Generate request ID if not exists
-	requestID := c.GetHeader("X-Request-ID")
+	// Set by the RequestID middleware; generated here only if it did not run
+	requestID := c.GetString("request_id")
 	if requestID == "" {
-		requestID = uuid.New().String()
+		requestID = service.NewRequestID(c.GetHeader(RequestIDHeader))
 	}
 
 	// Calculate response time
@@ -193,11 +200,16 @@ func logUsage(tokenService *service.TokenService, tokenID int, c *gin.Context, s
 		UserAgent:      c.Request.UserAgent(),
 		Referer:        c.Request.Referer(),
 		RequestID:      requestID,
+		APIVersion:     c.GetString("api_version"),
 		ErrorMessage:   errorMsg,
+		ErrorCode:      c.GetString("error_code"),
+	}
+	if log.ErrorMessage == "" {
+		log.ErrorMessage = c.GetString("error_message")
 	}
 
-	// Log asynchronously to avoid blocking request
-	go tokenService.LogTokenUsage(log)
+	// Queued for the batch writer to avoid blocking the request
+	tokenService.LogTokenUsage(log)
 }
 
 // CORSForAdmin configures CORS for admin dashboard
@@ -223,9 +235,10 @@ func ScopeChecker(requiredScopes ...string) gin.HandlerFunc {
 		tokenScopes, exists := c.Get("token_scopes")
 		if !exists {
 			c.JSON(http.StatusForbidden, models.ErrorResponse{
-				Success: false,
-				Message: "Access denied",
-				Error:   "No scopes found for token",
+				Success:   false,
+				RequestID: c.GetString("request_id"),
+				Message:   "Access denied",
+				Error:     "No scopes found for token",
 			})
 			c.Abort()
 			return
@@ -244,9 +257,10 @@ func ScopeChecker(requiredScopes ...string) gin.HandlerFunc {
 		for _, required := range requiredScopes {
 			if !strings.Contains(scopesJSON, required) {
 				c.JSON(http.StatusForbidden, models.ErrorResponse{
-					Success: false,
-					Message: "Insufficient permissions",
-					Error:   "Token does not have required scope: " + required,
+					Success:   false,
+					RequestID: c.GetString("request_id"),
+					Message:   "Insufficient permissions",
+					Error:     "Token does not have required scope: " + required,
 				})
 				c.Abort()
 				return
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"api-gateway/models"
+	"api-gateway/service"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API versions stored under the "api_version" context key and in
+// token_usage_logs.api_version
+const (
+	APIVersionV1 = "v1"
+	APIVersionV2 = "v2"
+)
+
+// APIVersion tags requests with the API version of their route group. It must
+// run before CombinedAuth so auth errors use the version's response shape.
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("api_version", version)
+		c.Next()
+	}
+}
+
+// Deprecated marks every response of the group as deprecated: a Deprecation
+// header (RFC 9745) with the deprecation date, a Sunset header (RFC 8594) with
+// the date the version stops working, and a successor-version link to the same
+// path under successorPrefix. Zero dates leave their header out.
+func Deprecated(deprecatedAt, sunset time.Time, prefix, successorPrefix string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !deprecatedAt.IsZero() {
+			c.Header("Deprecation", fmt.Sprintf("@%d", deprecatedAt.Unix()))
+		}
+		if !sunset.IsZero() {
+			c.Header("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		if rest, ok := strings.CutPrefix(c.Request.URL.Path, prefix); ok {
+			c.Header("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successorPrefix, rest))
+		}
+		c.Next()
+	}
+}
+
+// abortWithError ends the request with an auth-level error in the shape of the
+// request's API version: a problem document on v2, or the v1
+// success/error/message body with v1Status. Both record err's code.
+func abortWithError(c *gin.Context, v1Status int, err error, errMsg, message string) {
+	if c.GetString("api_version") == APIVersionV2 {
+		detail := errMsg
+		if service.ErrorCode(err) == models.ErrCodeInternal {
+			detail = message // never expose unexpected errors
+		}
+		AbortWithProblem(c, err, detail)
+		return
+	}
+	RecordError(c, err)
+	c.AbortWithStatusJSON(v1Status, gin.H{
+		"success":    false,
+		"request_id": c.GetString("request_id"),
+		"error":      errMsg,
+		"message":    message,
+	})
+}
@@ -32,6 +32,7 @@ type TicketStatistics struct {
 	ByMode     []ModeCount     `json:"by_mode"`
 	AvgDuration float64        `json:"avg_duration_minutes" example:"125.5"`
 	TotalCount  int            `json:"total_count" example:"45"`
+	SLABreached int            `json:"sla_breached_count" example:"3"` // open tickets past their SLA due time
 }
 
 // MachineStatistics provides machine-related statistics
@@ -191,10 +192,11 @@ type AreaStats struct {
 
 // TicketsByFLMResponse provides tickets grouped by FLM
 type TicketsByFLMResponse struct {
-	Success bool              `json:"success" example:"true"`
-	Message string            `json:"message" example:"Tickets by FLM retrieved successfully"`
-	Data    []FLMTicketsGroup `json:"data"`
-	Total   int               `json:"total" example:"58"`
+	Success      bool              `json:"success" example:"true"`
+	Message      string            `json:"message" example:"Tickets by FLM retrieved successfully"`
+	Data         []FLMTicketsGroup `json:"data"`
+	Total        int               `json:"total" example:"58"`          // Number of FLM groups
+	TotalTickets int               `json:"total_tickets" example:"312"` // Number of tickets across all groups
 }
 
 // FLMTicketsGroup groups tickets by FLM provider
@@ -226,4 +228,5 @@ type CriticalTerminal struct {
 	FLM             string  `json:"flm" example:"AVT - CIDENG"`
 	SLM             string  `json:"slm" example:"KGP - WINCOR DW"`
 	GPS             string  `json:"gps" example:"-6.200000,106.816666"`
+	MatchedRules    []string `json:"matched_rules" example:"High priority off-line"` // Names of the criticality rules that matched
 }
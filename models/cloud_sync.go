@@ -0,0 +1,211 @@
+package models
+
+import "time"
+
+// Cloud sync queue statuses
+const (
+	CloudSyncPending    = "pending"
+	CloudSyncSynced     = "synced"
+	CloudSyncFailed     = "failed"     // attempts exhausted; listed as a failure
+	CloudSyncSuperseded = "superseded" // replaced by a newer snapshot of the same terminal
+)
+
+// Cloud sync queue sources
+const (
+	CloudSyncSourceUpdate    = "update"    // PUT /api/v1/data/:terminal_id
+	CloudSyncSourceReconcile = "reconcile" // repair queued by a reconciliation run
+)
+
+// Reconciliation difference kinds
+const (
+	CloudDiffMismatch      = "mismatch"
+	CloudDiffMissingRemote = "missing_remote"
+	CloudDiffExtraRemote   = "extra_remote"
+)
+
+// ============================================================================
+// Cloud App Wire Format
+// ============================================================================
+
+// CloudTicket is the ticket representation exchanged with the cloud app:
+// the body of PUT {CLOUD_APP_URL}/tickets/{terminal_id} and the items of
+// GET {CLOUD_APP_URL}/tickets.
+type CloudTicket struct {
+	TerminalID     string `json:"terminal_id" example:"ATM-001"`
+	TerminalName   string `json:"terminal_name" example:"Main Branch ATM"`
+	Priority       string `json:"priority" example:"1.High"`
+	Mode           string `json:"mode" example:"Off-line"`
+	Status         string `json:"status" example:"2.Kirim FLM"`
+	CurrentProblem string `json:"current_problem" example:"Card reader error"`
+	Remarks        string `json:"remarks" example:"Technician dispatched"`
+	Condition      string `json:"condition" example:"Normal"`
+	CloseTime      string `json:"close_time,omitempty" example:"2024-01-15T18:00:00+07:00"` // RFC 3339
+	ProblemHistory string `json:"problem_history" example:"Card reader issue resolved"`
+	ModeHistory    string `json:"mode_history" example:"Online->Offline->Online"`
+	UpdatedAt      string `json:"updated_at,omitempty" example:"2024-01-15T10:30:00+07:00"` // when the gateway queued the snapshot
+}
+
+// NewCloudTicket builds the cloud app representation of a data row.
+func NewCloudTicket(row *DataRow) *CloudTicket {
+	t := &CloudTicket{
+		TerminalID:     row.TerminalID,
+		TerminalName:   row.TerminalName,
+		Priority:       row.Priority.String,
+		Mode:           row.Mode.String,
+		Status:         row.Status.String,
+		CurrentProblem: row.CurrentProblem.String,
+		Remarks:        row.Remarks.String,
+		Condition:      row.Condition.String,
+		ProblemHistory: row.ProblemHistory.String,
+		ModeHistory:    row.ModeHistory.String,
+	}
+	if row.CloseTime.Valid {
+		t.CloseTime = row.CloseTime.Time.Format(time.RFC3339)
+	}
+	return t
+}
+
+// DiffFields returns the names of the synchronised fields that differ between
+// the two tickets. Close times are compared as instants.
+func (t *CloudTicket) DiffFields(other *CloudTicket) []string {
+	var fields []string
+	pairs := []struct {
+		name string
+		a, b string
+	}{
+		{"priority", t.Priority, other.Priority},
+		{"mode", t.Mode, other.Mode},
+		{"status", t.Status, other.Status},
+		{"current_problem", t.CurrentProblem, other.CurrentProblem},
+		{"remarks", t.Remarks, other.Remarks},
+		{"condition", t.Condition, other.Condition},
+		{"problem_history", t.ProblemHistory, other.ProblemHistory},
+		{"mode_history", t.ModeHistory, other.ModeHistory},
+	}
+	for _, p := range pairs {
+		if p.a != p.b {
+			fields = append(fields, p.name)
+		}
+	}
+	if !sameCloseTime(t.CloseTime, other.CloseTime) {
+		fields = append(fields, "close_time")
+	}
+	return fields
+}
+
+// sameCloseTime compares two close times, falling back to string equality
+// when either side does not parse.
+func sameCloseTime(a, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+	ta, okA := ParseTicketTime(a, nil)
+	tb, okB := ParseTicketTime(b, nil)
+	if !okA || !okB {
+		return a == b
+	}
+	return ta.Equal(tb)
+}
+
+// CloudTicketListResponse is the body of GET {CLOUD_APP_URL}/tickets
+type CloudTicketListResponse struct {
+	Data []*CloudTicket `json:"data"`
+}
+
+// ============================================================================
+// Database Models
+// ============================================================================
+
+// CloudSyncItem is one queued ticket snapshot.
+type CloudSyncItem struct {
+	ID             int64      `json:"id" db:"id"`
+	TerminalID     string     `json:"terminal_id" db:"terminal_id" example:"ATM-001"`
+	Source         string     `json:"source" db:"source" example:"update"`
+	Payload        string     `json:"payload,omitempty" db:"payload"`
+	Status         string     `json:"status" db:"status" example:"failed"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	LastStatusCode *int       `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+	SyncedAt       *time.Time `json:"synced_at,omitempty" db:"synced_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CloudSyncDiff is one difference found by a reconciliation run.
+type CloudSyncDiff struct {
+	TerminalID string   `json:"terminal_id" example:"ATM-001"`
+	Kind       string   `json:"kind" example:"mismatch"`           // mismatch, missing_remote, extra_remote
+	Fields     []string `json:"fields,omitempty" example:"status"` // differing fields (mismatch only)
+}
+
+// CloudSyncReconciliation summarises one comparison of open_ticket with the cloud app.
+type CloudSyncReconciliation struct {
+	ID            int             `json:"id" db:"id"`
+	StartedAt     time.Time       `json:"started_at" db:"started_at"`
+	FinishedAt    time.Time       `json:"finished_at" db:"finished_at"`
+	TriggeredBy   *int            `json:"triggered_by,omitempty" db:"triggered_by"`
+	LocalCount    int             `json:"local_count" db:"local_count"`
+	RemoteCount   int             `json:"remote_count" db:"remote_count"`
+	Matched       int             `json:"matched" db:"matched"`
+	Mismatched    int             `json:"mismatched" db:"mismatched"`
+	MissingRemote int             `json:"missing_remote" db:"missing_remote"`
+	ExtraRemote   int             `json:"extra_remote" db:"extra_remote"`
+	Requeued      int             `json:"requeued" db:"requeued"`
+	Error         string          `json:"error,omitempty" db:"error"`
+	Details       []CloudSyncDiff `json:"details,omitempty" db:"details"`
+}
+
+// ============================================================================
+// Response Models
+// ============================================================================
+
+// CloudSyncQueueStats counts queue items by status.
+type CloudSyncQueueStats struct {
+	Pending          int        `json:"pending"`
+	Synced           int        `json:"synced"`
+	Failed           int        `json:"failed"`
+	Superseded       int        `json:"superseded"`
+	OldestPendingAt  *time.Time `json:"oldest_pending_at,omitempty"`
+	LastSyncedAt     *time.Time `json:"last_synced_at,omitempty"`
+	LastFailureAt    *time.Time `json:"last_failure_at,omitempty"`
+	LastFailureError string     `json:"last_failure_error,omitempty"`
+}
+
+// CloudSyncStatus is returned by GET /api/v1/admin/cloud-sync/status
+type CloudSyncStatus struct {
+	Enabled            bool                     `json:"enabled"`
+	CloudAppURL        string                   `json:"cloud_app_url,omitempty" example:"https://cloud.example.com"`
+	ReconcileInterval  string                   `json:"reconcile_interval,omitempty" example:"1h0m0s"`
+	Queue              CloudSyncQueueStats      `json:"queue"`
+	LastReconciliation *CloudSyncReconciliation `json:"last_reconciliation,omitempty"`
+}
+
+// CloudSyncStatusResponse wraps the sync status
+type CloudSyncStatusResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    *CloudSyncStatus `json:"data"`
+}
+
+// CloudSyncItemListResponse lists queue items
+type CloudSyncItemListResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    []*CloudSyncItem `json:"data"`
+	Total   int              `json:"total"`
+}
+
+// CloudSyncReconciliationResponse contains one reconciliation run
+type CloudSyncReconciliationResponse struct {
+	Success bool                     `json:"success"`
+	Message string                   `json:"message"`
+	Data    *CloudSyncReconciliation `json:"data"`
+}
+
+// CloudSyncReconciliationListResponse lists reconciliation runs
+type CloudSyncReconciliationListResponse struct {
+	Success bool                       `json:"success"`
+	Message string                     `json:"message"`
+	Data    []*CloudSyncReconciliation `json:"data"`
+	Total   int                        `json:"total"`
+}
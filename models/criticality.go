@@ -0,0 +1,115 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// CriticalityRule is an admin-managed rule that marks open tickets as critical.
+// Every condition that is set must match; unset (nil) conditions are ignored.
+// A terminal is critical when at least one active rule matches it.
+type CriticalityRule struct {
+	ID          int    `json:"id" db:"id"`
+	Name        string `json:"name" db:"name"`
+	Description string `json:"description,omitempty" db:"description"`
+
+	// Conditions
+	Priority           *string  `json:"priority,omitempty" db:"priority" example:"1.High"`
+	Mode               *string  `json:"mode,omitempty" db:"mode" example:"Off-line"`
+	Status             *string  `json:"status,omitempty" db:"status" example:"0.NEW"`
+	MinTicketsDuration *float64 `json:"min_tickets_duration,omitempty" db:"min_tickets_duration" example:"240"`
+	MaxBalance         *int     `json:"max_balance,omitempty" db:"max_balance" example:"500000"`
+	Region             *string  `json:"region,omitempty" db:"region" example:"BANDUNG"`
+
+	IsActive  bool      `json:"is_active" db:"is_active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	CreatedBy *int      `json:"created_by,omitempty" db:"created_by"`
+}
+
+// CriticalityRuleRequest is the payload for creating or replacing a criticality rule.
+// At least one condition must be set.
+type CriticalityRuleRequest struct {
+	Name               string   `json:"name" binding:"required,min=3,max=200"`
+	Description        string   `json:"description"`
+	Priority           *string  `json:"priority"`
+	Mode               *string  `json:"mode"`
+	Status             *string  `json:"status"`
+	MinTicketsDuration *float64 `json:"min_tickets_duration"`
+	MaxBalance         *int     `json:"max_balance"`
+	Region             *string  `json:"region"`
+	IsActive           *bool    `json:"is_active"`
+}
+
+// HasConditions reports whether the request sets at least one condition.
+func (r *CriticalityRuleRequest) HasConditions() bool {
+	return r.Priority != nil || r.Mode != nil || r.Status != nil ||
+		r.MinTicketsDuration != nil || r.MaxBalance != nil || r.Region != nil
+}
+
+// TerminalLocationRow is an open ticket row joined with the terminal's
+// location from machine_master.dbo.atmi.
+type TerminalLocationRow struct {
+	DataRow
+	Province    string
+	CityRegency string
+	District    string
+	GPS         string
+}
+
+// Location returns a "Province - City" label, skipping empty parts.
+func (t *TerminalLocationRow) Location() string {
+	parts := make([]string, 0, 2)
+	if t.Province != "" {
+		parts = append(parts, t.Province)
+	}
+	if t.CityRegency != "" {
+		parts = append(parts, t.CityRegency)
+	}
+	return strings.Join(parts, " - ")
+}
+
+// Matches reports whether every condition set on the rule holds for the row.
+// Region matches the atmi province, city/regency or district, or the FLM service area.
+func (r *CriticalityRule) Matches(t *TerminalLocationRow) bool {
+	if r.Priority != nil && t.Priority.String != *r.Priority {
+		return false
+	}
+	if r.Mode != nil && t.Mode.String != *r.Mode {
+		return false
+	}
+	if r.Status != nil && t.Status.String != *r.Status {
+		return false
+	}
+	if r.MinTicketsDuration != nil && t.TicketsDuration < *r.MinTicketsDuration {
+		return false
+	}
+	if r.MaxBalance != nil && t.Balance > *r.MaxBalance {
+		return false
+	}
+	if r.Region != nil {
+		region := *r.Region
+		if !strings.EqualFold(t.Province, region) &&
+			!strings.EqualFold(t.CityRegency, region) &&
+			!strings.EqualFold(t.District, region) &&
+			!strings.EqualFold(GetFLMArea(t.FLM.String), region) {
+			return false
+		}
+	}
+	return true
+}
+
+// CriticalityRuleListResponse contains all configured criticality rules
+type CriticalityRuleListResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Data    []*CriticalityRule `json:"data"`
+	Total   int                `json:"total"`
+}
+
+// CriticalityRuleResponse contains a single criticality rule
+type CriticalityRuleResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    *CriticalityRule `json:"data,omitempty"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // DataRow is the unified response row returned by GET /api/v1/data.
 // It combines all ticket fields from ticket_master.dbo.open_ticket
 // with machine dimension columns from machine_master.dbo.machine via a LEFT JOIN.
@@ -12,7 +14,7 @@ type DataRow struct {
 	InitialProblem    NullString `json:"initial_problem" swaggertype:"string" example:"Cash dispenser jam"`
 	CurrentProblem    NullString `json:"current_problem" swaggertype:"string" example:"Card reader error"`
 	PDuration         NullString `json:"p_duration" swaggertype:"string" example:"2h 30m"`
-	IncidentStartTime NullString `json:"incident_start_datetime" swaggertype:"string" example:"2024-01-15 10:30:00"`
+	IncidentStartTime TicketTime `json:"incident_start_datetime" swaggertype:"string" format:"date-time" example:"2024-01-15T10:30:00+07:00"`
 	Count             int        `json:"count" example:"5"`
 	Status            NullString `json:"status" swaggertype:"string" example:"0.NEW"`
 	Remarks           NullString `json:"remarks" swaggertype:"string" example:"Waiting for technician"`
@@ -20,8 +22,8 @@ type DataRow struct {
 	Condition         NullString `json:"condition" swaggertype:"string" example:"Critical"`
 	TicketsNo         NullString `json:"tickets_no" swaggertype:"string" example:"TKT-2024-001"`
 	TicketsDuration   float64    `json:"tickets_duration" example:"150.5"`
-	OpenTime          NullString `json:"open_time" swaggertype:"string" example:"2024-01-15 08:00:00"`
-	CloseTime         NullString `json:"close_time" swaggertype:"string" example:"2024-01-15 18:00:00"`
+	OpenTime          TicketTime `json:"open_time" swaggertype:"string" format:"date-time" example:"2024-01-15T08:00:00+07:00"`
+	CloseTime         TicketTime `json:"close_time" swaggertype:"string" format:"date-time" example:"2024-01-15T18:00:00+07:00"`
 	ProblemHistory    NullString `json:"problem_history" swaggertype:"string" example:"Card reader issue resolved"`
 	ModeHistory       NullString `json:"mode_history" swaggertype:"string" example:"Online->Offline->Online"`
 	DSPFLM            NullString `json:"dsp_flm" swaggertype:"string" example:"FLM-001"`
@@ -34,6 +36,51 @@ type DataRow struct {
 	FLM     NullString `json:"flm" swaggertype:"string" example:"AVT - BANDUNG"`   // mm.[FLM]
 	SLM     NullString `json:"slm" swaggertype:"string" example:"KGP - WINCOR DW"` // mm.[SLM]
 	Net     NullString `json:"net" swaggertype:"string" example:"NOSAIRIS"`        // mm.[Net]
+
+	// ── SLA fields (computed from the matching SLA policy; null when none applies) ──
+	SLAPolicy           *string    `json:"sla_policy,omitempty" example:"High priority"`
+	SLADueAt            *time.Time `json:"sla_due_at" example:"2024-01-15T14:30:00+07:00"` // resolution deadline
+	SLARemainingMinutes *int       `json:"sla_remaining_minutes" example:"95"`             // minutes until sla_due_at, negative once breached
+	SLABreached         *bool      `json:"sla_breached" example:"false"`                   // closed after, or still open past, sla_due_at
+	SLAResponseDueAt    *time.Time `json:"sla_response_due_at,omitempty" example:"2024-01-15T11:30:00+07:00"`
+	SLAResponseBreached *bool      `json:"sla_response_breached,omitempty" example:"false"` // still 0.NEW past sla_response_due_at
+}
+
+// UseLegacyTimeFormat makes the ticket timestamps marshal exactly as stored
+// in the ticket DB instead of RFC 3339 (legacy_time_format=true).
+func (d *DataRow) UseLegacyTimeFormat() {
+	d.IncidentStartTime.UseLegacyFormat()
+	d.OpenTime.UseLegacyFormat()
+	d.CloseTime.UseLegacyFormat()
+}
+
+// ToOpenTicket returns the ticket part of the row (machine dimension fields dropped).
+func (d *DataRow) ToOpenTicket() *OpenTicket {
+	return &OpenTicket{
+		TerminalID:        d.TerminalID,
+		TerminalName:      d.TerminalName,
+		Priority:          d.Priority,
+		Mode:              d.Mode,
+		InitialProblem:    d.InitialProblem,
+		CurrentProblem:    d.CurrentProblem,
+		PDuration:         d.PDuration,
+		IncidentStartTime: d.IncidentStartTime,
+		Count:             d.Count,
+		Status:            d.Status,
+		Remarks:           d.Remarks,
+		Balance:           d.Balance,
+		Condition:         d.Condition,
+		TicketsNo:         d.TicketsNo,
+		TicketsDuration:   d.TicketsDuration,
+		OpenTime:          d.OpenTime,
+		CloseTime:         d.CloseTime,
+		ProblemHistory:    d.ProblemHistory,
+		ModeHistory:       d.ModeHistory,
+		DSPFLM:            d.DSPFLM,
+		DSPSLM:            d.DSPSLM,
+		LastWithdrawal:    d.LastWithdrawal,
+		ExportName:        d.ExportName,
+	}
 }
 
 // DataUpdateRequest represents updatable ticket fields sent in PUT /api/v1/data/:terminal_id
@@ -44,16 +91,17 @@ type DataUpdateRequest struct {
 	Status         string `json:"status" example:"2.Kirim FLM"`
 	Remarks        string `json:"remarks" example:"Technician dispatched"`
 	Condition      string `json:"condition" example:"Normal"`
-	CloseTime      string `json:"close_time" example:"2024-01-15 18:00:00"`
+	CloseTime      string `json:"close_time" example:"2024-01-15T18:00:00+07:00"` // RFC 3339 or any accepted ticket time format
 	ProblemHistory string `json:"problem_history" example:"Card reader issue resolved"`
 	ModeHistory    string `json:"mode_history" example:"Online->Offline->Online"`
 }
 
 // DataResponse is the standardized single-row response
 type DataResponse struct {
-	Success bool     `json:"success"`
-	Message string   `json:"message"`
-	Data    *DataRow `json:"data,omitempty"`
+	Success   bool     `json:"success"`
+	Message   string   `json:"message"`
+	Data      *DataRow `json:"data,omitempty"`
+	RequestID string   `json:"request_id,omitempty"` // Set on errors
 }
 
 // DataListResponse is the standardized list response
@@ -65,11 +113,13 @@ type DataListResponse struct {
 	Page       int        `json:"page,omitempty"`
 	PageSize   int        `json:"page_size,omitempty"`
 	TotalPages int        `json:"total_pages,omitempty"`
+	RequestID  string     `json:"request_id,omitempty"` // Set on errors
 
-	SortBy    string `json:"sort_by,omitempty"`
-	SortOrder string `json:"sort_order,omitempty"`
-	Search    string `json:"search,omitempty"`
-	Status    string `json:"status,omitempty"`
-	Mode      string `json:"mode,omitempty"`
-	Priority  string `json:"priority,omitempty"`
+	SortBy      string `json:"sort_by,omitempty"`
+	SortOrder   string `json:"sort_order,omitempty"`
+	Search      string `json:"search,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Mode        string `json:"mode,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+	SLABreached *bool  `json:"sla_breached,omitempty"`
 }
@@ -0,0 +1,39 @@
+package models
+
+// Envelope is the body of every successful /api/v2 response: data, plus meta
+// for lists. Failed responses are Problem documents instead.
+type Envelope struct {
+	Data interface{}   `json:"data"`
+	Meta *EnvelopeMeta `json:"meta,omitempty"`
+}
+
+// EnvelopeMeta holds response metadata that is not part of the resource itself.
+type EnvelopeMeta struct {
+	Pagination *Pagination `json:"pagination,omitempty"`
+}
+
+// Pagination describes the page returned by a v2 list endpoint.
+type Pagination struct {
+	Page       int `json:"page" example:"1"`
+	PageSize   int `json:"page_size" example:"100"`
+	Total      int `json:"total" example:"312"` // Rows matching the filters across all pages
+	TotalPages int `json:"total_pages" example:"4"`
+}
+
+// NewPagination computes the page count for total rows split into pages of pageSize.
+func NewPagination(page, pageSize, total int) *Pagination {
+	totalPages := total / pageSize
+	if total%pageSize > 0 {
+		totalPages++
+	}
+	return &Pagination{Page: page, PageSize: pageSize, Total: total, TotalPages: totalPages}
+}
+
+// FieldMetadata is the v2 form of MetadataResponse: the valid status, mode
+// and priority values without the v1 success/message fields.
+type FieldMetadata struct {
+	Statuses    []StatusInfo   `json:"statuses"`
+	Modes       []ModeInfo     `json:"modes"`
+	Priorities  []PriorityInfo `json:"priorities"`
+	LastUpdated string         `json:"last_updated" example:"2024-01-15T10:30:00Z"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Dependency states reported by the health prober
+const (
+	DependencyUp            = "up"
+	DependencyDown          = "down"
+	DependencyUnknown       = "unknown" // not probed yet
+	DependencyNotConfigured = "not_configured"
+)
+
+// Overall readiness states reported by GET /readyz
+const (
+	ReadinessReady    = "ready"
+	ReadinessDegraded = "degraded"  // a non-critical dependency is down
+	ReadinessNotReady = "not_ready" // a critical dependency is down or not probed yet
+)
+
+// DependencyHealth is the last probe result of one dependency.
+type DependencyHealth struct {
+	Name          string     `json:"name" example:"ticket_master"`
+	Status        string     `json:"status" example:"up"`
+	Critical      bool       `json:"critical"` // down fails readiness; otherwise it only degrades it
+	LatencyMs     float64    `json:"latency_ms" example:"3.2"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// ReadinessReport is the body of GET /readyz and GET /health.
+type ReadinessReport struct {
+	Status       string             `json:"status" example:"ready"`
+	Dependencies []DependencyHealth `json:"dependencies"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Idempotency record statuses
+const (
+	IdempotencyInProgress = "in_progress" // the first request is still running
+	IdempotencyCompleted  = "completed"   // the response is stored for replay
+)
+
+// IdempotencyRecord is one Idempotency-Key of one token, with the fingerprint
+// of the request that first used it and, once finished, that request's response.
+type IdempotencyRecord struct {
+	ID          int64  `json:"id" db:"id"`
+	TokenID     int    `json:"token_id" db:"token_id"`
+	Key         string `json:"idempotency_key" db:"idempotency_key"`
+	Method      string `json:"method" db:"method"`
+	Path        string `json:"path" db:"path"`
+	RequestHash string `json:"request_hash" db:"request_hash"`
+	Status      string `json:"status" db:"status"`
+
+	ResponseStatus      int    `json:"response_status,omitempty" db:"response_status"`
+	ResponseContentType string `json:"response_content_type,omitempty" db:"response_content_type"`
+	ResponseBody        []byte `json:"-" db:"response_body"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+}
@@ -3,6 +3,8 @@ package models
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"time"
 )
 
 // NullString is a custom type that handles NULL database values
@@ -51,17 +53,23 @@ func (nt NullTime) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON implements the json.Unmarshaler interface
+// Accepts null, RFC 3339, or any other ticket time format; values without
+// an offset are interpreted in the ticket zone
 func (nt *NullTime) UnmarshalJSON(data []byte) error {
-	var t *string
-	if err := json.Unmarshal(data, &t); err != nil {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
 		return err
 	}
-	if t != nil {
-		nt.Valid = true
-		// Parse the time string - adjust format as needed
-		// This is a simplified version
-	} else {
+	if s == nil || *s == "" {
 		nt.Valid = false
+		nt.Time = time.Time{}
+		return nil
+	}
+	t, ok := ParseTicketTime(*s, ticketLocation)
+	if !ok {
+		return fmt.Errorf("unrecognised time format %q", *s)
 	}
+	nt.Valid = true
+	nt.Time = t
 	return nil
 }
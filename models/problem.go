@@ -0,0 +1,43 @@
+package models
+
+// ProblemContentType is the media type of Problem responses (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details document. Code is an extension
+// member holding a stable machine-readable cause; it is also recorded in
+// token_usage_logs.error_code so analytics can group errors by cause.
+// RequestID echoes the X-Request-ID header for support requests.
+type Problem struct {
+	Type      string `json:"type" example:"about:blank"`
+	Title     string `json:"title" example:"Not Found"` // HTTP status text
+	Status    int    `json:"status" example:"404"`
+	Detail    string `json:"detail,omitempty" example:"not found"`
+	Instance  string `json:"instance,omitempty" example:"/api/v2/data/ATM-001"`
+	Code      string `json:"code" example:"not_found"`
+	RequestID string `json:"request_id,omitempty" example:"3f1c9a4e-7b2d-4e8a-9c61-0d5f2b7e8a13"`
+}
+
+// Stable error codes returned in Problem.Code and stored in
+// TokenUsageLog.ErrorCode. Clients may rely on these; never rename one.
+const (
+	ErrCodeInvalidRequest     = "invalid_request"
+	ErrCodeNoFieldsToUpdate   = "no_fields_to_update"
+	ErrCodeOutOfScope         = "out_of_scope"
+	ErrCodeNotFound           = "not_found"
+	ErrCodeConflict           = "conflict"
+	ErrCodeIdempotencyReused  = "idempotency_key_reused"
+	ErrCodeIdempotencyPending = "idempotency_in_progress"
+	ErrCodeMissingToken       = "missing_token"
+	ErrCodeInvalidToken       = "invalid_token"
+	ErrCodeTokenRevoked       = "token_revoked"
+	ErrCodeTokenExpired       = "token_expired"
+	ErrCodeTokenDisabled      = "token_disabled"
+	ErrCodeIPNotAllowed       = "ip_not_allowed"
+	ErrCodeClientCertRequired = "client_certificate_required"
+	ErrCodeClientCertMismatch = "client_certificate_mismatch"
+	ErrCodeRateLimited        = "rate_limited"
+	ErrCodeInternal           = "internal_error"
+	ErrCodeServiceUnavailable = "service_unavailable"
+	ErrCodeQueryTimeout       = "query_timeout"
+	ErrCodeRequestCanceled    = "request_canceled"
+)
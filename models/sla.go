@@ -0,0 +1,208 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatusNew is the ticket status of a ticket nobody has responded to yet.
+// The SLA response target is met once a ticket leaves this status.
+const StatusNew = "0.NEW"
+
+// ============================================================================
+// Database Models
+// ============================================================================
+
+// SLACalendar defines the business hours SLA targets are counted in.
+type SLACalendar struct {
+	ID        int       `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name" example:"Office hours (WIB)"`
+	Timezone  string    `json:"timezone" db:"timezone" example:"Asia/Jakarta"`
+	WorkDays  string    `json:"work_days" db:"work_days" example:"1,2,3,4,5"` // 0 = Sunday ... 6 = Saturday
+	WorkStart string    `json:"work_start" db:"work_start" example:"08:00"`
+	WorkEnd   string    `json:"work_end" db:"work_end" example:"17:00"`
+	Holidays  []string  `json:"holidays" db:"holidays" example:"2026-12-25"` // stored as JSON array
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	CreatedBy *int      `json:"created_by,omitempty" db:"created_by"`
+
+	// Parsed schedule, populated by Compile
+	loc        *time.Location
+	days       map[time.Weekday]bool
+	startMin   int
+	endMin     int
+	holidaySet map[string]bool
+}
+
+// SLAPolicy defines the response and resolution targets for one ticket priority,
+// optionally narrowed to one FLM vendor (mm.[FLM name]).
+type SLAPolicy struct {
+	ID                int       `json:"id" db:"id"`
+	Name              string    `json:"name" db:"name" example:"High priority"`
+	Priority          string    `json:"priority" db:"priority" example:"1.High"`
+	VendorName        *string   `json:"vendor_name,omitempty" db:"vendor_name" example:"AVT"`
+	ResponseMinutes   *int      `json:"response_minutes,omitempty" db:"response_minutes" example:"60"`
+	ResolutionMinutes int       `json:"resolution_minutes" db:"resolution_minutes" example:"240"`
+	CalendarID        *int      `json:"calendar_id,omitempty" db:"calendar_id"` // nil = 24x7
+	IsActive          bool      `json:"is_active" db:"is_active"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+	CreatedBy         *int      `json:"created_by,omitempty" db:"created_by"`
+}
+
+// ============================================================================
+// Request/Response Models
+// ============================================================================
+
+// SLACalendarRequest is the payload for creating or replacing an SLA calendar
+type SLACalendarRequest struct {
+	Name      string   `json:"name" binding:"required,min=3,max=200"`
+	Timezone  string   `json:"timezone" binding:"required" example:"Asia/Jakarta"`
+	WorkDays  []int    `json:"work_days" binding:"required,min=1,dive,min=0,max=6" example:"1,2,3,4,5"`
+	WorkStart string   `json:"work_start" binding:"required" example:"08:00"`
+	WorkEnd   string   `json:"work_end" binding:"required" example:"17:00"`
+	Holidays  []string `json:"holidays" example:"2026-12-25"`
+}
+
+// SLAPolicyRequest is the payload for creating or replacing an SLA policy
+type SLAPolicyRequest struct {
+	Name              string  `json:"name" binding:"required,min=3,max=200"`
+	Priority          string  `json:"priority" binding:"required" example:"1.High"`
+	VendorName        *string `json:"vendor_name" example:"AVT"`
+	ResponseMinutes   *int    `json:"response_minutes" binding:"omitempty,min=1" example:"60"`
+	ResolutionMinutes int     `json:"resolution_minutes" binding:"required,min=1" example:"240"`
+	CalendarID        *int    `json:"calendar_id"`
+	IsActive          *bool   `json:"is_active"`
+}
+
+// SLACalendarResponse contains a single SLA calendar
+type SLACalendarResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Data    *SLACalendar `json:"data,omitempty"`
+}
+
+// SLAPolicyResponse contains a single SLA policy
+type SLAPolicyResponse struct {
+	Success bool       `json:"success"`
+	Message string     `json:"message"`
+	Data    *SLAPolicy `json:"data,omitempty"`
+}
+
+// ============================================================================
+// Helper Functions
+// ============================================================================
+
+// FormatWorkDays converts weekday numbers to the stored comma-separated form.
+func FormatWorkDays(days []int) string {
+	parts := make([]string, len(days))
+	for i, d := range days {
+		parts[i] = strconv.Itoa(d)
+	}
+	return strings.Join(parts, ",")
+}
+
+// HolidaysJSON returns the holiday list as stored in the database.
+func (c *SLACalendar) HolidaysJSON() string {
+	if c.Holidays == nil {
+		return "[]"
+	}
+	b, _ := json.Marshal(c.Holidays)
+	return string(b)
+}
+
+// Compile parses the calendar's timezone, work days, hours and holidays.
+// It must succeed before AddBusinessMinutes is used.
+func (c *SLACalendar) Compile() error {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid timezone %q: %v", c.Timezone, err)
+	}
+
+	days := make(map[time.Weekday]bool)
+	for _, part := range strings.Split(c.WorkDays, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		d, err := strconv.Atoi(part)
+		if err != nil || d < 0 || d > 6 {
+			return fmt.Errorf("invalid work day %q", part)
+		}
+		days[time.Weekday(d)] = true
+	}
+	if len(days) == 0 {
+		return fmt.Errorf("calendar has no work days")
+	}
+
+	startMin, err := parseClock(c.WorkStart)
+	if err != nil {
+		return err
+	}
+	endMin, err := parseClock(c.WorkEnd)
+	if err != nil {
+		return err
+	}
+	if endMin <= startMin {
+		return fmt.Errorf("work_end must be after work_start")
+	}
+
+	holidays := make(map[string]bool, len(c.Holidays))
+	for _, h := range c.Holidays {
+		if _, err := time.Parse("2006-01-02", h); err != nil {
+			return fmt.Errorf("invalid holiday %q (expected YYYY-MM-DD)", h)
+		}
+		holidays[h] = true
+	}
+
+	c.loc, c.days, c.startMin, c.endMin, c.holidaySet = loc, days, startMin, endMin, holidays
+	return nil
+}
+
+// Location returns the calendar's time zone (time.Local before Compile).
+func (c *SLACalendar) Location() *time.Location {
+	if c.loc == nil {
+		return time.Local
+	}
+	return c.loc
+}
+
+// AddBusinessMinutes returns the instant that lies the given number of business
+// minutes after start, skipping non-work days, holidays and out-of-hours time.
+func (c *SLACalendar) AddBusinessMinutes(start time.Time, minutes int) time.Time {
+	remaining := time.Duration(minutes) * time.Minute
+	t := start.In(c.Location())
+
+	// Bounded so a misconfigured calendar cannot loop forever (~10 years of days)
+	for i := 0; i < 3660; i++ {
+		dayStart := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		if c.days[t.Weekday()] && !c.holidaySet[dayStart.Format("2006-01-02")] {
+			open := dayStart.Add(time.Duration(c.startMin) * time.Minute)
+			closeAt := dayStart.Add(time.Duration(c.endMin) * time.Minute)
+			if t.Before(open) {
+				t = open
+			}
+			if t.Before(closeAt) {
+				available := closeAt.Sub(t)
+				if remaining <= available {
+					return t.Add(remaining)
+				}
+				remaining -= available
+			}
+		}
+		t = dayStart.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q (expected HH:MM)", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
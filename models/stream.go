@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Change event types sent on GET /api/v1/data/stream
+const (
+	ChangeCreated = "created" // ticket appeared in open_ticket
+	ChangeUpdated = "updated" // ticket fields changed
+	ChangeClosed  = "closed"  // close time set, or ticket removed from open_ticket
+	ChangeReset   = "reset"   // missed events are no longer held; refetch the data
+)
+
+// ChangeEvent is one ticket change delivered to stream subscribers.
+// ID is the SSE event id used for Last-Event-ID resume.
+type ChangeEvent struct {
+	ID         uint64    `json:"id" example:"1042"`
+	Type       string    `json:"type" example:"updated"`
+	TerminalID string    `json:"terminal_id" example:"ATM-001"`
+	OccurredAt time.Time `json:"occurred_at" example:"2024-01-15T10:35:00+07:00"` // when the change was detected
+	Data       *DataRow  `json:"data"`                                            // row after the change (last known row for removals)
+}
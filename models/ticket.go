@@ -10,7 +10,7 @@ type OpenTicket struct {
 	InitialProblem     NullString `json:"initial_problem" db:"Initial Problem" swaggertype:"string" example:"Cash dispenser jam"` // Initial problem description (nullable)
 	CurrentProblem     NullString `json:"current_problem" db:"Current Problem" swaggertype:"string" example:"Card reader error"` // Current problem description (nullable)
 	PDuration          NullString `json:"p_duration" db:"P-Duration" swaggertype:"string" example:"2h 30m"`                     // Problem duration (nullable)
-	IncidentStartTime  NullString `json:"incident_start_datetime" db:"Incident start datetime" swaggertype:"string" example:"2024-01-15 10:30:00"` // Incident start timestamp (nullable)
+	IncidentStartTime  TicketTime `json:"incident_start_datetime" db:"Incident start datetime" swaggertype:"string" format:"date-time" example:"2024-01-15T10:30:00+07:00"` // Incident start timestamp (nullable)
 	Count              int        `json:"count" db:"Count" example:"5"`                                                         // Count value
 	Status             NullString `json:"status" db:"Status" swaggertype:"string" example:"0.NEW"`                             // Ticket status (nullable): 0.NEW, 1.Req FD ke HD, 2.Kirim FLM, etc.
 	Remarks            NullString `json:"remarks" db:"Remarks" swaggertype:"string" example:"Waiting for technician"`           // Remarks/notes (nullable)
@@ -18,8 +18,8 @@ type OpenTicket struct {
 	Condition          NullString `json:"condition" db:"Condition" swaggertype:"string" example:"Critical"`                     // Condition status (nullable)
 	TicketsNo          NullString `json:"tickets_no" db:"Tickets no" swaggertype:"string" example:"TKT-2024-001"`              // Ticket number (nullable)
 	TicketsDuration    float64    `json:"tickets_duration" db:"Tickets duration" example:"150.5"`                               // Ticket duration in minutes (float)
-	OpenTime           NullString `json:"open_time" db:"Open time" swaggertype:"string" example:"2024-01-15 08:00:00"`         // Ticket open time (nullable)
-	CloseTime          NullString `json:"close_time" db:"Close time" swaggertype:"string" example:"2024-01-15 18:00:00"`       // Ticket close time (nullable)
+	OpenTime           TicketTime `json:"open_time" db:"Open time" swaggertype:"string" format:"date-time" example:"2024-01-15T08:00:00+07:00"`         // Ticket open time (nullable)
+	CloseTime          TicketTime `json:"close_time" db:"Close time" swaggertype:"string" format:"date-time" example:"2024-01-15T18:00:00+07:00"`       // Ticket close time (nullable)
 	ProblemHistory     NullString `json:"problem_history" db:"Problem History" swaggertype:"string" example:"Card reader issue resolved"` // Problem history (nullable)
 	ModeHistory        NullString `json:"mode_history" db:"Mode History" swaggertype:"string" example:"Online->Offline->Online"` // Mode change history (nullable)
 	DSPFLM             NullString `json:"dsp_flm" db:"DSP FLM" swaggertype:"string" example:"FLM-001"`                         // DSP FLM identifier (nullable)
@@ -28,6 +28,14 @@ type OpenTicket struct {
 	ExportName         NullString `json:"export_name" db:"Export Name" swaggertype:"string" example:"ATM_Report_Jan2024"`      // Export name for reports (nullable)
 }
 
+// UseLegacyTimeFormat makes the ticket timestamps marshal exactly as stored
+// in the ticket DB instead of RFC 3339 (legacy_time_format=true).
+func (t *OpenTicket) UseLegacyTimeFormat() {
+	t.IncidentStartTime.UseLegacyFormat()
+	t.OpenTime.UseLegacyFormat()
+	t.CloseTime.UseLegacyFormat()
+}
+
 // TicketCreateRequest represents the payload for creating a new ticket
 // Used when receiving ticket creation requests from the cloud app
 type TicketCreateRequest struct {
@@ -84,4 +92,5 @@ type ErrorResponse struct {
 	Success bool   `json:"success" example:"false"` // Always false for errors
 	Message string `json:"message" example:"Error message describing what went wrong"` // Error message
 	Error   string `json:"error,omitempty" example:"detailed error information"` // Optional detailed error
+	RequestID string `json:"request_id,omitempty" example:"3f1c9a4e-7b2d-4e8a-9c61-0d5f2b7e8a13"` // X-Request-ID of the failed request
 }
@@ -0,0 +1,172 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LegacyTimeLayout is how ticket timestamps are written back to the ticket DB
+// and how they are emitted when a client opts into legacy_time_format.
+const LegacyTimeLayout = "2006-01-02 15:04:05"
+
+// ticketTimeLayouts are the layouts accepted for the free-text time columns of
+// ticket_master.dbo.open_ticket and for timestamps sent by clients, tried in order.
+// Slash/dash dates are day-first, as written in Indonesia.
+var ticketTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02/01/2006",
+	"02-01-2006 15:04:05",
+	"02-01-2006 15:04",
+	"02-01-2006",
+	"02 Jan 2006 15:04:05",
+	"02 Jan 2006 15:04",
+	"02 Jan 2006",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// ticketLocation is the zone ticket timestamps without an explicit offset are
+// stored in and the zone they are emitted in. Set once at startup.
+var ticketLocation = time.Local
+
+// SetTicketLocation sets the zone ticket timestamps are interpreted and emitted in.
+func SetTicketLocation(loc *time.Location) {
+	if loc != nil {
+		ticketLocation = loc
+	}
+}
+
+// TicketLocation returns the zone ticket timestamps are interpreted and emitted in.
+func TicketLocation() *time.Location {
+	return ticketLocation
+}
+
+// ParseTicketTime parses a ticket timestamp string. Values without an explicit
+// offset are interpreted in loc. Returns false for empty or unparseable input.
+func ParseTicketTime(value string, loc *time.Location) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	if loc == nil {
+		loc = ticketLocation
+	}
+	for _, layout := range ticketTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// NormalizeTicketTime parses a client-supplied timestamp in any accepted format
+// and returns it in the ticket DB's storage layout. Empty input stays empty.
+func NormalizeTicketTime(value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	t, ok := ParseTicketTime(value, ticketLocation)
+	if !ok {
+		return "", fmt.Errorf("unrecognised time format %q (use RFC 3339, e.g. 2024-01-15T18:00:00+07:00)", value)
+	}
+	return t.In(ticketLocation).Format(LegacyTimeLayout), nil
+}
+
+// TicketTime is a nullable ticket timestamp read from a free-text (or datetime)
+// column. It marshals to RFC 3339 in the ticket zone, or to the value exactly as
+// stored when legacy output was requested for the row.
+type TicketTime struct {
+	Time  time.Time
+	Valid bool   // Time holds a parsed timestamp
+	Raw   string // value as stored in the database
+
+	legacy bool
+}
+
+// Scan implements the sql.Scanner interface.
+func (tt *TicketTime) Scan(value interface{}) error {
+	*tt = TicketTime{}
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		// datetime columns come back as wall-clock time labelled UTC;
+		// the ticket DB stores local time, so re-label rather than convert.
+		if v.Location() == time.UTC {
+			v = time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), ticketLocation)
+		}
+		tt.Time, tt.Valid = v, true
+		tt.Raw = v.Format(LegacyTimeLayout)
+		return nil
+	case []byte:
+		tt.Raw = string(v)
+	case string:
+		tt.Raw = v
+	default:
+		return fmt.Errorf("cannot scan %T into TicketTime", value)
+	}
+	tt.Time, tt.Valid = ParseTicketTime(tt.Raw, ticketLocation)
+	return nil
+}
+
+// Value implements the driver.Valuer interface, writing the storage layout.
+func (tt TicketTime) Value() (driver.Value, error) {
+	if !tt.Valid {
+		return nil, nil
+	}
+	return tt.Time.In(ticketLocation).Format(LegacyTimeLayout), nil
+}
+
+// UseLegacyFormat switches JSON output to the value exactly as stored.
+func (tt *TicketTime) UseLegacyFormat() {
+	tt.legacy = true
+}
+
+// MarshalJSON implements the json.Marshaler interface
+// Returns null when the column is NULL or holds an unparseable value
+func (tt TicketTime) MarshalJSON() ([]byte, error) {
+	if tt.legacy {
+		if tt.Raw == "" && !tt.Valid {
+			return []byte("null"), nil
+		}
+		return json.Marshal(tt.Raw)
+	}
+	if !tt.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(tt.Time.In(ticketLocation).Format(time.RFC3339))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+// Accepts null or a string in any of the accepted ticket time formats
+func (tt *TicketTime) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*tt = TicketTime{}
+	if s == nil || strings.TrimSpace(*s) == "" {
+		return nil
+	}
+	t, ok := ParseTicketTime(*s, ticketLocation)
+	if !ok {
+		return fmt.Errorf("unrecognised time format %q", *s)
+	}
+	tt.Time, tt.Valid, tt.Raw = t, true, *s
+	return nil
+}
@@ -54,6 +54,12 @@ type APIToken struct {
 	IPWhitelist    string `json:"ip_whitelist,omitempty" db:"ip_whitelist"`       // JSON array
 	AllowedOrigins string `json:"allowed_origins,omitempty" db:"allowed_origins"` // JSON array
 
+	// Client certificate binding (mTLS). When set, requests with this token must
+	// present a verified client certificate with this SHA-256 fingerprint
+	// (lowercase hex) and/or subject DN (RFC 2253, e.g. "CN=avt-client,O=AVT").
+	ClientCertFingerprint string `json:"client_cert_fingerprint,omitempty" db:"client_cert_fingerprint"`
+	ClientCertSubject     string `json:"client_cert_subject,omitempty" db:"client_cert_subject"`
+
 	// Rate Limiting
 	RateLimitPerMinute int `json:"rate_limit_per_minute" db:"rate_limit_per_minute"`
 	RateLimitPerHour   int `json:"rate_limit_per_hour" db:"rate_limit_per_hour"`
@@ -107,6 +113,7 @@ type TokenUsageLog struct {
 
 	// Metadata
 	RequestID        string `json:"request_id,omitempty" db:"request_id"`
+	APIVersion       string `json:"api_version,omitempty" db:"api_version"` // v1 or v2; empty for gRPC calls
 	RequestBodySize  int    `json:"request_body_size,omitempty" db:"request_body_size"`
 	ResponseBodySize int    `json:"response_body_size,omitempty" db:"response_body_size"`
 
@@ -160,6 +167,7 @@ type AuditLog struct {
 	IPAddress   string `json:"ip_address,omitempty" db:"ip_address"`
 	UserAgent   string `json:"user_agent,omitempty" db:"user_agent"`
 	Description string `json:"description,omitempty" db:"description"`
+	RequestID   string `json:"request_id,omitempty" db:"request_id"` // X-Request-ID of the admin request
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 }
 
@@ -195,6 +203,10 @@ type CreateTokenRequest struct {
 	RateLimitPerDay    int      `json:"rate_limit_per_day"`
 	ExpiresAt          *time.Time `json:"expires_at"`
 
+	// Client certificate binding – optional, see APIToken
+	ClientCertFingerprint string `json:"client_cert_fingerprint"`
+	ClientCertSubject     string `json:"client_cert_subject"`
+
 	// Vendor filter – mutually exclusive with IsSuperToken
 	// VendorName is a human-readable label (e.g. "AVT").
 	// FilterColumn is the logical key used to build the WHERE clause (e.g. "flm_name").
@@ -226,6 +238,10 @@ type UpdateTokenRequest struct {
 	RateLimitPerDay    *int       `json:"rate_limit_per_day"`
 	ExpiresAt          *time.Time `json:"expires_at"`
 
+	// Client certificate binding – set to "" to remove
+	ClientCertFingerprint *string `json:"client_cert_fingerprint"`
+	ClientCertSubject     *string `json:"client_cert_subject"`
+
 	// Vendor filter fields – all optional, only updated when non-empty / explicitly set
 	VendorName   *string `json:"vendor_name"`
 	FilterColumn *string `json:"filter_column"`
@@ -305,6 +321,25 @@ type DailyUsage struct {
 	AvgResponseTimeMs  float64 `json:"avg_response_time_ms"`
 }
 
+// ErrorCodeStats counts failed requests by their stable error code
+type ErrorCodeStats struct {
+	ErrorCode    string   `json:"error_code" example:"rate_limited"`
+	RequestCount int64    `json:"request_count"`
+	UniqueTokens int      `json:"unique_tokens"`
+	LastSeenAt   NullTime `json:"last_seen_at" swaggertype:"string" example:"2024-02-15T10:30:00Z"`
+}
+
+// APIVersionUsage shows how much of a token's REST traffic still goes to the
+// deprecated v1 API
+type APIVersionUsage struct {
+	TokenID       int      `json:"token_id"`
+	TokenName     string   `json:"token_name"`
+	V1Requests    int64    `json:"v1_requests"`
+	V2Requests    int64    `json:"v2_requests"`
+	V1Endpoints   int      `json:"v1_endpoints"` // Distinct v1 paths called
+	LastV1Request NullTime `json:"last_v1_request_at" swaggertype:"string" example:"2024-02-15T10:30:00Z"`
+}
+
 // ============================================================================
 // Helper Functions
 // ============================================================================
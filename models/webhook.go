@@ -0,0 +1,123 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Webhook event types
+const (
+	WebhookTicketCreated = "ticket.created"
+	WebhookTicketUpdated = "ticket.updated"
+	WebhookTicketClosed  = "ticket.closed"
+)
+
+// WebhookEventTypes lists every event type a subscription can filter on.
+var WebhookEventTypes = []string{WebhookTicketCreated, WebhookTicketUpdated, WebhookTicketClosed}
+
+// Webhook delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead" // attempts exhausted; listed in the dead-letter view
+)
+
+// ============================================================================
+// Database Models
+// ============================================================================
+
+// WebhookSubscription pushes ticket events to a URL on behalf of an API token.
+// Events are limited to terminals inside the token's vendor scope.
+type WebhookSubscription struct {
+	ID         int       `json:"id" db:"id"`
+	TokenID    int       `json:"token_id" db:"token_id"`
+	URL        string    `json:"url" db:"url" example:"https://vendor.example.com/hooks/tickets"`
+	EventTypes []string  `json:"event_types" db:"event_types" example:"ticket.updated,ticket.closed"` // empty = all events
+	Secret     string    `json:"-" db:"secret"`                                                       // only returned on create
+	IsActive   bool      `json:"is_active" db:"is_active"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	CreatedBy  *int      `json:"created_by,omitempty" db:"created_by"`
+
+	// Resolved from the owning token when loading subscriptions for delivery
+	TokenFilterColumn string `json:"-"`
+	TokenFilterValue  string `json:"-"`
+	TokenIsSuper      bool   `json:"-"`
+}
+
+// WantsEvent reports whether the subscription's event filter includes eventType.
+func (s *WebhookSubscription) WantsEvent(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// EventTypesString returns the event filter as stored in the database.
+func (s *WebhookSubscription) EventTypesString() string {
+	return strings.Join(s.EventTypes, ",")
+}
+
+// WebhookDelivery is one outbox row: an event queued for one subscription.
+type WebhookDelivery struct {
+	ID             int64      `json:"id" db:"id"`
+	SubscriptionID int        `json:"subscription_id" db:"subscription_id"`
+	EventID        string     `json:"event_id" db:"event_id"`
+	EventType      string     `json:"event_type" db:"event_type" example:"ticket.updated"`
+	TerminalID     string     `json:"terminal_id" db:"terminal_id" example:"ATM-001"`
+	Payload        string     `json:"payload,omitempty" db:"payload"`
+	Status         string     `json:"status" db:"status" example:"pending"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	LastStatusCode *int       `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+
+	// Populated when claimed for delivery
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookPayload is the JSON body POSTed to subscribers.
+type WebhookPayload struct {
+	EventID    string    `json:"event_id" example:"9b2f3c1e-6a8d-4f5b-a1c2-3d4e5f6a7b8c"`
+	EventType  string    `json:"event_type" example:"ticket.updated"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       *DataRow  `json:"data"`
+}
+
+// ============================================================================
+// Request/Response Models
+// ============================================================================
+
+// WebhookSubscriptionRequest is the payload for creating or replacing a subscription.
+// A secret is generated when none is supplied.
+type WebhookSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required,url,max=2000" example:"https://vendor.example.com/hooks/tickets"`
+	EventTypes []string `json:"event_types" binding:"dive,oneof=ticket.created ticket.updated ticket.closed" example:"ticket.updated,ticket.closed"`
+	Secret     string   `json:"secret" binding:"omitempty,min=16,max=200"`
+	IsActive   *bool    `json:"is_active"`
+}
+
+// WebhookSubscriptionResponse contains a single subscription. Secret is only
+// set in the response to a create request.
+type WebhookSubscriptionResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message"`
+	Data    *WebhookSubscription `json:"data,omitempty"`
+	Secret  string               `json:"secret,omitempty"`
+}
+
+// WebhookDeliveryListResponse lists outbox rows
+type WebhookDeliveryListResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Data    []*WebhookDelivery `json:"data"`
+	Total   int                `json:"total"`
+}
@@ -0,0 +1,918 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: data.proto
+
+// gRPC counterpart of the /api/v1/data endpoints. Field names and values match
+// the REST JSON; nullable columns are proto3 optional fields and unset
+// timestamps mean NULL (or an unparseable stored value).
+
+package datapb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DataRow is one open ticket joined with its machine dimension columns.
+type DataRow struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ticket fields
+	TerminalId            string                 `protobuf:"bytes,1,opt,name=terminal_id,json=terminalId,proto3" json:"terminal_id,omitempty"`
+	TerminalName          string                 `protobuf:"bytes,2,opt,name=terminal_name,json=terminalName,proto3" json:"terminal_name,omitempty"`
+	Priority              *string                `protobuf:"bytes,3,opt,name=priority,proto3,oneof" json:"priority,omitempty"`
+	Mode                  *string                `protobuf:"bytes,4,opt,name=mode,proto3,oneof" json:"mode,omitempty"`
+	InitialProblem        *string                `protobuf:"bytes,5,opt,name=initial_problem,json=initialProblem,proto3,oneof" json:"initial_problem,omitempty"`
+	CurrentProblem        *string                `protobuf:"bytes,6,opt,name=current_problem,json=currentProblem,proto3,oneof" json:"current_problem,omitempty"`
+	PDuration             *string                `protobuf:"bytes,7,opt,name=p_duration,json=pDuration,proto3,oneof" json:"p_duration,omitempty"`
+	IncidentStartDatetime *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=incident_start_datetime,json=incidentStartDatetime,proto3" json:"incident_start_datetime,omitempty"`
+	Count                 int64                  `protobuf:"varint,9,opt,name=count,proto3" json:"count,omitempty"`
+	Status                *string                `protobuf:"bytes,10,opt,name=status,proto3,oneof" json:"status,omitempty"`
+	Remarks               *string                `protobuf:"bytes,11,opt,name=remarks,proto3,oneof" json:"remarks,omitempty"`
+	Balance               int64                  `protobuf:"varint,12,opt,name=balance,proto3" json:"balance,omitempty"`
+	Condition             *string                `protobuf:"bytes,13,opt,name=condition,proto3,oneof" json:"condition,omitempty"`
+	TicketsNo             *string                `protobuf:"bytes,14,opt,name=tickets_no,json=ticketsNo,proto3,oneof" json:"tickets_no,omitempty"`
+	TicketsDuration       float64                `protobuf:"fixed64,15,opt,name=tickets_duration,json=ticketsDuration,proto3" json:"tickets_duration,omitempty"`
+	OpenTime              *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=open_time,json=openTime,proto3" json:"open_time,omitempty"`
+	CloseTime             *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=close_time,json=closeTime,proto3" json:"close_time,omitempty"`
+	ProblemHistory        *string                `protobuf:"bytes,18,opt,name=problem_history,json=problemHistory,proto3,oneof" json:"problem_history,omitempty"`
+	ModeHistory           *string                `protobuf:"bytes,19,opt,name=mode_history,json=modeHistory,proto3,oneof" json:"mode_history,omitempty"`
+	DspFlm                *string                `protobuf:"bytes,20,opt,name=dsp_flm,json=dspFlm,proto3,oneof" json:"dsp_flm,omitempty"`
+	DspSlm                *string                `protobuf:"bytes,21,opt,name=dsp_slm,json=dspSlm,proto3,oneof" json:"dsp_slm,omitempty"`
+	LastWithdrawal        *timestamppb.Timestamp `protobuf:"bytes,22,opt,name=last_withdrawal,json=lastWithdrawal,proto3" json:"last_withdrawal,omitempty"`
+	ExportName            *string                `protobuf:"bytes,23,opt,name=export_name,json=exportName,proto3,oneof" json:"export_name,omitempty"`
+	// Machine dimension fields
+	FlmName *string `protobuf:"bytes,24,opt,name=flm_name,json=flmName,proto3,oneof" json:"flm_name,omitempty"`
+	Flm     *string `protobuf:"bytes,25,opt,name=flm,proto3,oneof" json:"flm,omitempty"`
+	Slm     *string `protobuf:"bytes,26,opt,name=slm,proto3,oneof" json:"slm,omitempty"`
+	Net     *string `protobuf:"bytes,27,opt,name=net,proto3,oneof" json:"net,omitempty"`
+	// SLA fields (unset when no active SLA policy matches the row)
+	SlaPolicy           *string                `protobuf:"bytes,28,opt,name=sla_policy,json=slaPolicy,proto3,oneof" json:"sla_policy,omitempty"`
+	SlaDueAt            *timestamppb.Timestamp `protobuf:"bytes,29,opt,name=sla_due_at,json=slaDueAt,proto3" json:"sla_due_at,omitempty"`
+	SlaRemainingMinutes *int64                 `protobuf:"varint,30,opt,name=sla_remaining_minutes,json=slaRemainingMinutes,proto3,oneof" json:"sla_remaining_minutes,omitempty"`
+	SlaBreached         *bool                  `protobuf:"varint,31,opt,name=sla_breached,json=slaBreached,proto3,oneof" json:"sla_breached,omitempty"`
+	SlaResponseDueAt    *timestamppb.Timestamp `protobuf:"bytes,32,opt,name=sla_response_due_at,json=slaResponseDueAt,proto3" json:"sla_response_due_at,omitempty"`
+	SlaResponseBreached *bool                  `protobuf:"varint,33,opt,name=sla_response_breached,json=slaResponseBreached,proto3,oneof" json:"sla_response_breached,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *DataRow) Reset() {
+	*x = DataRow{}
+	mi := &file_data_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DataRow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DataRow) ProtoMessage() {}
+
+func (x *DataRow) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DataRow.ProtoReflect.Descriptor instead.
+func (*DataRow) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DataRow) GetTerminalId() string {
+	if x != nil {
+		return x.TerminalId
+	}
+	return ""
+}
+
+func (x *DataRow) GetTerminalName() string {
+	if x != nil {
+		return x.TerminalName
+	}
+	return ""
+}
+
+func (x *DataRow) GetPriority() string {
+	if x != nil && x.Priority != nil {
+		return *x.Priority
+	}
+	return ""
+}
+
+func (x *DataRow) GetMode() string {
+	if x != nil && x.Mode != nil {
+		return *x.Mode
+	}
+	return ""
+}
+
+func (x *DataRow) GetInitialProblem() string {
+	if x != nil && x.InitialProblem != nil {
+		return *x.InitialProblem
+	}
+	return ""
+}
+
+func (x *DataRow) GetCurrentProblem() string {
+	if x != nil && x.CurrentProblem != nil {
+		return *x.CurrentProblem
+	}
+	return ""
+}
+
+func (x *DataRow) GetPDuration() string {
+	if x != nil && x.PDuration != nil {
+		return *x.PDuration
+	}
+	return ""
+}
+
+func (x *DataRow) GetIncidentStartDatetime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IncidentStartDatetime
+	}
+	return nil
+}
+
+func (x *DataRow) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *DataRow) GetStatus() string {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ""
+}
+
+func (x *DataRow) GetRemarks() string {
+	if x != nil && x.Remarks != nil {
+		return *x.Remarks
+	}
+	return ""
+}
+
+func (x *DataRow) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *DataRow) GetCondition() string {
+	if x != nil && x.Condition != nil {
+		return *x.Condition
+	}
+	return ""
+}
+
+func (x *DataRow) GetTicketsNo() string {
+	if x != nil && x.TicketsNo != nil {
+		return *x.TicketsNo
+	}
+	return ""
+}
+
+func (x *DataRow) GetTicketsDuration() float64 {
+	if x != nil {
+		return x.TicketsDuration
+	}
+	return 0
+}
+
+func (x *DataRow) GetOpenTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpenTime
+	}
+	return nil
+}
+
+func (x *DataRow) GetCloseTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CloseTime
+	}
+	return nil
+}
+
+func (x *DataRow) GetProblemHistory() string {
+	if x != nil && x.ProblemHistory != nil {
+		return *x.ProblemHistory
+	}
+	return ""
+}
+
+func (x *DataRow) GetModeHistory() string {
+	if x != nil && x.ModeHistory != nil {
+		return *x.ModeHistory
+	}
+	return ""
+}
+
+func (x *DataRow) GetDspFlm() string {
+	if x != nil && x.DspFlm != nil {
+		return *x.DspFlm
+	}
+	return ""
+}
+
+func (x *DataRow) GetDspSlm() string {
+	if x != nil && x.DspSlm != nil {
+		return *x.DspSlm
+	}
+	return ""
+}
+
+func (x *DataRow) GetLastWithdrawal() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastWithdrawal
+	}
+	return nil
+}
+
+func (x *DataRow) GetExportName() string {
+	if x != nil && x.ExportName != nil {
+		return *x.ExportName
+	}
+	return ""
+}
+
+func (x *DataRow) GetFlmName() string {
+	if x != nil && x.FlmName != nil {
+		return *x.FlmName
+	}
+	return ""
+}
+
+func (x *DataRow) GetFlm() string {
+	if x != nil && x.Flm != nil {
+		return *x.Flm
+	}
+	return ""
+}
+
+func (x *DataRow) GetSlm() string {
+	if x != nil && x.Slm != nil {
+		return *x.Slm
+	}
+	return ""
+}
+
+func (x *DataRow) GetNet() string {
+	if x != nil && x.Net != nil {
+		return *x.Net
+	}
+	return ""
+}
+
+func (x *DataRow) GetSlaPolicy() string {
+	if x != nil && x.SlaPolicy != nil {
+		return *x.SlaPolicy
+	}
+	return ""
+}
+
+func (x *DataRow) GetSlaDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SlaDueAt
+	}
+	return nil
+}
+
+func (x *DataRow) GetSlaRemainingMinutes() int64 {
+	if x != nil && x.SlaRemainingMinutes != nil {
+		return *x.SlaRemainingMinutes
+	}
+	return 0
+}
+
+func (x *DataRow) GetSlaBreached() bool {
+	if x != nil && x.SlaBreached != nil {
+		return *x.SlaBreached
+	}
+	return false
+}
+
+func (x *DataRow) GetSlaResponseDueAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SlaResponseDueAt
+	}
+	return nil
+}
+
+func (x *DataRow) GetSlaResponseBreached() bool {
+	if x != nil && x.SlaResponseBreached != nil {
+		return *x.SlaResponseBreached
+	}
+	return false
+}
+
+// ListDataRequest takes the query parameters of GET /api/v1/data.
+type ListDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Page          int32                  `protobuf:"varint,1,opt,name=page,proto3" json:"page,omitempty"`                           // 0 streams every matching row
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // rows per page when page > 0 (default 100, max 500)
+	SortBy        string                 `protobuf:"bytes,3,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`          // default incident_start_datetime
+	SortOrder     string                 `protobuf:"bytes,4,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"` // asc or desc (default desc)
+	Search        string                 `protobuf:"bytes,5,opt,name=search,proto3" json:"search,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Mode          string                 `protobuf:"bytes,7,opt,name=mode,proto3" json:"mode,omitempty"`
+	Priority      string                 `protobuf:"bytes,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	SlaBreached   *bool                  `protobuf:"varint,9,opt,name=sla_breached,json=slaBreached,proto3,oneof" json:"sla_breached,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDataRequest) Reset() {
+	*x = ListDataRequest{}
+	mi := &file_data_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDataRequest) ProtoMessage() {}
+
+func (x *ListDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDataRequest.ProtoReflect.Descriptor instead.
+func (*ListDataRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListDataRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListDataRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDataRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListDataRequest) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+func (x *ListDataRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListDataRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListDataRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *ListDataRequest) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *ListDataRequest) GetSlaBreached() bool {
+	if x != nil && x.SlaBreached != nil {
+		return *x.SlaBreached
+	}
+	return false
+}
+
+type GetDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TerminalId    string                 `protobuf:"bytes,1,opt,name=terminal_id,json=terminalId,proto3" json:"terminal_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDataRequest) Reset() {
+	*x = GetDataRequest{}
+	mi := &file_data_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDataRequest) ProtoMessage() {}
+
+func (x *GetDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDataRequest.ProtoReflect.Descriptor instead.
+func (*GetDataRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetDataRequest) GetTerminalId() string {
+	if x != nil {
+		return x.TerminalId
+	}
+	return ""
+}
+
+// UpdateDataRequest takes the body of PUT /api/v1/data/{terminal_id}.
+// Empty fields are left unchanged.
+type UpdateDataRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TerminalId     string                 `protobuf:"bytes,1,opt,name=terminal_id,json=terminalId,proto3" json:"terminal_id,omitempty"`
+	Priority       string                 `protobuf:"bytes,2,opt,name=priority,proto3" json:"priority,omitempty"`
+	Mode           string                 `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`
+	CurrentProblem string                 `protobuf:"bytes,4,opt,name=current_problem,json=currentProblem,proto3" json:"current_problem,omitempty"`
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Remarks        string                 `protobuf:"bytes,6,opt,name=remarks,proto3" json:"remarks,omitempty"`
+	Condition      string                 `protobuf:"bytes,7,opt,name=condition,proto3" json:"condition,omitempty"`
+	CloseTime      string                 `protobuf:"bytes,8,opt,name=close_time,json=closeTime,proto3" json:"close_time,omitempty"` // RFC 3339 or any accepted ticket time format
+	ProblemHistory string                 `protobuf:"bytes,9,opt,name=problem_history,json=problemHistory,proto3" json:"problem_history,omitempty"`
+	ModeHistory    string                 `protobuf:"bytes,10,opt,name=mode_history,json=modeHistory,proto3" json:"mode_history,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateDataRequest) Reset() {
+	*x = UpdateDataRequest{}
+	mi := &file_data_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDataRequest) ProtoMessage() {}
+
+func (x *UpdateDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDataRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDataRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateDataRequest) GetTerminalId() string {
+	if x != nil {
+		return x.TerminalId
+	}
+	return ""
+}
+
+func (x *UpdateDataRequest) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *UpdateDataRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *UpdateDataRequest) GetCurrentProblem() string {
+	if x != nil {
+		return x.CurrentProblem
+	}
+	return ""
+}
+
+func (x *UpdateDataRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *UpdateDataRequest) GetRemarks() string {
+	if x != nil {
+		return x.Remarks
+	}
+	return ""
+}
+
+func (x *UpdateDataRequest) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *UpdateDataRequest) GetCloseTime() string {
+	if x != nil {
+		return x.CloseTime
+	}
+	return ""
+}
+
+func (x *UpdateDataRequest) GetProblemHistory() string {
+	if x != nil {
+		return x.ProblemHistory
+	}
+	return ""
+}
+
+func (x *UpdateDataRequest) GetModeHistory() string {
+	if x != nil {
+		return x.ModeHistory
+	}
+	return ""
+}
+
+type WatchDataRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LastEventId   string                 `protobuf:"bytes,1,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"` // ID of the last event received, to resume
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDataRequest) Reset() {
+	*x = WatchDataRequest{}
+	mi := &file_data_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDataRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDataRequest) ProtoMessage() {}
+
+func (x *WatchDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDataRequest.ProtoReflect.Descriptor instead.
+func (*WatchDataRequest) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WatchDataRequest) GetLastEventId() string {
+	if x != nil {
+		return x.LastEventId
+	}
+	return ""
+}
+
+// ChangeEvent is one ticket change. type is created, updated or closed, or
+// reset when the events after last_event_id are no longer held and the
+// client should re-list instead.
+type ChangeEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	TerminalId    string                 `protobuf:"bytes,3,opt,name=terminal_id,json=terminalId,proto3" json:"terminal_id,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	Data          *DataRow               `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"` // row after the change (last known row for removals)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeEvent) Reset() {
+	*x = ChangeEvent{}
+	mi := &file_data_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeEvent) ProtoMessage() {}
+
+func (x *ChangeEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_data_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeEvent.ProtoReflect.Descriptor instead.
+func (*ChangeEvent) Descriptor() ([]byte, []int) {
+	return file_data_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ChangeEvent) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ChangeEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ChangeEvent) GetTerminalId() string {
+	if x != nil {
+		return x.TerminalId
+	}
+	return ""
+}
+
+func (x *ChangeEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *ChangeEvent) GetData() *DataRow {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_data_proto protoreflect.FileDescriptor
+
+const file_data_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"data.proto\x12\x12apigateway.data.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9b\r\n" +
+	"\aDataRow\x12\x1f\n" +
+	"\vterminal_id\x18\x01 \x01(\tR\n" +
+	"terminalId\x12#\n" +
+	"\rterminal_name\x18\x02 \x01(\tR\fterminalName\x12\x1f\n" +
+	"\bpriority\x18\x03 \x01(\tH\x00R\bpriority\x88\x01\x01\x12\x17\n" +
+	"\x04mode\x18\x04 \x01(\tH\x01R\x04mode\x88\x01\x01\x12,\n" +
+	"\x0finitial_problem\x18\x05 \x01(\tH\x02R\x0einitialProblem\x88\x01\x01\x12,\n" +
+	"\x0fcurrent_problem\x18\x06 \x01(\tH\x03R\x0ecurrentProblem\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"p_duration\x18\a \x01(\tH\x04R\tpDuration\x88\x01\x01\x12R\n" +
+	"\x17incident_start_datetime\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x15incidentStartDatetime\x12\x14\n" +
+	"\x05count\x18\t \x01(\x03R\x05count\x12\x1b\n" +
+	"\x06status\x18\n" +
+	" \x01(\tH\x05R\x06status\x88\x01\x01\x12\x1d\n" +
+	"\aremarks\x18\v \x01(\tH\x06R\aremarks\x88\x01\x01\x12\x18\n" +
+	"\abalance\x18\f \x01(\x03R\abalance\x12!\n" +
+	"\tcondition\x18\r \x01(\tH\aR\tcondition\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"tickets_no\x18\x0e \x01(\tH\bR\tticketsNo\x88\x01\x01\x12)\n" +
+	"\x10tickets_duration\x18\x0f \x01(\x01R\x0fticketsDuration\x127\n" +
+	"\topen_time\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\bopenTime\x129\n" +
+	"\n" +
+	"close_time\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tcloseTime\x12,\n" +
+	"\x0fproblem_history\x18\x12 \x01(\tH\tR\x0eproblemHistory\x88\x01\x01\x12&\n" +
+	"\fmode_history\x18\x13 \x01(\tH\n" +
+	"R\vmodeHistory\x88\x01\x01\x12\x1c\n" +
+	"\adsp_flm\x18\x14 \x01(\tH\vR\x06dspFlm\x88\x01\x01\x12\x1c\n" +
+	"\adsp_slm\x18\x15 \x01(\tH\fR\x06dspSlm\x88\x01\x01\x12C\n" +
+	"\x0flast_withdrawal\x18\x16 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastWithdrawal\x12$\n" +
+	"\vexport_name\x18\x17 \x01(\tH\rR\n" +
+	"exportName\x88\x01\x01\x12\x1e\n" +
+	"\bflm_name\x18\x18 \x01(\tH\x0eR\aflmName\x88\x01\x01\x12\x15\n" +
+	"\x03flm\x18\x19 \x01(\tH\x0fR\x03flm\x88\x01\x01\x12\x15\n" +
+	"\x03slm\x18\x1a \x01(\tH\x10R\x03slm\x88\x01\x01\x12\x15\n" +
+	"\x03net\x18\x1b \x01(\tH\x11R\x03net\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"sla_policy\x18\x1c \x01(\tH\x12R\tslaPolicy\x88\x01\x01\x128\n" +
+	"\n" +
+	"sla_due_at\x18\x1d \x01(\v2\x1a.google.protobuf.TimestampR\bslaDueAt\x127\n" +
+	"\x15sla_remaining_minutes\x18\x1e \x01(\x03H\x13R\x13slaRemainingMinutes\x88\x01\x01\x12&\n" +
+	"\fsla_breached\x18\x1f \x01(\bH\x14R\vslaBreached\x88\x01\x01\x12I\n" +
+	"\x13sla_response_due_at\x18  \x01(\v2\x1a.google.protobuf.TimestampR\x10slaResponseDueAt\x127\n" +
+	"\x15sla_response_breached\x18! \x01(\bH\x15R\x13slaResponseBreached\x88\x01\x01B\v\n" +
+	"\t_priorityB\a\n" +
+	"\x05_modeB\x12\n" +
+	"\x10_initial_problemB\x12\n" +
+	"\x10_current_problemB\r\n" +
+	"\v_p_durationB\t\n" +
+	"\a_statusB\n" +
+	"\n" +
+	"\b_remarksB\f\n" +
+	"\n" +
+	"_conditionB\r\n" +
+	"\v_tickets_noB\x12\n" +
+	"\x10_problem_historyB\x0f\n" +
+	"\r_mode_historyB\n" +
+	"\n" +
+	"\b_dsp_flmB\n" +
+	"\n" +
+	"\b_dsp_slmB\x0e\n" +
+	"\f_export_nameB\v\n" +
+	"\t_flm_nameB\x06\n" +
+	"\x04_flmB\x06\n" +
+	"\x04_slmB\x06\n" +
+	"\x04_netB\r\n" +
+	"\v_sla_policyB\x18\n" +
+	"\x16_sla_remaining_minutesB\x0f\n" +
+	"\r_sla_breachedB\x18\n" +
+	"\x16_sla_response_breached\"\x93\x02\n" +
+	"\x0fListDataRequest\x12\x12\n" +
+	"\x04page\x18\x01 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x17\n" +
+	"\asort_by\x18\x03 \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\x04 \x01(\tR\tsortOrder\x12\x16\n" +
+	"\x06search\x18\x05 \x01(\tR\x06search\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x12\n" +
+	"\x04mode\x18\a \x01(\tR\x04mode\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\tR\bpriority\x12&\n" +
+	"\fsla_breached\x18\t \x01(\bH\x00R\vslaBreached\x88\x01\x01B\x0f\n" +
+	"\r_sla_breached\"1\n" +
+	"\x0eGetDataRequest\x12\x1f\n" +
+	"\vterminal_id\x18\x01 \x01(\tR\n" +
+	"terminalId\"\xc8\x02\n" +
+	"\x11UpdateDataRequest\x12\x1f\n" +
+	"\vterminal_id\x18\x01 \x01(\tR\n" +
+	"terminalId\x12\x1a\n" +
+	"\bpriority\x18\x02 \x01(\tR\bpriority\x12\x12\n" +
+	"\x04mode\x18\x03 \x01(\tR\x04mode\x12'\n" +
+	"\x0fcurrent_problem\x18\x04 \x01(\tR\x0ecurrentProblem\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x18\n" +
+	"\aremarks\x18\x06 \x01(\tR\aremarks\x12\x1c\n" +
+	"\tcondition\x18\a \x01(\tR\tcondition\x12\x1d\n" +
+	"\n" +
+	"close_time\x18\b \x01(\tR\tcloseTime\x12'\n" +
+	"\x0fproblem_history\x18\t \x01(\tR\x0eproblemHistory\x12!\n" +
+	"\fmode_history\x18\n" +
+	" \x01(\tR\vmodeHistory\"6\n" +
+	"\x10WatchDataRequest\x12\"\n" +
+	"\rlast_event_id\x18\x01 \x01(\tR\vlastEventId\"\xc0\x01\n" +
+	"\vChangeEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1f\n" +
+	"\vterminal_id\x18\x03 \x01(\tR\n" +
+	"terminalId\x12;\n" +
+	"\voccurred_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12/\n" +
+	"\x04data\x18\x05 \x01(\v2\x1b.apigateway.data.v1.DataRowR\x04data2\xd1\x02\n" +
+	"\vDataService\x12N\n" +
+	"\bListData\x12#.apigateway.data.v1.ListDataRequest\x1a\x1b.apigateway.data.v1.DataRow0\x01\x12J\n" +
+	"\aGetData\x12\".apigateway.data.v1.GetDataRequest\x1a\x1b.apigateway.data.v1.DataRow\x12P\n" +
+	"\n" +
+	"UpdateData\x12%.apigateway.data.v1.UpdateDataRequest\x1a\x1b.apigateway.data.v1.DataRow\x12T\n" +
+	"\tWatchData\x12$.apigateway.data.v1.WatchDataRequest\x1a\x1f.apigateway.data.v1.ChangeEvent0\x01B!Z\x1fapi-gateway/proto/datapb;datapbb\x06proto3"
+
+var (
+	file_data_proto_rawDescOnce sync.Once
+	file_data_proto_rawDescData []byte
+)
+
+func file_data_proto_rawDescGZIP() []byte {
+	file_data_proto_rawDescOnce.Do(func() {
+		file_data_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_data_proto_rawDesc), len(file_data_proto_rawDesc)))
+	})
+	return file_data_proto_rawDescData
+}
+
+var file_data_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_data_proto_goTypes = []any{
+	(*DataRow)(nil),               // 0: apigateway.data.v1.DataRow
+	(*ListDataRequest)(nil),       // 1: apigateway.data.v1.ListDataRequest
+	(*GetDataRequest)(nil),        // 2: apigateway.data.v1.GetDataRequest
+	(*UpdateDataRequest)(nil),     // 3: apigateway.data.v1.UpdateDataRequest
+	(*WatchDataRequest)(nil),      // 4: apigateway.data.v1.WatchDataRequest
+	(*ChangeEvent)(nil),           // 5: apigateway.data.v1.ChangeEvent
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+}
+var file_data_proto_depIdxs = []int32{
+	6,  // 0: apigateway.data.v1.DataRow.incident_start_datetime:type_name -> google.protobuf.Timestamp
+	6,  // 1: apigateway.data.v1.DataRow.open_time:type_name -> google.protobuf.Timestamp
+	6,  // 2: apigateway.data.v1.DataRow.close_time:type_name -> google.protobuf.Timestamp
+	6,  // 3: apigateway.data.v1.DataRow.last_withdrawal:type_name -> google.protobuf.Timestamp
+	6,  // 4: apigateway.data.v1.DataRow.sla_due_at:type_name -> google.protobuf.Timestamp
+	6,  // 5: apigateway.data.v1.DataRow.sla_response_due_at:type_name -> google.protobuf.Timestamp
+	6,  // 6: apigateway.data.v1.ChangeEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	0,  // 7: apigateway.data.v1.ChangeEvent.data:type_name -> apigateway.data.v1.DataRow
+	1,  // 8: apigateway.data.v1.DataService.ListData:input_type -> apigateway.data.v1.ListDataRequest
+	2,  // 9: apigateway.data.v1.DataService.GetData:input_type -> apigateway.data.v1.GetDataRequest
+	3,  // 10: apigateway.data.v1.DataService.UpdateData:input_type -> apigateway.data.v1.UpdateDataRequest
+	4,  // 11: apigateway.data.v1.DataService.WatchData:input_type -> apigateway.data.v1.WatchDataRequest
+	0,  // 12: apigateway.data.v1.DataService.ListData:output_type -> apigateway.data.v1.DataRow
+	0,  // 13: apigateway.data.v1.DataService.GetData:output_type -> apigateway.data.v1.DataRow
+	0,  // 14: apigateway.data.v1.DataService.UpdateData:output_type -> apigateway.data.v1.DataRow
+	5,  // 15: apigateway.data.v1.DataService.WatchData:output_type -> apigateway.data.v1.ChangeEvent
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_data_proto_init() }
+func file_data_proto_init() {
+	if File_data_proto != nil {
+		return
+	}
+	file_data_proto_msgTypes[0].OneofWrappers = []any{}
+	file_data_proto_msgTypes[1].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_data_proto_rawDesc), len(file_data_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_data_proto_goTypes,
+		DependencyIndexes: file_data_proto_depIdxs,
+		MessageInfos:      file_data_proto_msgTypes,
+	}.Build()
+	File_data_proto = out.File
+	file_data_proto_goTypes = nil
+	file_data_proto_depIdxs = nil
+}
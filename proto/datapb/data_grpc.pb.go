@@ -0,0 +1,266 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: data.proto
+
+// gRPC counterpart of the /api/v1/data endpoints. Field names and values match
+// the REST JSON; nullable columns are proto3 optional fields and unset
+// timestamps mean NULL (or an unparseable stored value).
+
+package datapb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DataService_ListData_FullMethodName   = "/apigateway.data.v1.DataService/ListData"
+	DataService_GetData_FullMethodName    = "/apigateway.data.v1.DataService/GetData"
+	DataService_UpdateData_FullMethodName = "/apigateway.data.v1.DataService/UpdateData"
+	DataService_WatchData_FullMethodName  = "/apigateway.data.v1.DataService/WatchData"
+)
+
+// DataServiceClient is the client API for DataService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DataService serves ticket + machine rows to internal services.
+// Every call must send an API token in the "x-api-token" metadata key; vendor
+// scoping, rate limits and usage logging are the same as on the REST API.
+type DataServiceClient interface {
+	// ListData streams the rows matching the request, one message per row.
+	// With page = 0 every matching row is streamed.
+	ListData(ctx context.Context, in *ListDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DataRow], error)
+	// GetData returns one row by terminal ID (NOT_FOUND outside the token's scope).
+	GetData(ctx context.Context, in *GetDataRequest, opts ...grpc.CallOption) (*DataRow, error)
+	// UpdateData changes ticket fields and returns the updated row.
+	UpdateData(ctx context.Context, in *UpdateDataRequest, opts ...grpc.CallOption) (*DataRow, error)
+	// WatchData streams ticket changes as they are detected, like
+	// GET /api/v1/data/stream. Pass the last received event ID to resume.
+	WatchData(ctx context.Context, in *WatchDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChangeEvent], error)
+}
+
+type dataServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDataServiceClient(cc grpc.ClientConnInterface) DataServiceClient {
+	return &dataServiceClient{cc}
+}
+
+func (c *dataServiceClient) ListData(ctx context.Context, in *ListDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DataRow], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DataService_ServiceDesc.Streams[0], DataService_ListData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListDataRequest, DataRow]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_ListDataClient = grpc.ServerStreamingClient[DataRow]
+
+func (c *dataServiceClient) GetData(ctx context.Context, in *GetDataRequest, opts ...grpc.CallOption) (*DataRow, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DataRow)
+	err := c.cc.Invoke(ctx, DataService_GetData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) UpdateData(ctx context.Context, in *UpdateDataRequest, opts ...grpc.CallOption) (*DataRow, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DataRow)
+	err := c.cc.Invoke(ctx, DataService_UpdateData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataServiceClient) WatchData(ctx context.Context, in *WatchDataRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChangeEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DataService_ServiceDesc.Streams[1], DataService_WatchData_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchDataRequest, ChangeEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_WatchDataClient = grpc.ServerStreamingClient[ChangeEvent]
+
+// DataServiceServer is the server API for DataService service.
+// All implementations must embed UnimplementedDataServiceServer
+// for forward compatibility.
+//
+// DataService serves ticket + machine rows to internal services.
+// Every call must send an API token in the "x-api-token" metadata key; vendor
+// scoping, rate limits and usage logging are the same as on the REST API.
+type DataServiceServer interface {
+	// ListData streams the rows matching the request, one message per row.
+	// With page = 0 every matching row is streamed.
+	ListData(*ListDataRequest, grpc.ServerStreamingServer[DataRow]) error
+	// GetData returns one row by terminal ID (NOT_FOUND outside the token's scope).
+	GetData(context.Context, *GetDataRequest) (*DataRow, error)
+	// UpdateData changes ticket fields and returns the updated row.
+	UpdateData(context.Context, *UpdateDataRequest) (*DataRow, error)
+	// WatchData streams ticket changes as they are detected, like
+	// GET /api/v1/data/stream. Pass the last received event ID to resume.
+	WatchData(*WatchDataRequest, grpc.ServerStreamingServer[ChangeEvent]) error
+	mustEmbedUnimplementedDataServiceServer()
+}
+
+// UnimplementedDataServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDataServiceServer struct{}
+
+func (UnimplementedDataServiceServer) ListData(*ListDataRequest, grpc.ServerStreamingServer[DataRow]) error {
+	return status.Errorf(codes.Unimplemented, "method ListData not implemented")
+}
+func (UnimplementedDataServiceServer) GetData(context.Context, *GetDataRequest) (*DataRow, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetData not implemented")
+}
+func (UnimplementedDataServiceServer) UpdateData(context.Context, *UpdateDataRequest) (*DataRow, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateData not implemented")
+}
+func (UnimplementedDataServiceServer) WatchData(*WatchDataRequest, grpc.ServerStreamingServer[ChangeEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchData not implemented")
+}
+func (UnimplementedDataServiceServer) mustEmbedUnimplementedDataServiceServer() {}
+func (UnimplementedDataServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeDataServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DataServiceServer will
+// result in compilation errors.
+type UnsafeDataServiceServer interface {
+	mustEmbedUnimplementedDataServiceServer()
+}
+
+func RegisterDataServiceServer(s grpc.ServiceRegistrar, srv DataServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDataServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DataService_ServiceDesc, srv)
+}
+
+func _DataService_ListData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataServiceServer).ListData(m, &grpc.GenericServerStream[ListDataRequest, DataRow]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_ListDataServer = grpc.ServerStreamingServer[DataRow]
+
+func _DataService_GetData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).GetData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_GetData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).GetData(ctx, req.(*GetDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_UpdateData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataServiceServer).UpdateData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DataService_UpdateData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataServiceServer).UpdateData(ctx, req.(*UpdateDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataService_WatchData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDataRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataServiceServer).WatchData(m, &grpc.GenericServerStream[WatchDataRequest, ChangeEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DataService_WatchDataServer = grpc.ServerStreamingServer[ChangeEvent]
+
+// DataService_ServiceDesc is the grpc.ServiceDesc for DataService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DataService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "apigateway.data.v1.DataService",
+	HandlerType: (*DataServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetData",
+			Handler:    _DataService_GetData_Handler,
+		},
+		{
+			MethodName: "UpdateData",
+			Handler:    _DataService_UpdateData_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListData",
+			Handler:       _DataService_ListData_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchData",
+			Handler:       _DataService_WatchData_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "data.proto",
+}
@@ -0,0 +1,354 @@
+package repository
+
+import (
+	"api-gateway/models"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CloudSyncRepository handles the cloud app sync queue and reconciliation history.
+// Both live in the token_management database.
+type CloudSyncRepository struct {
+	db     *sql.DB
+	logger *logrus.Logger
+}
+
+// NewCloudSyncRepository creates a new cloud sync repository instance
+func NewCloudSyncRepository(db *sql.DB, logger *logrus.Logger) *CloudSyncRepository {
+	return &CloudSyncRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ============================================================================
+// Queue
+// ============================================================================
+
+const cloudSyncItemSelectQuery = `
+	SELECT id, terminal_id, source, payload, status, attempts, next_attempt_at,
+	       last_status_code, ISNULL(last_error, ''), synced_at, created_at
+	FROM cloud_sync_queue
+`
+
+// scanItem scans a row into a CloudSyncItem struct
+func (r *CloudSyncRepository) scanItem(row interface {
+	Scan(dest ...interface{}) error
+}) (*models.CloudSyncItem, error) {
+	var item models.CloudSyncItem
+	var statusCode sql.NullInt64
+	var syncedAt sql.NullTime
+
+	err := row.Scan(
+		&item.ID, &item.TerminalID, &item.Source, &item.Payload, &item.Status,
+		&item.Attempts, &item.NextAttemptAt, &statusCode, &item.LastError,
+		&syncedAt, &item.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode.Valid {
+		v := int(statusCode.Int64)
+		item.LastStatusCode = &v
+	}
+	if syncedAt.Valid {
+		item.SyncedAt = &syncedAt.Time
+	}
+	return &item, nil
+}
+
+// scanItems scans every row of a queue query
+func (r *CloudSyncRepository) scanItems(rows *sql.Rows) ([]*models.CloudSyncItem, error) {
+	defer rows.Close()
+
+	items := []*models.CloudSyncItem{}
+	for rows.Next() {
+		item, err := r.scanItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// Enqueue writes ticket snapshots to the queue. Older pending snapshots of the same
+// terminals are marked superseded in the same transaction, since only the latest
+// state needs to reach the cloud app.
+func (r *CloudSyncRepository) Enqueue(items []*models.CloudSyncItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, item := range items {
+		if _, err := tx.Exec(`
+			UPDATE cloud_sync_queue SET status = 'superseded'
+			WHERE terminal_id = @p1 AND status = 'pending'
+		`, item.TerminalID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO cloud_sync_queue (terminal_id, source, payload)
+			VALUES (@p1, @p2, @p3)
+		`, item.TerminalID, item.Source, item.Payload); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ClaimDue leases up to limit pending items that are due by pushing their
+// next_attempt_at forward by lease. A worker that dies mid-push leaves the item
+// to be retried once the lease expires, and concurrent gateway instances claim
+// disjoint rows.
+func (r *CloudSyncRepository) ClaimDue(limit int, lease time.Duration) ([]*models.CloudSyncItem, error) {
+	query := `
+		UPDATE TOP (@p1) cloud_sync_queue
+		SET next_attempt_at = DATEADD(SECOND, @p2, GETDATE())
+		OUTPUT INSERTED.id, INSERTED.terminal_id, INSERTED.source, INSERTED.payload,
+		       INSERTED.status, INSERTED.attempts, INSERTED.next_attempt_at,
+		       INSERTED.last_status_code, ISNULL(INSERTED.last_error, ''),
+		       INSERTED.synced_at, INSERTED.created_at
+		WHERE status = 'pending' AND next_attempt_at <= GETDATE()
+	`
+	rows, err := r.db.Query(query, limit, int(lease.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	return r.scanItems(rows)
+}
+
+// MarkSynced records a successful push. A snapshot superseded while in flight
+// keeps its superseded status.
+func (r *CloudSyncRepository) MarkSynced(id int64, attempts, statusCode int) error {
+	_, err := r.db.Exec(`
+		UPDATE cloud_sync_queue
+		SET status = CASE WHEN status = 'superseded' THEN status ELSE 'synced' END,
+		    attempts = @p1, last_attempt_at = GETDATE(), last_status_code = @p2, last_error = NULL,
+		    synced_at = GETDATE()
+		WHERE id = @p3
+	`, attempts, statusCode, id)
+	return err
+}
+
+// MarkFailed records a failed attempt and either schedules the next one or, when
+// final is true, moves the item to the failed state
+func (r *CloudSyncRepository) MarkFailed(id int64, attempts int, statusCode *int, errMsg string, nextAttempt time.Time, final bool) error {
+	status := models.CloudSyncPending
+	if final {
+		status = models.CloudSyncFailed
+	}
+	if len(errMsg) > 1000 {
+		errMsg = errMsg[:1000]
+	}
+	_, err := r.db.Exec(`
+		UPDATE cloud_sync_queue
+		SET status = @p1, attempts = @p2, last_attempt_at = GETDATE(), last_status_code = @p3,
+		    last_error = @p4, next_attempt_at = @p5
+		WHERE id = @p6 AND status = 'pending'
+	`, status, attempts, statusCode, errMsg, nextAttempt, id)
+	return err
+}
+
+// ListItems retrieves the most recent queue items, optionally narrowed to one status
+func (r *CloudSyncRepository) ListItems(status string, limit int) ([]*models.CloudSyncItem, error) {
+	query := strings.Replace(cloudSyncItemSelectQuery, "SELECT", "SELECT TOP (@p1)", 1)
+	args := []interface{}{limit}
+	if status != "" {
+		query += " WHERE status = @p2"
+		args = append(args, status)
+	}
+	query += " ORDER BY id DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return r.scanItems(rows)
+}
+
+// RequeueItem resets a failed item to be attempted again now. Items superseded by
+// a newer snapshot of the same terminal are not requeued.
+func (r *CloudSyncRepository) RequeueItem(id int64) error {
+	result, err := r.db.Exec(`
+		UPDATE cloud_sync_queue
+		SET status = 'pending', attempts = 0, next_attempt_at = GETDATE()
+		WHERE id = @p1 AND status = 'failed'
+	`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("cloud sync item %w or not failed", ErrNotFound)
+	}
+	return nil
+}
+
+// GetQueueStats counts queue items by status and reports the latest outcomes
+func (r *CloudSyncRepository) GetQueueStats() (*models.CloudSyncQueueStats, error) {
+	var stats models.CloudSyncQueueStats
+
+	rows, err := r.db.Query(`SELECT status, COUNT(*) FROM cloud_sync_queue GROUP BY status`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		switch status {
+		case models.CloudSyncPending:
+			stats.Pending = count
+		case models.CloudSyncSynced:
+			stats.Synced = count
+		case models.CloudSyncFailed:
+			stats.Failed = count
+		case models.CloudSyncSuperseded:
+			stats.Superseded = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var oldestPending, lastSynced sql.NullTime
+	err = r.db.QueryRow(`
+		SELECT (SELECT MIN(created_at) FROM cloud_sync_queue WHERE status = 'pending'),
+		       (SELECT MAX(synced_at) FROM cloud_sync_queue WHERE synced_at IS NOT NULL)
+	`).Scan(&oldestPending, &lastSynced)
+	if err != nil {
+		return nil, err
+	}
+	if oldestPending.Valid {
+		stats.OldestPendingAt = &oldestPending.Time
+	}
+	if lastSynced.Valid {
+		stats.LastSyncedAt = &lastSynced.Time
+	}
+
+	// Most recent failed attempt, whether or not it will be retried
+	var failedAt time.Time
+	var lastError string
+	err = r.db.QueryRow(`
+		SELECT TOP 1 last_attempt_at, last_error FROM cloud_sync_queue
+		WHERE last_error IS NOT NULL
+		ORDER BY last_attempt_at DESC
+	`).Scan(&failedAt, &lastError)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil {
+		stats.LastFailureAt = &failedAt
+		stats.LastFailureError = lastError
+	}
+	return &stats, nil
+}
+
+// ============================================================================
+// Reconciliations
+// ============================================================================
+
+const cloudSyncReconciliationSelectQuery = `
+	SELECT id, started_at, finished_at, triggered_by, local_count, remote_count, matched,
+	       mismatched, missing_remote, extra_remote, requeued, ISNULL(error, ''), ISNULL(details, '')
+	FROM cloud_sync_reconciliations
+`
+
+// scanReconciliation scans a row into a CloudSyncReconciliation struct
+func (r *CloudSyncRepository) scanReconciliation(row interface {
+	Scan(dest ...interface{}) error
+}) (*models.CloudSyncReconciliation, error) {
+	var rec models.CloudSyncReconciliation
+	var triggeredBy sql.NullInt64
+	var details string
+
+	err := row.Scan(
+		&rec.ID, &rec.StartedAt, &rec.FinishedAt, &triggeredBy, &rec.LocalCount,
+		&rec.RemoteCount, &rec.Matched, &rec.Mismatched, &rec.MissingRemote,
+		&rec.ExtraRemote, &rec.Requeued, &rec.Error, &details,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if triggeredBy.Valid {
+		v := int(triggeredBy.Int64)
+		rec.TriggeredBy = &v
+	}
+	if details != "" {
+		if err := json.Unmarshal([]byte(details), &rec.Details); err != nil {
+			r.logger.Warnf("Invalid details JSON on cloud sync reconciliation %d: %v", rec.ID, err)
+		}
+	}
+	return &rec, nil
+}
+
+// CreateReconciliation records a reconciliation run
+func (r *CloudSyncRepository) CreateReconciliation(rec *models.CloudSyncReconciliation) error {
+	var details interface{}
+	if len(rec.Details) > 0 {
+		b, err := json.Marshal(rec.Details)
+		if err != nil {
+			return err
+		}
+		details = string(b)
+	}
+	var errMsg interface{}
+	if rec.Error != "" {
+		errMsg = rec.Error
+		if len(rec.Error) > 1000 {
+			errMsg = rec.Error[:1000]
+		}
+	}
+
+	query := `
+		INSERT INTO cloud_sync_reconciliations (
+			started_at, finished_at, triggered_by, local_count, remote_count, matched,
+			mismatched, missing_remote, extra_remote, requeued, error, details
+		)
+		OUTPUT INSERTED.id
+		VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10, @p11, @p12)
+	`
+	return r.db.QueryRow(query,
+		rec.StartedAt, rec.FinishedAt, rec.TriggeredBy, rec.LocalCount, rec.RemoteCount,
+		rec.Matched, rec.Mismatched, rec.MissingRemote, rec.ExtraRemote, rec.Requeued,
+		errMsg, details,
+	).Scan(&rec.ID)
+}
+
+// ListReconciliations retrieves the most recent reconciliation runs
+func (r *CloudSyncRepository) ListReconciliations(limit int) ([]*models.CloudSyncReconciliation, error) {
+	query := strings.Replace(cloudSyncReconciliationSelectQuery, "SELECT", "SELECT TOP (@p1)", 1) +
+		" ORDER BY id DESC"
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recs := []*models.CloudSyncReconciliation{}
+	for rows.Next() {
+		rec, err := r.scanReconciliation(rows)
+		if err != nil {
+			return nil, err
+		}
+		recs = append(recs, rec)
+	}
+	return recs, rows.Err()
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"api-gateway/models"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CriticalityRepository handles database operations for criticality rules.
+// Rules live in the token_management database alongside other admin-managed data.
+type CriticalityRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+	logger   *logrus.Logger
+}
+
+// NewCriticalityRepository creates a new criticality rule repository instance
+func NewCriticalityRepository(db *sql.DB, timeouts Timeouts, logger *logrus.Logger) *CriticalityRepository {
+	return &CriticalityRepository{
+		db:       db,
+		timeouts: timeouts,
+		logger:   logger,
+	}
+}
+
+const criticalityRuleSelectQuery = `
+	SELECT id, name, ISNULL(description, '') as description,
+	       priority, mode, status, min_tickets_duration, max_balance, region,
+	       is_active, created_at, updated_at, created_by
+	FROM criticality_rules
+`
+
+// scanRule scans a row into a CriticalityRule struct
+func (r *CriticalityRepository) scanRule(row interface {
+	Scan(dest ...interface{}) error
+}) (*models.CriticalityRule, error) {
+	var rule models.CriticalityRule
+	var priority, mode, status, region sql.NullString
+	var minDuration sql.NullFloat64
+	var maxBalance, createdBy sql.NullInt64
+
+	err := row.Scan(
+		&rule.ID, &rule.Name, &rule.Description,
+		&priority, &mode, &status, &minDuration, &maxBalance, &region,
+		&rule.IsActive, &rule.CreatedAt, &rule.UpdatedAt, &createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if priority.Valid {
+		rule.Priority = &priority.String
+	}
+	if mode.Valid {
+		rule.Mode = &mode.String
+	}
+	if status.Valid {
+		rule.Status = &status.String
+	}
+	if region.Valid {
+		rule.Region = &region.String
+	}
+	if minDuration.Valid {
+		rule.MinTicketsDuration = &minDuration.Float64
+	}
+	if maxBalance.Valid {
+		v := int(maxBalance.Int64)
+		rule.MaxBalance = &v
+	}
+	if createdBy.Valid {
+		v := int(createdBy.Int64)
+		rule.CreatedBy = &v
+	}
+	return &rule, nil
+}
+
+// GetAllRules retrieves all criticality rules, optionally only active ones
+func (r *CriticalityRepository) GetAllRules(ctx context.Context, activeOnly bool) (_ []*models.CriticalityRule, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
+	query := criticalityRuleSelectQuery
+	if activeOnly {
+		query += ` WHERE is_active = 1`
+	}
+	query += ` ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*models.CriticalityRule
+	for rows.Next() {
+		rule, err := r.scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
+
+// GetRuleByID retrieves a criticality rule by ID
+func (r *CriticalityRepository) GetRuleByID(ctx context.Context, id int) (_ *models.CriticalityRule, err error) {
+	ctx, done := r.timeouts.read(ctx, &err)
+	defer done()
+
+	row := r.db.QueryRowContext(ctx, criticalityRuleSelectQuery+` WHERE id = @p1`, id)
+	rule, err := r.scanRule(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("criticality rule %w", ErrNotFound)
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// CreateRule inserts a new criticality rule and returns its ID
+func (r *CriticalityRepository) CreateRule(ctx context.Context, rule *models.CriticalityRule, createdBy int) (_ int, err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	query := `
+		INSERT INTO criticality_rules (
+			name, description, priority, mode, status,
+			min_tickets_duration, max_balance, region, is_active, created_by
+		)
+		OUTPUT INSERTED.id
+		VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10)
+	`
+	var id int
+	err = r.db.QueryRowContext(ctx, query,
+		rule.Name, rule.Description, rule.Priority, rule.Mode, rule.Status,
+		rule.MinTicketsDuration, rule.MaxBalance, rule.Region, rule.IsActive, createdBy,
+	).Scan(&id)
+	return id, err
+}
+
+// UpdateRule replaces all fields of an existing criticality rule
+func (r *CriticalityRepository) UpdateRule(ctx context.Context, rule *models.CriticalityRule) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	query := `
+		UPDATE criticality_rules
+		SET name = @p1, description = @p2, priority = @p3, mode = @p4, status = @p5,
+		    min_tickets_duration = @p6, max_balance = @p7, region = @p8,
+		    is_active = @p9, updated_at = GETDATE()
+		WHERE id = @p10
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		rule.Name, rule.Description, rule.Priority, rule.Mode, rule.Status,
+		rule.MinTicketsDuration, rule.MaxBalance, rule.Region, rule.IsActive, rule.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("criticality rule %w", ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteRule deletes a criticality rule permanently
+func (r *CriticalityRepository) DeleteRule(ctx context.Context, id int) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	_, err = r.db.ExecContext(ctx, `DELETE FROM criticality_rules WHERE id = @p1`, id)
+	return err
+}
@@ -0,0 +1,23 @@
+package repository
+
+import "errors"
+
+// Repository errors
+// Callers match these with errors.Is; the service package re-exports them
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrNotAccessible    = errors.New("not found or not accessible for this vendor")
+	ErrNoFieldsToUpdate = errors.New("no fields to update")
+	ErrQueryTimeout     = errors.New("database query timed out")
+)
+
+// isDuplicateKey reports whether err is SQL Server's unique index (2601) or
+// unique constraint (2627) violation.
+func isDuplicateKey(err error) bool {
+	var sqlErr interface{ SQLErrorNumber() int32 }
+	if !errors.As(err, &sqlErr) {
+		return false
+	}
+	n := sqlErr.SQLErrorNumber()
+	return n == 2601 || n == 2627
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"api-gateway/models"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// IdempotencyRepository stores Idempotency-Key records in the token_management
+// database.
+type IdempotencyRepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+	logger   *logrus.Logger
+}
+
+// NewIdempotencyRepository creates a new idempotency repository instance
+func NewIdempotencyRepository(db *sql.DB, timeouts Timeouts, logger *logrus.Logger) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		db:       db,
+		timeouts: timeouts,
+		logger:   logger,
+	}
+}
+
+// Reserve claims rec's key for its token. It first drops the token's record
+// for that key if it has expired, or if it is still in progress but older than
+// staleBefore (its request died without finishing). It reports false when the
+// key is held by another record, which the caller then loads with Get.
+func (r *IdempotencyRepository) Reserve(ctx context.Context, rec *models.IdempotencyRecord, staleBefore time.Time) (_ bool, err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE token_id = @p1 AND idempotency_key = @p2
+		  AND (expires_at <= @p6 OR (status = 'in_progress' AND created_at < @p8));
+
+		INSERT INTO idempotency_keys (
+			token_id, idempotency_key, method, path, request_hash, status, created_at, expires_at
+		)
+		OUTPUT INSERTED.id
+		SELECT @p1, @p2, @p3, @p4, @p5, 'in_progress', @p6, @p7
+		WHERE NOT EXISTS (
+			SELECT 1 FROM idempotency_keys WITH (UPDLOCK, HOLDLOCK)
+			WHERE token_id = @p1 AND idempotency_key = @p2
+		);
+	`
+	err = r.db.QueryRowContext(ctx, query,
+		rec.TokenID, rec.Key, rec.Method, rec.Path, rec.RequestHash,
+		rec.CreatedAt, rec.ExpiresAt, staleBefore,
+	).Scan(&rec.ID)
+	if err == sql.ErrNoRows || isDuplicateKey(err) {
+		// Held by another record; a concurrent first request may have
+		// inserted it between our check and our insert
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	rec.Status = models.IdempotencyInProgress
+	return true, nil
+}
+
+// Get returns the token's record for key
+func (r *IdempotencyRepository) Get(ctx context.Context, tokenID int, key string) (_ *models.IdempotencyRecord, err error) {
+	ctx, done := r.timeouts.read(ctx, &err)
+	defer done()
+
+	query := `
+		SELECT id, token_id, idempotency_key, method, path, request_hash, status,
+		       ISNULL(response_status, 0) as response_status,
+		       ISNULL(response_content_type, '') as response_content_type,
+		       response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE token_id = @p1 AND idempotency_key = @p2
+	`
+	var rec models.IdempotencyRecord
+	err = r.db.QueryRowContext(ctx, query, tokenID, key).Scan(
+		&rec.ID, &rec.TokenID, &rec.Key, &rec.Method, &rec.Path, &rec.RequestHash, &rec.Status,
+		&rec.ResponseStatus, &rec.ResponseContentType, &rec.ResponseBody, &rec.CreatedAt, &rec.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// Complete stores the response of a reserved record
+func (r *IdempotencyRepository) Complete(ctx context.Context, id int64, status int, contentType string, body []byte) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	query := `
+		UPDATE idempotency_keys
+		SET status = 'completed', response_status = @p2, response_content_type = @p3,
+		    response_body = @p4, completed_at = GETDATE()
+		WHERE id = @p1
+	`
+	_, err = r.db.ExecContext(ctx, query, id, status, contentType, body)
+	return err
+}
+
+// Delete removes a record, freeing its key
+func (r *IdempotencyRepository) Delete(ctx context.Context, id int64) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	_, err = r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE id = @p1`, id)
+	return err
+}
+
+// DeleteExpired removes every record whose window has passed
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context, now time.Time) (_ int64, err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= @p1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"api-gateway/models"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// machineSelect reads the machine master record (machine_master.dbo.atmi).
+// machine and open_ticket are joined so a token's vendor filter (mm.* or op.*
+// columns) can be applied exactly as on the data endpoint.
+const machineSelect = `
+	SELECT
+		a.[terminal_id],
+		ISNULL(a.[store], ''),
+		ISNULL(a.[store_code], ''),
+		ISNULL(a.[store_name], ''),
+		a.[date_of_activation],
+		ISNULL(a.[status], ''),
+		ISNULL(TRY_CAST(a.[std] AS INT), 0),
+		ISNULL(a.[gps], ''),
+		ISNULL(TRY_CAST(a.[lat] AS FLOAT), 0),
+		ISNULL(TRY_CAST(a.[lon] AS FLOAT), 0),
+		ISNULL(a.[province], ''),
+		ISNULL(a.[city/regency], ''),
+		ISNULL(a.[district], '')
+	FROM machine_master.dbo.atmi a
+	LEFT JOIN machine_master.dbo.machine mm
+		ON a.[terminal_id] = mm.[Terminal ID]
+	LEFT JOIN ticket_master.dbo.open_ticket op
+		ON a.[terminal_id] = op.[Terminal ID]
+`
+
+// MachineRepository reads machine master records.
+type MachineRepository struct {
+	machineDB *sql.DB
+	timeouts  Timeouts
+	logger    *logrus.Logger
+}
+
+// NewMachineRepository creates a new MachineRepository.
+func NewMachineRepository(machineDB *sql.DB, timeouts Timeouts, logger *logrus.Logger) *MachineRepository {
+	return &MachineRepository{
+		machineDB: machineDB,
+		timeouts:  timeouts,
+		logger:    logger,
+	}
+}
+
+// scanMachine scans a single machineSelect row.
+func scanMachine(row interface {
+	Scan(...interface{}) error
+}) (*models.ATMI, error) {
+	m := &models.ATMI{}
+	err := row.Scan(
+		&m.TerminalID, &m.Store, &m.StoreCode, &m.StoreName, &m.DateOfActivation,
+		&m.Status, &m.Std, &m.GPS, &m.Lat, &m.Lon, &m.Province, &m.CityRegency, &m.District,
+	)
+	return m, err
+}
+
+// GetByTerminalID retrieves one machine record with optional vendor scoping.
+func (r *MachineRepository) GetByTerminalID(ctx context.Context, terminalID string, filter *VendorFilter) (_ *models.ATMI, err error) {
+	ctx, done := r.timeouts.read(ctx, &err)
+	defer done()
+
+	conditions, args, _ := appendVendorCondition(filter, []string{"a.[terminal_id] = @p1"}, []interface{}{terminalID}, 2)
+	query := machineSelect + "WHERE " + strings.Join(conditions, " AND ")
+
+	m, err := scanMachine(r.machineDB.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("machine %w", ErrNotFound)
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to get machine by terminal ID: %v", err)
+		return nil, fmt.Errorf("failed to get machine: %w", err)
+	}
+	return m, nil
+}
+
+// GetByTerminalIDs retrieves the machine records of several terminals in one
+// query, keyed by terminal ID. Terminals without a record (or outside the
+// vendor scope) are absent from the map.
+func (r *MachineRepository) GetByTerminalIDs(ctx context.Context, terminalIDs []string, filter *VendorFilter) (_ map[string]*models.ATMI, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
+	result := make(map[string]*models.ATMI, len(terminalIDs))
+	if len(terminalIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(terminalIDs))
+	args := make([]interface{}, len(terminalIDs))
+	for i, id := range terminalIDs {
+		placeholders[i] = fmt.Sprintf("@p%d", i+1)
+		args[i] = id
+	}
+	conditions := []string{fmt.Sprintf("a.[terminal_id] IN (%s)", strings.Join(placeholders, ", "))}
+	conditions, args, _ = appendVendorCondition(filter, conditions, args, len(args)+1)
+
+	rows, err := r.machineDB.QueryContext(ctx, machineSelect+"WHERE "+strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		r.logger.WithContext(ctx).Errorf("Failed to query machines: %v", err)
+		return nil, fmt.Errorf("failed to query machines: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := scanMachine(rows)
+		if err != nil {
+			r.logger.WithContext(ctx).Errorf("Failed to scan machine: %v", err)
+			continue
+		}
+		result[m.TerminalID] = m
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"api-gateway/models"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SLARepository handles database operations for SLA policies and calendars.
+// Both live in the token_management database alongside other admin-managed data.
+type SLARepository struct {
+	db       *sql.DB
+	timeouts Timeouts
+	logger   *logrus.Logger
+}
+
+// NewSLARepository creates a new SLA repository instance
+func NewSLARepository(db *sql.DB, timeouts Timeouts, logger *logrus.Logger) *SLARepository {
+	return &SLARepository{
+		db:       db,
+		timeouts: timeouts,
+		logger:   logger,
+	}
+}
+
+// ============================================================================
+// Calendars
+// ============================================================================
+
+const slaCalendarSelectQuery = `
+	SELECT id, name, timezone, work_days, work_start, work_end,
+	       ISNULL(holidays, '[]') as holidays, created_at, updated_at, created_by
+	FROM sla_calendars
+`
+
+// scanCalendar scans a row into an SLACalendar struct
+func (r *SLARepository) scanCalendar(row interface {
+	Scan(dest ...interface{}) error
+}) (*models.SLACalendar, error) {
+	var cal models.SLACalendar
+	var holidays string
+	var createdBy sql.NullInt64
+
+	err := row.Scan(
+		&cal.ID, &cal.Name, &cal.Timezone, &cal.WorkDays, &cal.WorkStart, &cal.WorkEnd,
+		&holidays, &cal.CreatedAt, &cal.UpdatedAt, &createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cal.Holidays = []string{}
+	if err := json.Unmarshal([]byte(holidays), &cal.Holidays); err != nil {
+		r.logger.Warnf("Invalid holidays JSON on SLA calendar %d: %v", cal.ID, err)
+	}
+	if createdBy.Valid {
+		v := int(createdBy.Int64)
+		cal.CreatedBy = &v
+	}
+	return &cal, nil
+}
+
+// GetAllCalendars retrieves all SLA calendars
+func (r *SLARepository) GetAllCalendars(ctx context.Context) (_ []*models.SLACalendar, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
+	rows, err := r.db.QueryContext(ctx, slaCalendarSelectQuery+` ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var calendars []*models.SLACalendar
+	for rows.Next() {
+		cal, err := r.scanCalendar(rows)
+		if err != nil {
+			return nil, err
+		}
+		calendars = append(calendars, cal)
+	}
+	return calendars, rows.Err()
+}
+
+// GetCalendarByID retrieves an SLA calendar by ID
+func (r *SLARepository) GetCalendarByID(ctx context.Context, id int) (_ *models.SLACalendar, err error) {
+	ctx, done := r.timeouts.read(ctx, &err)
+	defer done()
+
+	cal, err := r.scanCalendar(r.db.QueryRowContext(ctx, slaCalendarSelectQuery+` WHERE id = @p1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("SLA calendar %w", ErrNotFound)
+		}
+		return nil, err
+	}
+	return cal, nil
+}
+
+// CreateCalendar inserts a new SLA calendar and returns its ID
+func (r *SLARepository) CreateCalendar(ctx context.Context, cal *models.SLACalendar, createdBy int) (_ int, err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	query := `
+		INSERT INTO sla_calendars (name, timezone, work_days, work_start, work_end, holidays, created_by)
+		OUTPUT INSERTED.id
+		VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7)
+	`
+	var id int
+	err = r.db.QueryRowContext(ctx, query,
+		cal.Name, cal.Timezone, cal.WorkDays, cal.WorkStart, cal.WorkEnd,
+		cal.HolidaysJSON(), createdBy,
+	).Scan(&id)
+	return id, err
+}
+
+// UpdateCalendar replaces all fields of an existing SLA calendar
+func (r *SLARepository) UpdateCalendar(ctx context.Context, cal *models.SLACalendar) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	query := `
+		UPDATE sla_calendars
+		SET name = @p1, timezone = @p2, work_days = @p3, work_start = @p4,
+		    work_end = @p5, holidays = @p6, updated_at = GETDATE()
+		WHERE id = @p7
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		cal.Name, cal.Timezone, cal.WorkDays, cal.WorkStart, cal.WorkEnd,
+		cal.HolidaysJSON(), cal.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("SLA calendar %w", ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteCalendar deletes an SLA calendar. Fails while a policy still references it.
+func (r *SLARepository) DeleteCalendar(ctx context.Context, id int) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	_, err = r.db.ExecContext(ctx, `DELETE FROM sla_calendars WHERE id = @p1`, id)
+	return err
+}
+
+// ============================================================================
+// Policies
+// ============================================================================
+
+const slaPolicySelectQuery = `
+	SELECT id, name, priority, vendor_name, response_minutes, resolution_minutes,
+	       calendar_id, is_active, created_at, updated_at, created_by
+	FROM sla_policies
+`
+
+// scanPolicy scans a row into an SLAPolicy struct
+func (r *SLARepository) scanPolicy(row interface {
+	Scan(dest ...interface{}) error
+}) (*models.SLAPolicy, error) {
+	var p models.SLAPolicy
+	var vendorName sql.NullString
+	var responseMinutes, calendarID, createdBy sql.NullInt64
+
+	err := row.Scan(
+		&p.ID, &p.Name, &p.Priority, &vendorName, &responseMinutes, &p.ResolutionMinutes,
+		&calendarID, &p.IsActive, &p.CreatedAt, &p.UpdatedAt, &createdBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if vendorName.Valid {
+		p.VendorName = &vendorName.String
+	}
+	if responseMinutes.Valid {
+		v := int(responseMinutes.Int64)
+		p.ResponseMinutes = &v
+	}
+	if calendarID.Valid {
+		v := int(calendarID.Int64)
+		p.CalendarID = &v
+	}
+	if createdBy.Valid {
+		v := int(createdBy.Int64)
+		p.CreatedBy = &v
+	}
+	return &p, nil
+}
+
+// GetAllPolicies retrieves all SLA policies, optionally only active ones
+func (r *SLARepository) GetAllPolicies(ctx context.Context, activeOnly bool) (_ []*models.SLAPolicy, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
+	query := slaPolicySelectQuery
+	if activeOnly {
+		query += ` WHERE is_active = 1`
+	}
+	query += ` ORDER BY priority, vendor_name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []*models.SLAPolicy
+	for rows.Next() {
+		p, err := r.scanPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+// GetPolicyByID retrieves an SLA policy by ID
+func (r *SLARepository) GetPolicyByID(ctx context.Context, id int) (_ *models.SLAPolicy, err error) {
+	ctx, done := r.timeouts.read(ctx, &err)
+	defer done()
+
+	p, err := r.scanPolicy(r.db.QueryRowContext(ctx, slaPolicySelectQuery+` WHERE id = @p1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("SLA policy %w", ErrNotFound)
+		}
+		return nil, err
+	}
+	return p, nil
+}
+
+// CreatePolicy inserts a new SLA policy and returns its ID
+func (r *SLARepository) CreatePolicy(ctx context.Context, p *models.SLAPolicy, createdBy int) (_ int, err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	query := `
+		INSERT INTO sla_policies (
+			name, priority, vendor_name, response_minutes, resolution_minutes,
+			calendar_id, is_active, created_by
+		)
+		OUTPUT INSERTED.id
+		VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8)
+	`
+	var id int
+	err = r.db.QueryRowContext(ctx, query,
+		p.Name, p.Priority, p.VendorName, p.ResponseMinutes, p.ResolutionMinutes,
+		p.CalendarID, p.IsActive, createdBy,
+	).Scan(&id)
+	return id, err
+}
+
+// UpdatePolicy replaces all fields of an existing SLA policy
+func (r *SLARepository) UpdatePolicy(ctx context.Context, p *models.SLAPolicy) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	query := `
+		UPDATE sla_policies
+		SET name = @p1, priority = @p2, vendor_name = @p3, response_minutes = @p4,
+		    resolution_minutes = @p5, calendar_id = @p6, is_active = @p7, updated_at = GETDATE()
+		WHERE id = @p8
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		p.Name, p.Priority, p.VendorName, p.ResponseMinutes, p.ResolutionMinutes,
+		p.CalendarID, p.IsActive, p.ID,
+	)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("SLA policy %w", ErrNotFound)
+	}
+	return nil
+}
+
+// DeletePolicy deletes an SLA policy permanently
+func (r *SLARepository) DeletePolicy(ctx context.Context, id int) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	_, err = r.db.ExecContext(ctx, `DELETE FROM sla_policies WHERE id = @p1`, id)
+	return err
+}
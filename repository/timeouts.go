@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Timeouts bounds how long one repository call may run, by kind of
+// operation. The caller's context still applies, so a client disconnect
+// cancels the query early; a zero duration leaves only the caller's context.
+type Timeouts struct {
+	Read  time.Duration // Single-row lookups, counts, metadata, rate-limit counters
+	List  time.Duration // Multi-row reads and aggregates, including page=0 exports
+	Write time.Duration // Inserts, updates and deletes
+}
+
+func (t Timeouts) read(ctx context.Context, err *error) (context.Context, func()) {
+	return bound(ctx, t.Read, err)
+}
+
+func (t Timeouts) list(ctx context.Context, err *error) (context.Context, func()) {
+	return bound(ctx, t.List, err)
+}
+
+func (t Timeouts) write(ctx context.Context, err *error) (context.Context, func()) {
+	return bound(ctx, t.Write, err)
+}
+
+// bound derives the context for one repository call from ctx, limited to d.
+// The returned function must be deferred: it releases the context and turns
+// the call's error (*err) into ErrQueryTimeout or context.Canceled when the
+// context is why it failed.
+func bound(ctx context.Context, d time.Duration, err *error) (context.Context, func()) {
+	var cancel context.CancelFunc
+	if d > 0 {
+		ctx, cancel = context.WithTimeout(ctx, d)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	return ctx, func() {
+		*err = contextError(ctx, *err)
+		cancel()
+	}
+}
+
+// contextError classifies err by the state of the context it ran under. The
+// driver reports a deadline as context.DeadlineExceeded, as a network timeout
+// or as a cancelled batch depending on where it was interrupted.
+func contextError(ctx context.Context, err error) error {
+	if err == nil || errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) && !errors.Is(err, ErrQueryTimeout):
+		return fmt.Errorf("%w: %v", ErrQueryTimeout, err)
+	case errors.Is(ctx.Err(), context.Canceled) && !errors.Is(err, context.Canceled):
+		return fmt.Errorf("%w: %v", context.Canceled, err)
+	}
+	return err
+}
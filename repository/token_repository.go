@@ -2,25 +2,33 @@ package repository
 
 import (
 	"api-gateway/models"
+	"api-gateway/tracing"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/sirupsen/logrus"
 )
 
+// tokenDBName is the database the token repository's spans are attributed to
+const tokenDBName = "token_management"
+
 // TokenRepository handles database operations for token management
 type TokenRepository struct {
-	db     *sql.DB
-	logger *logrus.Logger
+	db       *sql.DB
+	timeouts Timeouts
+	logger   *logrus.Logger
 }
 
 // NewTokenRepository creates a new token repository instance
-func NewTokenRepository(db *sql.DB, logger *logrus.Logger) *TokenRepository {
+func NewTokenRepository(db *sql.DB, timeouts Timeouts, logger *logrus.Logger) *TokenRepository {
 	return &TokenRepository{
-		db:     db,
-		logger: logger,
+		db:       db,
+		timeouts: timeouts,
+		logger:   logger,
 	}
 }
 
@@ -29,7 +37,10 @@ func NewTokenRepository(db *sql.DB, logger *logrus.Logger) *TokenRepository {
 // ============================================================================
 
 // GetAdminByUsername retrieves an admin user by username
-func (r *TokenRepository) GetAdminByUsername(username string) (*models.AdminUser, error) {
+func (r *TokenRepository) GetAdminByUsername(ctx context.Context, username string) (_ *models.AdminUser, err error) {
+	ctx, done := r.timeouts.read(ctx, &err)
+	defer done()
+
 	query := `
 		SELECT id, username, email, password_hash, ISNULL(full_name, '') as full_name,
 		       role, is_active, last_login_at, ISNULL(last_login_ip, '') as last_login_ip,
@@ -37,11 +48,11 @@ func (r *TokenRepository) GetAdminByUsername(username string) (*models.AdminUser
 		FROM admin_users
 		WHERE username = @p1 AND is_active = 1
 	`
-	row := r.db.QueryRow(query, username)
+	row := r.db.QueryRowContext(ctx, query, username)
 
 	var admin models.AdminUser
 	var createdBy sql.NullInt64
-	err := row.Scan(
+	err = row.Scan(
 		&admin.ID, &admin.Username, &admin.Email, &admin.PasswordHash,
 		&admin.FullName, &admin.Role, &admin.IsActive, &admin.LastLoginAt,
 		&admin.LastLoginIP, &admin.CreatedAt, &admin.UpdatedAt, &createdBy,
@@ -60,7 +71,10 @@ func (r *TokenRepository) GetAdminByUsername(username string) (*models.AdminUser
 }
 
 // GetAdminByID retrieves an admin user by ID
-func (r *TokenRepository) GetAdminByID(id int) (*models.AdminUser, error) {
+func (r *TokenRepository) GetAdminByID(ctx context.Context, id int) (_ *models.AdminUser, err error) {
+	ctx, done := r.timeouts.read(ctx, &err)
+	defer done()
+
 	query := `
 		SELECT id, username, email, password_hash, ISNULL(full_name, '') as full_name,
 		       role, is_active, last_login_at, ISNULL(last_login_ip, '') as last_login_ip,
@@ -68,11 +82,11 @@ func (r *TokenRepository) GetAdminByID(id int) (*models.AdminUser, error) {
 		FROM admin_users
 		WHERE id = @p1
 	`
-	row := r.db.QueryRow(query, id)
+	row := r.db.QueryRowContext(ctx, query, id)
 
 	var admin models.AdminUser
 	var createdBy sql.NullInt64
-	err := row.Scan(
+	err = row.Scan(
 		&admin.ID, &admin.Username, &admin.Email, &admin.PasswordHash,
 		&admin.FullName, &admin.Role, &admin.IsActive, &admin.LastLoginAt,
 		&admin.LastLoginIP, &admin.CreatedAt, &admin.UpdatedAt, &createdBy,
@@ -91,9 +105,12 @@ func (r *TokenRepository) GetAdminByID(id int) (*models.AdminUser, error) {
 }
 
 // UpdateAdminLastLogin updates the last login timestamp and IP
-func (r *TokenRepository) UpdateAdminLastLogin(adminID int, ipAddress string) error {
+func (r *TokenRepository) UpdateAdminLastLogin(ctx context.Context, adminID int, ipAddress string) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
 	query := `UPDATE admin_users SET last_login_at = GETDATE(), last_login_ip = @p1 WHERE id = @p2`
-	_, err := r.db.Exec(query, ipAddress, adminID)
+	_, err = r.db.ExecContext(ctx, query, ipAddress, adminID)
 	return err
 }
 
@@ -102,12 +119,15 @@ func (r *TokenRepository) UpdateAdminLastLogin(adminID int, ipAddress string) er
 // ============================================================================
 
 // CreateSession creates a new admin session
-func (r *TokenRepository) CreateSession(session *models.AdminSession) error {
+func (r *TokenRepository) CreateSession(ctx context.Context, session *models.AdminSession) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
 	query := `
 		INSERT INTO admin_sessions (session_token, admin_user_id, ip_address, user_agent, expires_at)
 		VALUES (@p1, @p2, @p3, @p4, @p5)
 	`
-	_, err := r.db.Exec(query,
+	_, err = r.db.ExecContext(ctx, query,
 		session.SessionToken, session.AdminUserID,
 		session.IPAddress, session.UserAgent, session.ExpiresAt,
 	)
@@ -115,17 +135,20 @@ func (r *TokenRepository) CreateSession(session *models.AdminSession) error {
 }
 
 // GetSessionByToken retrieves a session by token
-func (r *TokenRepository) GetSessionByToken(token string) (*models.AdminSession, error) {
+func (r *TokenRepository) GetSessionByToken(ctx context.Context, token string) (_ *models.AdminSession, err error) {
+	ctx, done := r.timeouts.read(ctx, &err)
+	defer done()
+
 	query := `
 		SELECT id, session_token, admin_user_id, ISNULL(ip_address, '') as ip_address,
 		       ISNULL(user_agent, '') as user_agent, expires_at, created_at, last_accessed_at
 		FROM admin_sessions
 		WHERE session_token = @p1 AND expires_at > GETDATE()
 	`
-	row := r.db.QueryRow(query, token)
+	row := r.db.QueryRowContext(ctx, query, token)
 
 	var session models.AdminSession
-	err := row.Scan(
+	err = row.Scan(
 		&session.ID, &session.SessionToken, &session.AdminUserID,
 		&session.IPAddress, &session.UserAgent, &session.ExpiresAt,
 		&session.CreatedAt, &session.LastAccessedAt,
@@ -140,16 +163,22 @@ func (r *TokenRepository) GetSessionByToken(token string) (*models.AdminSession,
 }
 
 // UpdateSessionAccess updates the last accessed timestamp
-func (r *TokenRepository) UpdateSessionAccess(sessionID int64) error {
+func (r *TokenRepository) UpdateSessionAccess(ctx context.Context, sessionID int64) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
 	query := `UPDATE admin_sessions SET last_accessed_at = GETDATE() WHERE id = @p1`
-	_, err := r.db.Exec(query, sessionID)
+	_, err = r.db.ExecContext(ctx, query, sessionID)
 	return err
 }
 
 // DeleteSession deletes a session (logout)
-func (r *TokenRepository) DeleteSession(token string) error {
+func (r *TokenRepository) DeleteSession(ctx context.Context, token string) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
 	query := `DELETE FROM admin_sessions WHERE session_token = @p1`
-	_, err := r.db.Exec(query, token)
+	_, err = r.db.ExecContext(ctx, query, token)
 	return err
 }
 
@@ -158,19 +187,24 @@ func (r *TokenRepository) DeleteSession(token string) error {
 // ============================================================================
 
 // CreateAPIToken creates a new API token and returns its ID
-func (r *TokenRepository) CreateAPIToken(token *models.APIToken, createdBy int) (int, error) {
+func (r *TokenRepository) CreateAPIToken(ctx context.Context, token *models.APIToken, createdBy int) (_ int, err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
 	query := `
 		INSERT INTO api_tokens (
 			token, name, description, token_prefix, scopes, permissions,
 			environment, is_active, ip_whitelist, allowed_origins,
 			rate_limit_per_minute, rate_limit_per_hour, rate_limit_per_day,
 			expires_at, created_by,
-			vendor_name, filter_column, filter_value, is_super_token
+			vendor_name, filter_column, filter_value, is_super_token,
+			client_cert_fingerprint, client_cert_subject
 		)
 		OUTPUT INSERTED.id
 		VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10,
 		        @p11, @p12, @p13, @p14, @p15,
-		        @p16, @p17, @p18, @p19)
+		        @p16, @p17, @p18, @p19,
+		        NULLIF(@p20, ''), NULLIF(@p21, ''))
 	`
 
 	var expiresAt interface{}
@@ -190,13 +224,14 @@ func (r *TokenRepository) CreateAPIToken(token *models.APIToken, createdBy int)
 	}
 
 	var id int
-	err := r.db.QueryRow(query,
+	err = r.db.QueryRowContext(ctx, query,
 		token.Token, token.Name, token.Description, token.TokenPrefix,
 		token.Scopes, token.Permissions, token.Environment, token.IsActive,
 		token.IPWhitelist, token.AllowedOrigins,
 		token.RateLimitPerMinute, token.RateLimitPerHour, token.RateLimitPerDay,
 		expiresAt, createdBy,
 		vendorName, filterColumn, filterValue, token.IsSuperToken,
+		token.ClientCertFingerprint, token.ClientCertSubject,
 	).Scan(&id)
 
 	return id, err
@@ -218,6 +253,7 @@ func (r *TokenRepository) scanToken(row interface{ Scan(dest ...interface{}) err
 		&t.TotalRequests, &t.CreatedAt, &t.UpdatedAt, &createdBy,
 		&t.RevokedAt, &revokedBy, &revokedReason,
 		&t.VendorName, &t.FilterColumn, &t.FilterValue, &t.IsSuperToken,
+		&t.ClientCertFingerprint, &t.ClientCertSubject,
 	)
 	if err != nil {
 		return nil, err
@@ -252,18 +288,26 @@ const tokenSelectQuery = `
 	       ISNULL(vendor_name, '') as vendor_name,
 	       ISNULL(filter_column, '') as filter_column,
 	       ISNULL(filter_value, '') as filter_value,
-	       ISNULL(is_super_token, 0) as is_super_token
+	       ISNULL(is_super_token, 0) as is_super_token,
+	       ISNULL(client_cert_fingerprint, '') as client_cert_fingerprint,
+	       ISNULL(client_cert_subject, '') as client_cert_subject
 	FROM api_tokens
 `
 
-// GetAPITokenByToken retrieves a token by its value
-func (r *TokenRepository) GetAPITokenByToken(tokenValue string) (*models.APIToken, error) {
+// GetAPITokenByToken retrieves a token by its value. It returns ErrNotFound
+// when no token has that value.
+func (r *TokenRepository) GetAPITokenByToken(ctx context.Context, tokenValue string) (_ *models.APIToken, err error) {
+	ctx, done := r.timeouts.read(ctx, &err)
+	defer done()
+
 	query := tokenSelectQuery + ` WHERE token = @p1`
-	row := r.db.QueryRow(query, tokenValue)
+	ctx, span := tracing.StartQuery(ctx, "TokenRepository.GetAPITokenByToken", tokenDBName)
+	row := r.db.QueryRowContext(ctx, query, tokenValue)
 	token, err := r.scanToken(row)
+	tracing.End(span, err)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("token not found")
+			return nil, ErrNotFound
 		}
 		return nil, err
 	}
@@ -271,9 +315,12 @@ func (r *TokenRepository) GetAPITokenByToken(tokenValue string) (*models.APIToke
 }
 
 // GetAPITokenByID retrieves a token by ID
-func (r *TokenRepository) GetAPITokenByID(id int) (*models.APIToken, error) {
+func (r *TokenRepository) GetAPITokenByID(ctx context.Context, id int) (_ *models.APIToken, err error) {
+	ctx, done := r.timeouts.read(ctx, &err)
+	defer done()
+
 	query := tokenSelectQuery + ` WHERE id = @p1`
-	row := r.db.QueryRow(query, id)
+	row := r.db.QueryRowContext(ctx, query, id)
 	token, err := r.scanToken(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -285,9 +332,12 @@ func (r *TokenRepository) GetAPITokenByID(id int) (*models.APIToken, error) {
 }
 
 // GetAllAPITokens retrieves all API tokens
-func (r *TokenRepository) GetAllAPITokens() ([]*models.APIToken, error) {
+func (r *TokenRepository) GetAllAPITokens(ctx context.Context) (_ []*models.APIToken, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
 	query := tokenSelectQuery + ` ORDER BY created_at DESC`
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -305,7 +355,10 @@ func (r *TokenRepository) GetAllAPITokens() ([]*models.APIToken, error) {
 }
 
 // UpdateAPIToken updates an existing API token
-func (r *TokenRepository) UpdateAPIToken(id int, updates map[string]interface{}) error {
+func (r *TokenRepository) UpdateAPIToken(ctx context.Context, id int, updates map[string]interface{}) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
 	query := "UPDATE api_tokens SET "
 	args := []interface{}{}
 	paramNum := 1
@@ -322,48 +375,106 @@ func (r *TokenRepository) UpdateAPIToken(id int, updates map[string]interface{})
 	query += fmt.Sprintf(" WHERE id = @p%d", paramNum)
 	args = append(args, id)
 
-	_, err := r.db.Exec(query, args...)
+	_, err = r.db.ExecContext(ctx, query, args...)
 	return err
 }
 
-// UpdateTokenUsage updates token usage statistics
-func (r *TokenRepository) UpdateTokenUsage(tokenID int, ipAddress, endpoint string) error {
-	query := `
-		UPDATE api_tokens
-		SET last_used_at = GETDATE(), last_used_ip = @p1,
-		    last_used_endpoint = @p2, total_requests = total_requests + 1
-		WHERE id = @p3
-	`
-	_, err := r.db.Exec(query, ipAddress, endpoint, tokenID)
-	return err
+// TokenUsageDelta is the usage of one token since the last flush: how many
+// requests it served and the most recent of them.
+type TokenUsageDelta struct {
+	TokenID          int
+	Requests         int64
+	LastUsedAt       time.Time
+	LastUsedIP       string
+	LastUsedEndpoint string
+}
+
+// tokenUsageRows is how many tokens one AddTokenUsage statement updates,
+// keeping it under SQL Server's 2100-parameter limit
+const tokenUsageRows = 400
+
+// AddTokenUsage adds each delta's requests to its token's total_requests and
+// sets its last-used fields, one statement per tokenUsageRows tokens. All of
+// them run in one transaction, so a failed call can be retried in full.
+func (r *TokenRepository) AddTokenUsage(ctx context.Context, deltas []TokenUsageDelta) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	ctx, span := tracing.StartQuery(ctx, "TokenRepository.AddTokenUsage", tokenDBName)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for len(deltas) > 0 {
+		chunk := deltas[:min(len(deltas), tokenUsageRows)]
+		deltas = deltas[len(chunk):]
+
+		values := make([]string, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*5)
+		for i, d := range chunk {
+			n := i * 5
+			values[i] = fmt.Sprintf("(@p%d, @p%d, @p%d, @p%d, @p%d)", n+1, n+2, n+3, n+4, n+5)
+			args = append(args, d.TokenID, d.Requests, d.LastUsedAt, truncate(d.LastUsedIP, 45), truncate(d.LastUsedEndpoint, 500))
+		}
+		query := `
+			UPDATE t
+			SET total_requests = t.total_requests + v.requests,
+			    last_used_at = v.last_used_at, last_used_ip = v.last_used_ip,
+			    last_used_endpoint = v.last_used_endpoint
+			FROM api_tokens t
+			JOIN (VALUES ` + strings.Join(values, ", ") + `)
+			    AS v (token_id, requests, last_used_at, last_used_ip, last_used_endpoint)
+			    ON t.id = v.token_id
+		`
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 // DisableToken disables a token
-func (r *TokenRepository) DisableToken(id int) error {
-	_, err := r.db.Exec(`UPDATE api_tokens SET is_active = 0 WHERE id = @p1`, id)
+func (r *TokenRepository) DisableToken(ctx context.Context, id int) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	_, err = r.db.ExecContext(ctx, `UPDATE api_tokens SET is_active = 0 WHERE id = @p1`, id)
 	return err
 }
 
 // EnableToken enables a token
-func (r *TokenRepository) EnableToken(id int) error {
-	_, err := r.db.Exec(`UPDATE api_tokens SET is_active = 1 WHERE id = @p1`, id)
+func (r *TokenRepository) EnableToken(ctx context.Context, id int) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	_, err = r.db.ExecContext(ctx, `UPDATE api_tokens SET is_active = 1 WHERE id = @p1`, id)
 	return err
 }
 
 // RevokeToken revokes a token permanently
-func (r *TokenRepository) RevokeToken(id int, revokedBy int, reason string) error {
+func (r *TokenRepository) RevokeToken(ctx context.Context, id int, revokedBy int, reason string) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
 	query := `
 		UPDATE api_tokens
 		SET revoked_at = GETDATE(), revoked_by = @p1, revoked_reason = @p2, is_active = 0
 		WHERE id = @p3
 	`
-	_, err := r.db.Exec(query, revokedBy, reason, id)
+	_, err = r.db.ExecContext(ctx, query, revokedBy, reason, id)
 	return err
 }
 
 // DeleteToken deletes a token permanently
-func (r *TokenRepository) DeleteToken(id int) error {
-	_, err := r.db.Exec(`DELETE FROM api_tokens WHERE id = @p1`, id)
+func (r *TokenRepository) DeleteToken(ctx context.Context, id int) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	_, err = r.db.ExecContext(ctx, `DELETE FROM api_tokens WHERE id = @p1`, id)
 	return err
 }
 
@@ -371,32 +482,91 @@ func (r *TokenRepository) DeleteToken(id int) error {
 // Token Usage Logs
 // ============================================================================
 
-// CreateUsageLog creates a new usage log entry
-func (r *TokenRepository) CreateUsageLog(log *models.TokenUsageLog) error {
-	// Set created_at if not already set
-	if log.CreatedAt.IsZero() {
-		log.CreatedAt = time.Now()
+// usageLogRows is how many rows one CreateUsageLogs INSERT writes, keeping it
+// under SQL Server's 2100-parameter limit
+const usageLogRows = 100
+
+// CreateUsageLogs writes usage log rows in a single transaction, usageLogRows
+// rows per INSERT. Rows without a CreatedAt get the current time. Text taken
+// from the request is cut to its column size, so one oversized URL or header
+// cannot fail the whole batch.
+func (r *TokenRepository) CreateUsageLogs(ctx context.Context, logs []*models.TokenUsageLog) (err error) {
+	if len(logs) == 0 {
+		return nil
 	}
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
 
-	query := `
-		INSERT INTO token_usage_logs (
-			token_id, method, endpoint, full_url, status_code, response_time_ms,
-			ip_address, user_agent, referer, request_id, request_body_size,
-			response_body_size, error_message, error_code, created_at
-		)
-		VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, @p10, @p11, @p12, @p13, @p14, @p15)
-	`
-	_, err := r.db.Exec(query,
-		log.TokenID, log.Method, log.Endpoint, log.FullURL,
-		log.StatusCode, log.ResponseTimeMs, log.IPAddress, log.UserAgent,
-		log.Referer, log.RequestID, log.RequestBodySize, log.ResponseBodySize,
-		log.ErrorMessage, log.ErrorCode, log.CreatedAt,
-	)
-	return err
+	ctx, span := tracing.StartQuery(ctx, "TokenRepository.CreateUsageLogs", tokenDBName)
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for len(logs) > 0 {
+		chunk := logs[:min(len(logs), usageLogRows)]
+		logs = logs[len(chunk):]
+
+		values := make([]string, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*16)
+		for i, log := range chunk {
+			if log.CreatedAt.IsZero() {
+				log.CreatedAt = now
+			}
+			p := make([]interface{}, 16)
+			for j := range p {
+				p[j] = i*16 + j + 1
+			}
+			values[i] = fmt.Sprintf("(@p%d, @p%d, @p%d, @p%d, @p%d, @p%d, @p%d, @p%d, @p%d, @p%d, @p%d, @p%d, @p%d, @p%d, NULLIF(@p%d, ''), @p%d)", p...)
+			args = append(args,
+				log.TokenID, truncate(log.Method, 10), truncate(log.Endpoint, 500), truncate(log.FullURL, 1000),
+				log.StatusCode, log.ResponseTimeMs, truncate(log.IPAddress, 45), truncate(log.UserAgent, 500),
+				truncate(log.Referer, 500), truncate(log.RequestID, 100), log.RequestBodySize, log.ResponseBodySize,
+				log.ErrorMessage, truncate(log.ErrorCode, 100), truncate(log.APIVersion, 10), log.CreatedAt,
+			)
+		}
+		query := `
+			INSERT INTO token_usage_logs (
+				token_id, method, endpoint, full_url, status_code, response_time_ms,
+				ip_address, user_agent, referer, request_id, request_body_size,
+				response_body_size, error_message, error_code, api_version, created_at
+			)
+			VALUES ` + strings.Join(values, ", ")
+		if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// truncate cuts s to fit an NVARCHAR(n) column, which counts UTF-16 code
+// units: one per character, two for characters outside the BMP
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for i, c := range s {
+		w := 1
+		if c > 0xFFFF {
+			w = 2
+		}
+		if n < w {
+			return s[:i]
+		}
+		n -= w
+	}
+	return s
 }
 
 // GetRecentUsageLogs retrieves recent usage logs
-func (r *TokenRepository) GetRecentUsageLogs(limit int) ([]*models.TokenUsageLog, error) {
+func (r *TokenRepository) GetRecentUsageLogs(ctx context.Context, limit int) (_ []*models.TokenUsageLog, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
 	query := `
 		SELECT TOP (@p1) id, token_id, method, endpoint, ISNULL(full_url, '') as full_url,
 		       status_code, ISNULL(response_time_ms, 0) as response_time_ms,
@@ -405,11 +575,12 @@ func (r *TokenRepository) GetRecentUsageLogs(limit int) ([]*models.TokenUsageLog
 		       ISNULL(request_body_size, 0) as request_body_size,
 		       ISNULL(response_body_size, 0) as response_body_size,
 		       ISNULL(error_message, '') as error_message,
-		       ISNULL(error_code, '') as error_code, created_at
+		       ISNULL(error_code, '') as error_code,
+		       ISNULL(api_version, '') as api_version, created_at
 		FROM token_usage_logs
 		ORDER BY created_at DESC
 	`
-	rows, err := r.db.Query(query, limit)
+	rows, err := r.db.QueryContext(ctx, query, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -422,7 +593,7 @@ func (r *TokenRepository) GetRecentUsageLogs(limit int) ([]*models.TokenUsageLog
 			&l.ID, &l.TokenID, &l.Method, &l.Endpoint, &l.FullURL,
 			&l.StatusCode, &l.ResponseTimeMs, &l.IPAddress, &l.UserAgent,
 			&l.Referer, &l.RequestID, &l.RequestBodySize, &l.ResponseBodySize,
-			&l.ErrorMessage, &l.ErrorCode, &l.CreatedAt,
+			&l.ErrorMessage, &l.ErrorCode, &l.APIVersion, &l.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -433,7 +604,10 @@ func (r *TokenRepository) GetRecentUsageLogs(limit int) ([]*models.TokenUsageLog
 }
 
 // GetUsageLogsByTokenID retrieves usage logs for a specific token
-func (r *TokenRepository) GetUsageLogsByTokenID(tokenID int, limit int) ([]*models.TokenUsageLog, error) {
+func (r *TokenRepository) GetUsageLogsByTokenID(ctx context.Context, tokenID int, limit int) (_ []*models.TokenUsageLog, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
 	query := `
 		SELECT TOP (@p2) id, token_id, method, endpoint, ISNULL(full_url, '') as full_url,
 		       status_code, ISNULL(response_time_ms, 0) as response_time_ms,
@@ -442,12 +616,13 @@ func (r *TokenRepository) GetUsageLogsByTokenID(tokenID int, limit int) ([]*mode
 		       ISNULL(request_body_size, 0) as request_body_size,
 		       ISNULL(response_body_size, 0) as response_body_size,
 		       ISNULL(error_message, '') as error_message,
-		       ISNULL(error_code, '') as error_code, created_at
+		       ISNULL(error_code, '') as error_code,
+		       ISNULL(api_version, '') as api_version, created_at
 		FROM token_usage_logs
 		WHERE token_id = @p1
 		ORDER BY created_at DESC
 	`
-	rows, err := r.db.Query(query, tokenID, limit)
+	rows, err := r.db.QueryContext(ctx, query, tokenID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -460,7 +635,7 @@ func (r *TokenRepository) GetUsageLogsByTokenID(tokenID int, limit int) ([]*mode
 			&l.ID, &l.TokenID, &l.Method, &l.Endpoint, &l.FullURL,
 			&l.StatusCode, &l.ResponseTimeMs, &l.IPAddress, &l.UserAgent,
 			&l.Referer, &l.RequestID, &l.RequestBodySize, &l.ResponseBodySize,
-			&l.ErrorMessage, &l.ErrorCode, &l.CreatedAt,
+			&l.ErrorMessage, &l.ErrorCode, &l.APIVersion, &l.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -474,38 +649,85 @@ func (r *TokenRepository) GetUsageLogsByTokenID(tokenID int, limit int) ([]*mode
 // Rate Limiting
 // ============================================================================
 
-// GetRateLimitCount gets the current request count for a rate limit window
-func (r *TokenRepository) GetRateLimitCount(tokenID int, windowType string, windowStart time.Time) (int, error) {
-	query := `
-		SELECT ISNULL(request_count, 0)
-		FROM token_rate_limits
-		WHERE token_id = @p1 AND window_type = @p2 AND window_start = @p3
-	`
-	var count int
-	err := r.db.QueryRow(query, tokenID, windowType, windowStart).Scan(&count)
-	if err == sql.ErrNoRows {
-		return 0, nil
-	}
-	return count, err
+// RateLimitWindow is one fixed window of a token's rate limit.
+type RateLimitWindow struct {
+	Type  string // minute, hour or day
+	Start time.Time
+	End   time.Time
+	Limit int
 }
 
-// IncrementRateLimit increments or creates a rate limit counter
-func (r *TokenRepository) IncrementRateLimit(tokenID int, windowType string, windowStart, windowEnd time.Time) error {
-	// Use MERGE for upsert
+// TakeRateLimit counts one request against every window, unless one of them
+// has already reached its limit, in which case nothing is counted and that
+// window's type is returned. The check and the increments are one MERGE
+// statement holding locks on the token's counters, so concurrent requests
+// cannot overshoot a limit, also across gateway instances.
+func (r *TokenRepository) TakeRateLimit(ctx context.Context, tokenID int, windows []RateLimitWindow) (exceeded string, err error) {
+	if len(windows) == 0 {
+		return "", nil
+	}
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
+	ctx, span := tracing.StartQuery(ctx, "TokenRepository.TakeRateLimit", tokenDBName)
+	defer func() { tracing.End(span, err) }()
+
+	values := make([]string, len(windows))
+	args := []interface{}{tokenID}
+	for i, w := range windows {
+		n := i*4 + 2
+		values[i] = fmt.Sprintf("(%d, @p%d, @p%d, @p%d, @p%d)", i, n, n+1, n+2, n+3)
+		args = append(args, w.Type, w.Start, w.End, w.Limit)
+	}
+	windowsAs := func(alias string) string {
+		return `(VALUES ` + strings.Join(values, ", ") + `) AS ` + alias + ` (ord, window_type, window_start, window_end, request_limit)`
+	}
+
 	query := `
-		MERGE token_rate_limits AS target
-		USING (SELECT @p1 AS token_id, @p2 AS window_type, @p3 AS window_start) AS source
-		ON target.token_id = source.token_id
+		MERGE token_rate_limits WITH (HOLDLOCK) AS target
+		USING (
+			SELECT v.window_type, v.window_start, v.window_end
+			FROM ` + windowsAs("v") + `
+			WHERE NOT EXISTS (
+				SELECT 1
+				FROM token_rate_limits l WITH (UPDLOCK, HOLDLOCK)
+				JOIN ` + windowsAs("w") + `
+				  ON l.window_type = w.window_type AND l.window_start = w.window_start
+				WHERE l.token_id = @p1 AND l.request_count >= w.request_limit
+			)
+		) AS source
+		ON target.token_id = @p1
 		   AND target.window_type = source.window_type
 		   AND target.window_start = source.window_start
 		WHEN MATCHED THEN
-			UPDATE SET request_count = request_count + 1, updated_at = GETDATE()
+			UPDATE SET request_count = target.request_count + 1, updated_at = GETDATE()
 		WHEN NOT MATCHED THEN
 			INSERT (token_id, window_type, window_start, window_end, request_count)
-			VALUES (@p1, @p2, @p3, @p4, 1);
+			VALUES (@p1, source.window_type, source.window_start, source.window_end, 1);
 	`
-	_, err := r.db.Exec(query, tokenID, windowType, windowStart, windowEnd)
-	return err
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return "", err
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return "", err
+	}
+
+	// Nothing was counted: name the first window that is full
+	query = `
+		SELECT TOP 1 v.window_type
+		FROM ` + windowsAs("v") + `
+		JOIN token_rate_limits l
+		  ON l.token_id = @p1 AND l.window_type = v.window_type AND l.window_start = v.window_start
+		WHERE l.request_count >= v.request_limit
+		ORDER BY v.ord
+	`
+	err = r.db.QueryRowContext(ctx, query, args...).Scan(&exceeded)
+	if err == sql.ErrNoRows {
+		// The counters changed in between; the request was still refused
+		return windows[0].Type, nil
+	}
+	return exceeded, err
 }
 
 // ============================================================================
@@ -513,7 +735,10 @@ func (r *TokenRepository) IncrementRateLimit(tokenID int, windowType string, win
 // ============================================================================
 
 // GetTokenAnalytics retrieves analytics for a specific token
-func (r *TokenRepository) GetTokenAnalytics(tokenID int, days int) (*models.TokenAnalytics, error) {
+func (r *TokenRepository) GetTokenAnalytics(ctx context.Context, tokenID int, days int) (_ *models.TokenAnalytics, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
 	query := `
 		SELECT
 			t.id AS token_id,
@@ -534,10 +759,10 @@ func (r *TokenRepository) GetTokenAnalytics(tokenID int, days int) (*models.Toke
 		WHERE t.id = @p1
 		GROUP BY t.id, t.name
 	`
-	row := r.db.QueryRow(query, tokenID, days)
+	row := r.db.QueryRowContext(ctx, query, tokenID, days)
 
 	var a models.TokenAnalytics
-	err := row.Scan(
+	err = row.Scan(
 		&a.TokenID, &a.TokenName, &a.TotalRequests,
 		&a.SuccessfulRequests, &a.FailedRequests, &a.ClientErrors,
 		&a.ServerErrors, &a.AvgResponseTimeMs, &a.MaxResponseTimeMs,
@@ -550,11 +775,14 @@ func (r *TokenRepository) GetTokenAnalytics(tokenID int, days int) (*models.Toke
 }
 
 // GetDashboardStats retrieves overall dashboard statistics
-func (r *TokenRepository) GetDashboardStats() (*models.TokenDashboardStats, error) {
+func (r *TokenRepository) GetDashboardStats(ctx context.Context) (_ *models.TokenDashboardStats, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
 	var stats models.TokenDashboardStats
 
 	// Get token counts
-	err := r.db.QueryRow(`
+	err = r.db.QueryRowContext(ctx, `
 		SELECT
 			COUNT(*) AS total_tokens,
 			COUNT(CASE WHEN is_active = 1 AND (expires_at IS NULL OR expires_at > GETDATE())
@@ -566,7 +794,7 @@ func (r *TokenRepository) GetDashboardStats() (*models.TokenDashboardStats, erro
 	}
 
 	// Get 24h request stats
-	err = r.db.QueryRow(`
+	err = r.db.QueryRowContext(ctx, `
 		SELECT
 			COUNT(*),
 			CASE WHEN COUNT(*) > 0 THEN
@@ -584,7 +812,10 @@ func (r *TokenRepository) GetDashboardStats() (*models.TokenDashboardStats, erro
 }
 
 // GetEndpointStats retrieves statistics per endpoint
-func (r *TokenRepository) GetEndpointStats(days int, limit int) ([]*models.EndpointStats, error) {
+func (r *TokenRepository) GetEndpointStats(ctx context.Context, days int, limit int) (_ []*models.EndpointStats, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
 	query := `
 		SELECT TOP (@p2)
 			endpoint, method, COUNT(*) AS request_count,
@@ -597,7 +828,7 @@ func (r *TokenRepository) GetEndpointStats(days int, limit int) ([]*models.Endpo
 		GROUP BY endpoint, method
 		ORDER BY request_count DESC
 	`
-	rows, err := r.db.Query(query, days, limit)
+	rows, err := r.db.QueryContext(ctx, query, days, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -618,7 +849,10 @@ func (r *TokenRepository) GetEndpointStats(days int, limit int) ([]*models.Endpo
 }
 
 // GetDailyUsage retrieves daily usage stats for charting
-func (r *TokenRepository) GetDailyUsage(tokenID *int, days int) ([]*models.DailyUsage, error) {
+func (r *TokenRepository) GetDailyUsage(ctx context.Context, tokenID *int, days int) (_ []*models.DailyUsage, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
 	query := `
 		SELECT
 			CONVERT(VARCHAR(10), l.created_at, 120) AS usage_date,
@@ -643,7 +877,7 @@ func (r *TokenRepository) GetDailyUsage(tokenID *int, days int) ([]*models.Daily
 		ORDER BY usage_date DESC
 	`
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -663,38 +897,126 @@ func (r *TokenRepository) GetDailyUsage(tokenID *int, days int) ([]*models.Daily
 	return usage, rows.Err()
 }
 
+// GetErrorStats counts failed requests per error code, optionally for one token
+func (r *TokenRepository) GetErrorStats(ctx context.Context, tokenID *int, days int) (_ []*models.ErrorCodeStats, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
+	query := `
+		SELECT error_code, COUNT(*) AS request_count,
+			COUNT(DISTINCT token_id) AS unique_tokens,
+			MAX(created_at) AS last_seen_at
+		FROM token_usage_logs
+		WHERE created_at >= DATEADD(day, -@p1, GETDATE())
+		  AND error_code IS NOT NULL AND error_code <> ''
+	`
+	args := []interface{}{days}
+
+	if tokenID != nil {
+		query += ` AND token_id = @p2`
+		args = append(args, *tokenID)
+	}
+
+	query += `
+		GROUP BY error_code
+		ORDER BY request_count DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*models.ErrorCodeStats
+	for rows.Next() {
+		var s models.ErrorCodeStats
+		if err := rows.Scan(&s.ErrorCode, &s.RequestCount, &s.UniqueTokens, &s.LastSeenAt); err != nil {
+			return nil, err
+		}
+		stats = append(stats, &s)
+	}
+	return stats, rows.Err()
+}
+
+// GetAPIVersionUsage returns v1 and v2 request counts per token, tokens still
+// calling v1 first
+func (r *TokenRepository) GetAPIVersionUsage(ctx context.Context, days int) (_ []*models.APIVersionUsage, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
+	query := `
+		SELECT
+			t.id AS token_id, t.name AS token_name,
+			COUNT(CASE WHEN l.api_version = 'v1' THEN 1 END) AS v1_requests,
+			COUNT(CASE WHEN l.api_version = 'v2' THEN 1 END) AS v2_requests,
+			COUNT(DISTINCT CASE WHEN l.api_version = 'v1' THEN l.endpoint END) AS v1_endpoints,
+			MAX(CASE WHEN l.api_version = 'v1' THEN l.created_at END) AS last_v1_request_at
+		FROM api_tokens t
+		INNER JOIN token_usage_logs l ON t.id = l.token_id
+		WHERE l.created_at >= DATEADD(day, -@p1, GETDATE())
+		  AND l.api_version IS NOT NULL
+		GROUP BY t.id, t.name
+		ORDER BY v1_requests DESC, t.name
+	`
+	rows, err := r.db.QueryContext(ctx, query, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usage []*models.APIVersionUsage
+	for rows.Next() {
+		var u models.APIVersionUsage
+		err := rows.Scan(&u.TokenID, &u.TokenName, &u.V1Requests,
+			&u.V2Requests, &u.V1Endpoints, &u.LastV1Request)
+		if err != nil {
+			return nil, err
+		}
+		usage = append(usage, &u)
+	}
+	return usage, rows.Err()
+}
+
 // ============================================================================
 // Audit Logging
 // ============================================================================
 
 // CreateAuditLog creates a new audit log entry
-func (r *TokenRepository) CreateAuditLog(log *models.AuditLog) error {
+func (r *TokenRepository) CreateAuditLog(ctx context.Context, log *models.AuditLog) (err error) {
+	ctx, done := r.timeouts.write(ctx, &err)
+	defer done()
+
 	query := `
 		INSERT INTO audit_logs (
 			admin_user_id, action, resource_type, resource_id,
-			old_values, new_values, ip_address, user_agent, description
+			old_values, new_values, ip_address, user_agent, description, request_id
 		)
-		VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9)
+		VALUES (@p1, @p2, @p3, @p4, @p5, @p6, @p7, @p8, @p9, NULLIF(@p10, ''))
 	`
-	_, err := r.db.Exec(query,
+	_, err = r.db.ExecContext(ctx, query,
 		log.AdminUserID, log.Action, log.ResourceType, log.ResourceID,
 		log.OldValues, log.NewValues, log.IPAddress, log.UserAgent,
-		log.Description,
+		log.Description, log.RequestID,
 	)
 	return err
 }
 
 // GetAuditLogs retrieves audit logs with limit
-func (r *TokenRepository) GetAuditLogs(limit int) ([]*models.AuditLog, error) {
+func (r *TokenRepository) GetAuditLogs(ctx context.Context, limit int) (_ []*models.AuditLog, err error) {
+	ctx, done := r.timeouts.list(ctx, &err)
+	defer done()
+
 	query := `
 		SELECT TOP (@p1) id, admin_user_id, action, resource_type, resource_id,
 		       ISNULL(old_values, '') as old_values, ISNULL(new_values, '') as new_values,
 		       ISNULL(ip_address, '') as ip_address, ISNULL(user_agent, '') as user_agent,
-		       ISNULL(description, '') as description, created_at
+		       ISNULL(description, '') as description,
+		       ISNULL(request_id, '') as request_id, created_at
 		FROM audit_logs
 		ORDER BY created_at DESC
 	`
-	rows, err := r.db.Query(query, limit)
+	rows, err := r.db.QueryContext(ctx, query, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -707,7 +1029,7 @@ func (r *TokenRepository) GetAuditLogs(limit int) ([]*models.AuditLog, error) {
 		err := rows.Scan(
 			&l.ID, &adminUserID, &l.Action, &l.ResourceType, &resourceID,
 			&l.OldValues, &l.NewValues, &l.IPAddress, &l.UserAgent,
-			&l.Description, &l.CreatedAt,
+			&l.Description, &l.RequestID, &l.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,362 @@
+package repository
+
+import (
+	"api-gateway/models"
+	"cmp"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WebhookRepository handles webhook subscriptions and the delivery outbox.
+// Both live in the token_management database next to the owning api_tokens.
+type WebhookRepository struct {
+	db     *sql.DB
+	logger *logrus.Logger
+}
+
+// NewWebhookRepository creates a new webhook repository instance
+func NewWebhookRepository(db *sql.DB, logger *logrus.Logger) *WebhookRepository {
+	return &WebhookRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ============================================================================
+// Subscriptions
+// ============================================================================
+
+const webhookSubscriptionSelectQuery = `
+	SELECT s.id, s.token_id, s.url, ISNULL(s.event_types, '') as event_types, s.secret,
+	       s.is_active, s.created_at, s.updated_at, s.created_by,
+	       ISNULL(t.filter_column, '') as filter_column,
+	       ISNULL(t.filter_value, '') as filter_value,
+	       ISNULL(t.is_super_token, 0) as is_super_token
+	FROM webhook_subscriptions s
+	JOIN api_tokens t ON t.id = s.token_id
+`
+
+// scanSubscription scans a row into a WebhookSubscription struct
+func (r *WebhookRepository) scanSubscription(row interface {
+	Scan(dest ...interface{}) error
+}) (*models.WebhookSubscription, error) {
+	var s models.WebhookSubscription
+	var eventTypes string
+	var createdBy sql.NullInt64
+
+	err := row.Scan(
+		&s.ID, &s.TokenID, &s.URL, &eventTypes, &s.Secret,
+		&s.IsActive, &s.CreatedAt, &s.UpdatedAt, &createdBy,
+		&s.TokenFilterColumn, &s.TokenFilterValue, &s.TokenIsSuper,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s.EventTypes = []string{}
+	for _, t := range strings.Split(eventTypes, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			s.EventTypes = append(s.EventTypes, t)
+		}
+	}
+	if createdBy.Valid {
+		v := int(createdBy.Int64)
+		s.CreatedBy = &v
+	}
+	return &s, nil
+}
+
+// querySubscriptions runs a subscription query and scans every row
+func (r *WebhookRepository) querySubscriptions(query string, args ...interface{}) ([]*models.WebhookSubscription, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []*models.WebhookSubscription
+	for rows.Next() {
+		s, err := r.scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
+
+// GetSubscriptionsByToken retrieves all subscriptions of one API token
+func (r *WebhookRepository) GetSubscriptionsByToken(tokenID int) ([]*models.WebhookSubscription, error) {
+	return r.querySubscriptions(webhookSubscriptionSelectQuery+` WHERE s.token_id = @p1 ORDER BY s.id`, tokenID)
+}
+
+// GetActiveSubscriptions retrieves the active subscriptions of active, unrevoked,
+// unexpired tokens, with each token's vendor scope
+func (r *WebhookRepository) GetActiveSubscriptions() ([]*models.WebhookSubscription, error) {
+	return r.querySubscriptions(webhookSubscriptionSelectQuery + `
+		WHERE s.is_active = 1 AND t.is_active = 1 AND t.revoked_at IS NULL
+		  AND (t.expires_at IS NULL OR t.expires_at > GETDATE())
+		ORDER BY s.id`)
+}
+
+// GetSubscriptionByID retrieves a subscription by ID
+func (r *WebhookRepository) GetSubscriptionByID(id int) (*models.WebhookSubscription, error) {
+	s, err := r.scanSubscription(r.db.QueryRow(webhookSubscriptionSelectQuery+` WHERE s.id = @p1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook subscription %w", ErrNotFound)
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+// CreateSubscription inserts a new subscription and returns its ID
+func (r *WebhookRepository) CreateSubscription(s *models.WebhookSubscription, createdBy int) (int, error) {
+	query := `
+		INSERT INTO webhook_subscriptions (token_id, url, event_types, secret, is_active, created_by)
+		OUTPUT INSERTED.id
+		VALUES (@p1, @p2, @p3, @p4, @p5, @p6)
+	`
+	var id int
+	err := r.db.QueryRow(query,
+		s.TokenID, s.URL, s.EventTypesString(), s.Secret, s.IsActive, createdBy,
+	).Scan(&id)
+	return id, err
+}
+
+// UpdateSubscription replaces the URL, event filter, secret and status of a subscription
+func (r *WebhookRepository) UpdateSubscription(s *models.WebhookSubscription) error {
+	query := `
+		UPDATE webhook_subscriptions
+		SET url = @p1, event_types = @p2, secret = @p3, is_active = @p4, updated_at = GETDATE()
+		WHERE id = @p5
+	`
+	result, err := r.db.Exec(query, s.URL, s.EventTypesString(), s.Secret, s.IsActive, s.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("webhook subscription %w", ErrNotFound)
+	}
+	return nil
+}
+
+// DeleteSubscription deletes a subscription and its outbox rows
+func (r *WebhookRepository) DeleteSubscription(id int) error {
+	_, err := r.db.Exec(`DELETE FROM webhook_subscriptions WHERE id = @p1`, id)
+	return err
+}
+
+// ============================================================================
+// Outbox
+// ============================================================================
+
+const webhookDeliverySelectQuery = `
+	SELECT id, subscription_id, CONVERT(NVARCHAR(36), event_id), event_type, terminal_id, payload,
+	       status, attempts, next_attempt_at, last_status_code, ISNULL(last_error, ''),
+	       delivered_at, created_at
+	FROM webhook_outbox
+`
+
+// scanDelivery scans a row into a WebhookDelivery struct. extra receives any
+// columns selected after the standard ones.
+func (r *WebhookRepository) scanDelivery(row interface {
+	Scan(dest ...interface{}) error
+}, extra ...interface{}) (*models.WebhookDelivery, error) {
+	var d models.WebhookDelivery
+	var statusCode sql.NullInt64
+	var deliveredAt sql.NullTime
+
+	dest := []interface{}{
+		&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.TerminalID, &d.Payload,
+		&d.Status, &d.Attempts, &d.NextAttemptAt, &statusCode, &d.LastError,
+		&deliveredAt, &d.CreatedAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	if statusCode.Valid {
+		v := int(statusCode.Int64)
+		d.LastStatusCode = &v
+	}
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return &d, nil
+}
+
+// EnqueueDeliveries writes outbox rows in a single transaction
+func (r *WebhookRepository) EnqueueDeliveries(deliveries []*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO webhook_outbox (subscription_id, event_id, event_type, terminal_id, payload)
+		VALUES (@p1, @p2, @p3, @p4, @p5)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, d := range deliveries {
+		if _, err := stmt.Exec(d.SubscriptionID, d.EventID, d.EventType, d.TerminalID, d.Payload); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ClaimDueDeliveries leases up to limit pending deliveries that are due, oldest
+// first, by pushing their next_attempt_at forward by lease, and returns them in
+// outbox order with the subscription's URL and secret. Deliveries whose token
+// has since been disabled, revoked or has expired are left alone, as are those
+// of inactive subscriptions. A worker that dies mid-delivery leaves the row to
+// be retried once the lease expires, and concurrent gateway instances claim
+// disjoint rows.
+func (r *WebhookRepository) ClaimDueDeliveries(limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT TOP (@p1) o.*
+			FROM webhook_outbox o
+			JOIN webhook_subscriptions s ON s.id = o.subscription_id
+			JOIN api_tokens t ON t.id = s.token_id
+			WHERE o.status = 'pending' AND o.next_attempt_at <= GETDATE()
+			  AND s.is_active = 1 AND t.is_active = 1 AND t.revoked_at IS NULL
+			  AND (t.expires_at IS NULL OR t.expires_at > GETDATE())
+			ORDER BY o.next_attempt_at, o.id
+		)
+		UPDATE due
+		SET next_attempt_at = DATEADD(SECOND, @p2, GETDATE())
+		OUTPUT INSERTED.id, INSERTED.subscription_id, CONVERT(NVARCHAR(36), INSERTED.event_id),
+		       INSERTED.event_type, INSERTED.terminal_id, INSERTED.payload,
+		       INSERTED.status, INSERTED.attempts, INSERTED.next_attempt_at,
+		       INSERTED.last_status_code, ISNULL(INSERTED.last_error, ''),
+		       INSERTED.delivered_at, INSERTED.created_at,
+		       s.url, s.secret
+		FROM due
+		JOIN webhook_subscriptions s ON s.id = due.subscription_id
+	`
+	rows, err := r.db.Query(query, limit, int(lease.Seconds()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		var url, secret string
+		d, err := r.scanDelivery(rows, &url, &secret)
+		if err != nil {
+			return nil, err
+		}
+		d.URL, d.Secret = url, secret
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// OUTPUT returns rows in no particular order; deliver each terminal's
+	// events in the order they were queued
+	slices.SortFunc(deliveries, func(a, b *models.WebhookDelivery) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return deliveries, nil
+}
+
+// MarkDelivered records a successful delivery
+func (r *WebhookRepository) MarkDelivered(id int64, attempts, statusCode int) error {
+	_, err := r.db.Exec(`
+		UPDATE webhook_outbox
+		SET status = 'delivered', attempts = @p1, last_status_code = @p2, last_error = NULL,
+		    delivered_at = GETDATE()
+		WHERE id = @p3
+	`, attempts, statusCode, id)
+	return err
+}
+
+// MarkFailed records a failed attempt and either schedules the next one or, when
+// dead is true, moves the delivery to the dead-letter state
+func (r *WebhookRepository) MarkFailed(id int64, attempts int, statusCode *int, errMsg string, nextAttempt time.Time, dead bool) error {
+	status := models.DeliveryPending
+	if dead {
+		status = models.DeliveryDead
+	}
+	if len(errMsg) > 1000 {
+		errMsg = errMsg[:1000]
+	}
+	_, err := r.db.Exec(`
+		UPDATE webhook_outbox
+		SET status = @p1, attempts = @p2, last_status_code = @p3, last_error = @p4, next_attempt_at = @p5
+		WHERE id = @p6
+	`, status, attempts, statusCode, errMsg, nextAttempt, id)
+	return err
+}
+
+// ListDeliveries retrieves the most recent outbox rows, optionally narrowed to one
+// subscription and/or one status
+func (r *WebhookRepository) ListDeliveries(subscriptionID *int, status string, limit int) ([]*models.WebhookDelivery, error) {
+	var conditions []string
+	args := []interface{}{limit}
+	if subscriptionID != nil {
+		args = append(args, *subscriptionID)
+		conditions = append(conditions, fmt.Sprintf("subscription_id = @p%d", len(args)))
+	}
+	if status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("status = @p%d", len(args)))
+	}
+
+	query := strings.Replace(webhookDeliverySelectQuery, "SELECT", "SELECT TOP (@p1)", 1)
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		d, err := r.scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RequeueDelivery resets a delivery (typically a dead one) to be attempted again now
+func (r *WebhookRepository) RequeueDelivery(id int64) error {
+	result, err := r.db.Exec(`
+		UPDATE webhook_outbox
+		SET status = 'pending', attempts = 0, next_attempt_at = GETDATE()
+		WHERE id = @p1 AND status <> 'delivered'
+	`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("webhook delivery not found or already delivered")
+	}
+	return nil
+}
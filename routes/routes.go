@@ -1,32 +1,52 @@
 package routes
 
 import (
+	"api-gateway/config"
+	_ "api-gateway/docs" // registers the generated OpenAPI specs
 	"api-gateway/handlers"
 	"api-gateway/middleware"
 	"api-gateway/service"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/swaggo/swag"
+	"golang.org/x/net/webdav"
 )
 
 // SetupRoutes configures all API routes
 func SetupRoutes(
 	router *gin.Engine,
 	dataHandler *handlers.DataHandler,
+	streamHandler *handlers.StreamHandler,
 	healthHandler *handlers.HealthHandler,
 	tokenHandler *handlers.TokenHandler,
+	statsHandler *handlers.StatsHandler,
+	slaHandler *handlers.SLAHandler,
+	webhookHandler *handlers.WebhookHandler,
+	cloudSyncHandler *handlers.CloudSyncHandler,
+	graphqlHandler *handlers.GraphQLHandler,
+	v2Handler *handlers.V2Handler,
 	tokenService *service.TokenService,
+	idempotencyService *service.IdempotencyService,
 	apiKey string,
+	apiConfig config.APIConfig,
 ) {
-	router.Use(middleware.CORS())
-
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(200, gin.H{"message": "Welcome to API Gateway"})
 	})
 
 	// Health check endpoints (no authentication required)
+	router.GET("/livez", healthHandler.Live)
+	router.GET("/readyz", healthHandler.Ready)
 	router.GET("/health", healthHandler.Check)
 	router.GET("/ping", healthHandler.Ping)
 
+	// Interactive API docs: data + health spec for API consumers
+	router.GET("/docs", redirectTo("/docs/index.html"))
+	router.GET("/docs/*any", swaggerUI("public"))
+
 	// Admin dashboard static files and pages
 	router.Static("/admin/assets", "./templates/assets")
 	router.LoadHTMLGlob("templates/*.html")
@@ -43,6 +63,14 @@ func SetupRoutes(
 			adminAPI.POST("/auth/login", tokenHandler.Login)
 			adminAPI.POST("/auth/logout", tokenHandler.Logout)
 
+			// Full spec including the admin API, behind the dashboard session
+			adminDocs := router.Group("/admin/docs")
+			adminDocs.Use(middleware.AdminAuthMiddleware(tokenService))
+			{
+				adminDocs.GET("", redirectTo("/admin/docs/index.html"))
+				adminDocs.GET("/*any", swaggerUI(swag.Name))
+			}
+
 			protected := adminAPI.Group("")
 			protected.Use(middleware.AdminAuthMiddleware(tokenService))
 			{
@@ -63,23 +91,132 @@ func SetupRoutes(
 				protected.GET("/analytics/tokens/:id", tokenHandler.GetTokenAnalytics)
 				protected.GET("/analytics/endpoints", tokenHandler.GetEndpointStats)
 				protected.GET("/analytics/daily", tokenHandler.GetDailyUsage)
+				protected.GET("/analytics/api-versions", tokenHandler.GetAPIVersionUsage)
+				protected.GET("/analytics/errors", tokenHandler.GetErrorStats)
 
 				// Audit logs
 				protected.GET("/audit-logs", tokenHandler.GetAuditLogs)
+
+				// Criticality rules (drive /api/v1/stats/critical)
+				if statsHandler != nil {
+					protected.GET("/criticality-rules", statsHandler.ListRules)
+					protected.POST("/criticality-rules", statsHandler.CreateRule)
+					protected.GET("/criticality-rules/:id", statsHandler.GetRule)
+					protected.PUT("/criticality-rules/:id", statsHandler.UpdateRule)
+					protected.DELETE("/criticality-rules/:id", statsHandler.DeleteRule)
+				}
+
+				// SLA policies & business-hours calendars (drive sla_* fields on /api/v1/data)
+				if slaHandler != nil {
+					protected.GET("/sla/calendars", slaHandler.ListCalendars)
+					protected.POST("/sla/calendars", slaHandler.CreateCalendar)
+					protected.GET("/sla/calendars/:id", slaHandler.GetCalendar)
+					protected.PUT("/sla/calendars/:id", slaHandler.UpdateCalendar)
+					protected.DELETE("/sla/calendars/:id", slaHandler.DeleteCalendar)
+
+					protected.GET("/sla/policies", slaHandler.ListPolicies)
+					protected.POST("/sla/policies", slaHandler.CreatePolicy)
+					protected.GET("/sla/policies/:id", slaHandler.GetPolicy)
+					protected.PUT("/sla/policies/:id", slaHandler.UpdatePolicy)
+					protected.DELETE("/sla/policies/:id", slaHandler.DeletePolicy)
+				}
+
+				// Outbound webhooks (per token) and the delivery outbox
+				if webhookHandler != nil {
+					protected.GET("/tokens/:id/webhooks", webhookHandler.ListWebhooks)
+					protected.POST("/tokens/:id/webhooks", webhookHandler.CreateWebhook)
+					protected.GET("/tokens/:id/webhooks/:webhook_id", webhookHandler.GetWebhook)
+					protected.PUT("/tokens/:id/webhooks/:webhook_id", webhookHandler.UpdateWebhook)
+					protected.DELETE("/tokens/:id/webhooks/:webhook_id", webhookHandler.DeleteWebhook)
+					protected.GET("/tokens/:id/webhooks/:webhook_id/deliveries", webhookHandler.ListDeliveries)
+
+					protected.GET("/webhooks/dead-letters", webhookHandler.ListDeadLetters)
+					protected.POST("/webhooks/deliveries/:delivery_id/retry", webhookHandler.RetryDelivery)
+				}
+
+				// Cloud app synchronisation (CLOUD_APP_URL)
+				if cloudSyncHandler != nil {
+					protected.GET("/cloud-sync/status", cloudSyncHandler.GetStatus)
+					protected.GET("/cloud-sync/queue", cloudSyncHandler.ListQueue)
+					protected.POST("/cloud-sync/queue/:id/retry", cloudSyncHandler.RetryItem)
+					protected.GET("/cloud-sync/failures", cloudSyncHandler.ListFailures)
+					protected.POST("/cloud-sync/reconcile", cloudSyncHandler.Reconcile)
+					protected.GET("/cloud-sync/reconciliations", cloudSyncHandler.ListReconciliations)
+				}
 			}
 		}
 	}
 
 	// ── Unified data endpoint (token-authenticated) ──────────────────────────
+	// Deprecated in favour of /api/v2; responses carry Deprecation/Sunset headers
 	api := router.Group("/api/v1")
-	api.Use(middleware.CombinedAuth(tokenService))
+	api.Use(
+		middleware.Deprecated(apiConfig.V1DeprecatedAt, apiConfig.V1Sunset, "/api/v1", "/api/v2"),
+		middleware.APIVersion(middleware.APIVersionV1),
+		middleware.CombinedAuth(tokenService),
+	)
 	{
 		data := api.Group("/data")
 		{
 			data.GET("", dataHandler.GetAll)
 			data.GET("/metadata", dataHandler.GetMetadata)
+			data.GET("/by-flm", dataHandler.GetByFLM)
+			data.GET("/stream", streamHandler.Stream)
 			data.GET("/:terminal_id", dataHandler.GetByID)
-			data.PUT("/:terminal_id", dataHandler.Update)
+			data.PUT("/:terminal_id", middleware.Idempotency(idempotencyService), dataHandler.Update)
+		}
+
+		if statsHandler != nil {
+			stats := api.Group("/stats")
+			{
+				stats.GET("/critical", statsHandler.GetCritical)
+			}
 		}
+
+		// GraphQL over the same data; query cost is capped by depth and row count
+		api.GET("/graphql", graphqlHandler.Query)
+		api.POST("/graphql", graphqlHandler.Query)
+	}
+
+	// ── v2: same data, data/meta envelopes; errors are problem+json ──────────
+	v2 := router.Group("/api/v2")
+	v2.Use(
+		middleware.APIVersion(middleware.APIVersionV2),
+		middleware.CombinedAuth(tokenService),
+	)
+	{
+		data := v2.Group("/data")
+		{
+			data.GET("", v2Handler.ListData)
+			data.GET("/metadata", v2Handler.GetMetadata)
+			data.GET("/by-flm", v2Handler.GetByFLM)
+			data.GET("/stream", streamHandler.Stream)
+			data.GET("/:terminal_id", v2Handler.GetData)
+			data.PUT("/:terminal_id", middleware.Idempotency(idempotencyService), v2Handler.UpdateData)
+		}
+
+		v2.GET("/stats/critical", v2Handler.GetCritical)
+
+		// SSE and GraphQL keep their protocol-defined bodies
+		v2.GET("/graphql", graphqlHandler.Query)
+		v2.POST("/graphql", graphqlHandler.Query)
+	}
+}
+
+// redirectTo sends the client to a fixed path.
+func redirectTo(path string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, path)
+	}
+}
+
+// swaggerUI serves the embedded Swagger UI for one generated spec instance.
+// Each mount gets its own file handler because gin-swagger pins the handler's
+// URL prefix on first use.
+func swaggerUI(instanceName string) gin.HandlerFunc {
+	files := &webdav.Handler{
+		FileSystem: swaggerFiles.FS,
+		LockSystem: webdav.NewMemLS(),
 	}
+	return ginSwagger.WrapHandler(files, ginSwagger.InstanceName(instanceName))
 }
@@ -22,6 +22,9 @@ UNIT_FILE="/etc/systemd/system/${SERVICE_NAME}.service"
 SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
 BINARY="${SCRIPT_DIR}/${SERVICE_NAME}"
 ENV_FILE="${SCRIPT_DIR}/.env"
+# One file per secret, named after its variable in lower case
+# (e.g. secrets/ticket_db_password); passed in as systemd credentials
+SECRETS_DIR="${SCRIPT_DIR}/secrets"
 
 # ---------------------------------------------------------------------------
 # Helpers
@@ -63,6 +66,19 @@ cmd_install() {
     # Defaults to the owner of the binary; override by setting SERVICE_USER env var
     SERVICE_USER="${SERVICE_USER:-$(stat -c '%U' "${BINARY}")}"
 
+    # Each secrets/<name> file becomes a credential the gateway reads through
+    # <NAME>_FILE, so the value stays out of the unit and the environment
+    CREDENTIALS=""
+    if [[ -d "${SECRETS_DIR}" ]]; then
+        for secret in "${SECRETS_DIR}"/*; do
+            [[ -f "${secret}" ]] || continue
+            name="$(basename "${secret}")"
+            CREDENTIALS+="LoadCredential=${name}:${secret}"$'\n'
+            CREDENTIALS+="Environment=${name^^}_FILE=%d/${name}"$'\n'
+            echo "   Secret: ${name^^}_FILE"
+        done
+    fi
+
     cat > "${UNIT_FILE}" <<EOF
 [Unit]
 Description=BASTET API Gateway
@@ -74,7 +90,7 @@ Type=simple
 User=${SERVICE_USER}
 WorkingDirectory=${SCRIPT_DIR}
 EnvironmentFile=${ENV_FILE}
-ExecStart=${BINARY}
+${CREDENTIALS}ExecStart=${BINARY}
 Restart=on-failure
 RestartSec=5s
 StandardOutput=journal
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"api-gateway/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CloudAppClient talks to the cloud app's ticket API:
+//
+//	PUT {CLOUD_APP_URL}/tickets/{terminal_id}  upsert one ticket (CloudTicket body)
+//	GET {CLOUD_APP_URL}/tickets                list open tickets ({"data": [CloudTicket...]})
+//
+// Requests carry the CLOUD_APP_API_KEY in the X-API-Key header.
+type CloudAppClient struct {
+	baseURL string
+	apiKey  atomic.Pointer[string] // replaced by SetAPIKey on config reload
+	client  *http.Client
+}
+
+// NewCloudAppClient creates a new CloudAppClient instance.
+func NewCloudAppClient(baseURL, apiKey string, timeout time.Duration) (*CloudAppClient, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("CLOUD_APP_URL must be an absolute http(s) URL")
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	c := &CloudAppClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+	c.SetAPIKey(apiKey)
+	return c, nil
+}
+
+// SetAPIKey replaces the key sent with requests from now on.
+func (c *CloudAppClient) SetAPIKey(apiKey string) {
+	c.apiKey.Store(&apiKey)
+}
+
+// BaseURL returns the configured cloud app URL with any credentials redacted.
+func (c *CloudAppClient) BaseURL() string {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return ""
+	}
+	return u.Redacted()
+}
+
+// PutTicket upserts one ticket. It returns the response status code (0 when no
+// response was received); any non-2xx response is an error.
+func (c *CloudAppClient) PutTicket(ctx context.Context, terminalID string, body []byte) (int, error) {
+	endpoint := c.baseURL + "/tickets/" + url.PathEscape(terminalID)
+	resp, err := c.do(ctx, http.MethodPut, endpoint, body)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// ListTickets fetches every open ticket the cloud app holds.
+func (c *CloudAppClient) ListTickets(ctx context.Context) ([]*models.CloudTicket, error) {
+	resp, err := c.do(ctx, http.MethodGet, c.baseURL+"/tickets", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil, fmt.Errorf("cloud app returned status %d", resp.StatusCode)
+	}
+
+	var list models.CloudTicketListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode cloud app tickets: %v", err)
+	}
+	return list.Data, nil
+}
+
+// do sends one authenticated request
+func (c *CloudAppClient) do(ctx context.Context, method, endpoint string, body []byte) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "api-gateway-cloud-sync/1.0")
+	if apiKey := *c.apiKey.Load(); apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	return c.client.Do(req)
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"api-gateway/models"
+	"api-gateway/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	cloudSyncBatchSize     = 50
+	cloudSyncBaseBackoff   = 30 * time.Second
+	cloudSyncMaxBackoff    = 1 * time.Hour
+	cloudSyncMaxDiffDetail = 500 // differences stored per reconciliation run
+)
+
+// CloudSyncService mirrors ticket updates made through the gateway to the cloud
+// app. Updates are queued durably and pushed by a background worker; a periodic
+// reconciliation compares open_ticket with the cloud app and queues repairs.
+type CloudSyncService struct {
+	repo      *repository.CloudSyncRepository
+	dataRepo  *repository.DataRepository
+	tokenRepo *repository.TokenRepository // audit logging
+	client    *CloudAppClient             // nil when CLOUD_APP_URL is not set
+	logger    *logrus.Logger
+
+	pollInterval      time.Duration
+	reconcileInterval time.Duration // 0 disables scheduled reconciliation
+	maxAttempts       int
+
+	reconcileMu sync.Mutex // one reconciliation at a time
+}
+
+// NewCloudSyncService creates a new CloudSyncService instance. A nil client
+// disables syncing; status and queue history stay available.
+func NewCloudSyncService(repo *repository.CloudSyncRepository, dataRepo *repository.DataRepository, tokenRepo *repository.TokenRepository, client *CloudAppClient, pollInterval, reconcileInterval time.Duration, maxAttempts int, logger *logrus.Logger) *CloudSyncService {
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	if reconcileInterval < 0 {
+		reconcileInterval = 0
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+	return &CloudSyncService{
+		repo:              repo,
+		dataRepo:          dataRepo,
+		tokenRepo:         tokenRepo,
+		client:            client,
+		logger:            logger,
+		pollInterval:      pollInterval,
+		reconcileInterval: reconcileInterval,
+		maxAttempts:       maxAttempts,
+	}
+}
+
+// Enabled reports whether a cloud app is configured.
+func (s *CloudSyncService) Enabled() bool {
+	return s.client != nil
+}
+
+// ============================================================================
+// Queueing
+// ============================================================================
+
+// Enqueue queues the current state of each row for the cloud app. It is a no-op
+// when syncing is disabled.
+func (s *CloudSyncService) Enqueue(source string, rows ...*models.DataRow) error {
+	if !s.Enabled() || len(rows) == 0 {
+		return nil
+	}
+
+	now := time.Now().In(models.TicketLocation()).Format(time.RFC3339)
+	items := make([]*models.CloudSyncItem, 0, len(rows))
+	for _, row := range rows {
+		ticket := models.NewCloudTicket(row)
+		ticket.UpdatedAt = now
+		payload, err := json.Marshal(ticket)
+		if err != nil {
+			return err
+		}
+		items = append(items, &models.CloudSyncItem{
+			TerminalID: row.TerminalID,
+			Source:     source,
+			Payload:    string(payload),
+		})
+	}
+
+	if err := s.repo.Enqueue(items); err != nil {
+		return fmt.Errorf("failed to queue cloud sync: %v", err)
+	}
+	return nil
+}
+
+// ============================================================================
+// Sync Worker
+// ============================================================================
+
+// Run pushes due queue items and runs scheduled reconciliations until ctx is
+// cancelled. It returns immediately when syncing is disabled.
+func (s *CloudSyncService) Run(ctx context.Context) {
+	if !s.Enabled() {
+		return
+	}
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	var reconcile <-chan time.Time
+	if s.reconcileInterval > 0 {
+		reconcileTicker := time.NewTicker(s.reconcileInterval)
+		defer reconcileTicker.Stop()
+		reconcile = reconcileTicker.C
+	}
+
+	s.logger.Infof("Cloud sync worker started (poll interval %s, reconcile interval %s)", s.pollInterval, s.reconcileInterval)
+	for {
+		s.syncDue(ctx)
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Cloud sync worker stopped")
+			return
+		case <-ticker.C:
+		case <-reconcile:
+			if _, err := s.Reconcile(ctx, nil); err != nil {
+				s.logger.Errorf("Scheduled cloud sync reconciliation failed: %v", err)
+			}
+		}
+	}
+}
+
+// syncDue claims and pushes batches until nothing is due.
+func (s *CloudSyncService) syncDue(ctx context.Context) {
+	// The lease outlives one request timeout so a slow attempt is not claimed twice
+	lease := 2*s.client.client.Timeout + 30*time.Second
+
+	for ctx.Err() == nil {
+		items, err := s.repo.ClaimDue(cloudSyncBatchSize, lease)
+		if err != nil {
+			s.logger.Errorf("Failed to claim cloud sync items: %v", err)
+			return
+		}
+		for _, item := range items {
+			s.push(ctx, item)
+		}
+		if len(items) < cloudSyncBatchSize {
+			return
+		}
+	}
+}
+
+// push makes one attempt to send a snapshot and records the outcome.
+func (s *CloudSyncService) push(ctx context.Context, item *models.CloudSyncItem) {
+	attempts := item.Attempts + 1
+	statusCode, err := s.client.PutTicket(ctx, item.TerminalID, []byte(item.Payload))
+	if err == nil {
+		if err := s.repo.MarkSynced(item.ID, attempts, statusCode); err != nil {
+			s.logger.Errorf("Failed to mark cloud sync item %d synced: %v", item.ID, err)
+		}
+		return
+	}
+
+	var code *int
+	if statusCode > 0 {
+		code = &statusCode
+	}
+	final := attempts >= s.maxAttempts
+	next := time.Now().Add(cloudSyncBackoff(attempts))
+	if final {
+		s.logger.Warnf("Cloud sync of terminal %s failed after %d attempts: %v", item.TerminalID, attempts, err)
+	}
+	if err := s.repo.MarkFailed(item.ID, attempts, code, err.Error(), next, final); err != nil {
+		s.logger.Errorf("Failed to record cloud sync item %d failure: %v", item.ID, err)
+	}
+}
+
+// cloudSyncBackoff returns the wait before the next attempt: 30s doubling per
+// attempt, capped at 1h.
+func cloudSyncBackoff(attempts int) time.Duration {
+	d := cloudSyncBaseBackoff
+	for i := 1; i < attempts && d < cloudSyncMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > cloudSyncMaxBackoff {
+		d = cloudSyncMaxBackoff
+	}
+	return d
+}
+
+// ============================================================================
+// Reconciliation
+// ============================================================================
+
+// Reconcile compares every open ticket with the cloud app's copy. Tickets that
+// are missing from the cloud app or differ from it are queued again; tickets the
+// cloud app holds but open_ticket does not are only reported. The run is recorded
+// even when it fails. triggeredBy is nil for scheduled runs.
+func (s *CloudSyncService) Reconcile(ctx context.Context, triggeredBy *int) (*models.CloudSyncReconciliation, error) {
+	if !s.Enabled() {
+		return nil, ErrCloudSyncDisabled
+	}
+
+	s.reconcileMu.Lock()
+	defer s.reconcileMu.Unlock()
+
+	rec := &models.CloudSyncReconciliation{StartedAt: time.Now(), TriggeredBy: triggeredBy}
+	runErr := s.reconcile(ctx, rec)
+	if runErr != nil {
+		rec.Error = runErr.Error()
+	}
+	rec.FinishedAt = time.Now()
+
+	if err := s.repo.CreateReconciliation(rec); err != nil {
+		s.logger.WithContext(ctx).Errorf("Failed to record cloud sync reconciliation: %v", err)
+	}
+	if runErr != nil {
+		return nil, runErr
+	}
+
+	s.logger.WithContext(ctx).Infof("Cloud sync reconciliation: %d local, %d remote, %d mismatched, %d missing remotely, %d extra remotely",
+		rec.LocalCount, rec.RemoteCount, rec.Mismatched, rec.MissingRemote, rec.ExtraRemote)
+
+	if triggeredBy != nil {
+		_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+			AdminUserID: triggeredBy, Action: "reconcile_cloud_sync",
+			ResourceType: "cloud_sync_reconciliation", ResourceID: &rec.ID,
+			Description: fmt.Sprintf("Ran cloud sync reconciliation (%d requeued)", rec.Requeued),
+			RequestID:   RequestIDFromContext(ctx),
+		})
+	}
+	return rec, nil
+}
+
+// reconcile fills rec with the comparison result and queues repairs.
+func (s *CloudSyncService) reconcile(ctx context.Context, rec *models.CloudSyncReconciliation) error {
+	rows, _, err := s.dataRepo.GetAll(ctx, nil, repository.QueryParams{})
+	if err != nil {
+		return fmt.Errorf("failed to load open tickets: %v", err)
+	}
+	remote, err := s.client.ListTickets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list cloud app tickets: %v", err)
+	}
+	rec.LocalCount, rec.RemoteCount = len(rows), len(remote)
+
+	remoteByID := make(map[string]*models.CloudTicket, len(remote))
+	for _, t := range remote {
+		remoteByID[t.TerminalID] = t
+	}
+
+	var diffs []models.CloudSyncDiff
+	var repairs []*models.DataRow
+	for _, row := range rows {
+		other, ok := remoteByID[row.TerminalID]
+		if !ok {
+			rec.MissingRemote++
+			diffs = append(diffs, models.CloudSyncDiff{TerminalID: row.TerminalID, Kind: models.CloudDiffMissingRemote})
+			repairs = append(repairs, row)
+			continue
+		}
+		delete(remoteByID, row.TerminalID)
+
+		if fields := models.NewCloudTicket(row).DiffFields(other); len(fields) > 0 {
+			rec.Mismatched++
+			diffs = append(diffs, models.CloudSyncDiff{TerminalID: row.TerminalID, Kind: models.CloudDiffMismatch, Fields: fields})
+			repairs = append(repairs, row)
+			continue
+		}
+		rec.Matched++
+	}
+
+	// Whatever is left is open remotely but not in open_ticket
+	extra := make([]string, 0, len(remoteByID))
+	for id := range remoteByID {
+		extra = append(extra, id)
+	}
+	sort.Strings(extra)
+	for _, id := range extra {
+		diffs = append(diffs, models.CloudSyncDiff{TerminalID: id, Kind: models.CloudDiffExtraRemote})
+	}
+	rec.ExtraRemote = len(extra)
+
+	if len(diffs) > cloudSyncMaxDiffDetail {
+		diffs = diffs[:cloudSyncMaxDiffDetail]
+	}
+	rec.Details = diffs
+
+	if err := s.Enqueue(models.CloudSyncSourceReconcile, repairs...); err != nil {
+		return err
+	}
+	rec.Requeued = len(repairs)
+	return nil
+}
+
+// ============================================================================
+// Status & Failures
+// ============================================================================
+
+// GetStatus reports whether syncing is enabled, the queue state and the latest
+// reconciliation run
+func (s *CloudSyncService) GetStatus() (*models.CloudSyncStatus, error) {
+	stats, err := s.repo.GetQueueStats()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.CloudSyncStatus{
+		Enabled: s.Enabled(),
+		Queue:   *stats,
+	}
+	if s.Enabled() {
+		status.CloudAppURL = s.client.BaseURL()
+		if s.reconcileInterval > 0 {
+			status.ReconcileInterval = s.reconcileInterval.String()
+		}
+	}
+
+	recs, err := s.repo.ListReconciliations(1)
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) > 0 {
+		status.LastReconciliation = recs[0]
+	}
+	return status, nil
+}
+
+// GetItems lists recent queue items, optionally by status
+func (s *CloudSyncService) GetItems(status string, limit int) ([]*models.CloudSyncItem, error) {
+	return s.repo.ListItems(status, limit)
+}
+
+// GetFailures lists items that exhausted their attempts
+func (s *CloudSyncService) GetFailures(limit int) ([]*models.CloudSyncItem, error) {
+	return s.repo.ListItems(models.CloudSyncFailed, limit)
+}
+
+// RetryItem puts a failed item back in the queue
+func (s *CloudSyncService) RetryItem(ctx context.Context, id int64, retriedBy int) error {
+	if !s.Enabled() {
+		return ErrCloudSyncDisabled
+	}
+	if err := s.repo.RequeueItem(id); err != nil {
+		return err
+	}
+
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &retriedBy, Action: "retry_cloud_sync",
+		ResourceType: "cloud_sync_item",
+		Description:  fmt.Sprintf("Requeued cloud sync item %d", id),
+		RequestID:    RequestIDFromContext(ctx),
+	})
+	return nil
+}
+
+// GetReconciliations lists recent reconciliation runs
+func (s *CloudSyncService) GetReconciliations(limit int) ([]*models.CloudSyncReconciliation, error) {
+	return s.repo.ListReconciliations(limit)
+}
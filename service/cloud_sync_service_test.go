@@ -0,0 +1,406 @@
+package service
+
+import (
+	"api-gateway/models"
+	"api-gateway/repository"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ============================================================================
+// Fake database
+// ============================================================================
+
+// fakeDB is a database/sql driver that answers statements from canned results
+// matched by a fragment of their SQL, and records every statement it runs.
+// Each result is used once; statements without one return no rows.
+type fakeDB struct {
+	mu      sync.Mutex
+	results []*fakeResult
+	calls   []fakeCall
+}
+
+type fakeResult struct {
+	match   string
+	columns []string
+	rows    [][]driver.Value
+}
+
+type fakeCall struct {
+	query string
+	args  []driver.Value
+}
+
+// expect queues the rows returned by the next statement containing match.
+func (db *fakeDB) expect(match string, columns []string, rows ...[]driver.Value) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.results = append(db.results, &fakeResult{match: match, columns: columns, rows: rows})
+}
+
+// find returns the calls whose SQL contains match, in order.
+func (db *fakeDB) find(match string) []fakeCall {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	var calls []fakeCall
+	for _, c := range db.calls {
+		if strings.Contains(c.query, match) {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+func (db *fakeDB) run(query string, named []driver.NamedValue) *fakeResult {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	args := make([]driver.Value, len(named))
+	for i, nv := range named {
+		args[i] = nv.Value
+	}
+	db.calls = append(db.calls, fakeCall{query: query, args: args})
+	for i, r := range db.results {
+		if strings.Contains(query, r.match) {
+			db.results = append(db.results[:i], db.results[i+1:]...)
+			return r
+		}
+	}
+	return &fakeResult{}
+}
+
+func (db *fakeDB) open() *sql.DB {
+	return sql.OpenDB(fakeConnector{db})
+}
+
+type fakeConnector struct{ db *fakeDB }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{c.db}, nil }
+func (c fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.db, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{c.db}, nil }
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	r := c.db.run(query, args)
+	return &fakeRows{columns: r.columns, rows: r.rows}, nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.run(query, args)
+	return driver.RowsAffected(1), nil
+}
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return fakeConn{s.db}.ExecContext(context.Background(), s.query, named(args))
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return fakeConn{s.db}.QueryContext(context.Background(), s.query, named(args))
+}
+
+func named(args []driver.Value) []driver.NamedValue {
+	nv := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		nv[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return nv
+}
+
+type fakeTx struct{ db *fakeDB }
+
+func (tx fakeTx) Commit() error   { tx.db.run("COMMIT", nil); return nil }
+func (tx fakeTx) Rollback() error { tx.db.run("ROLLBACK", nil); return nil }
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// ============================================================================
+// Cloud app stand-in
+// ============================================================================
+
+// cloudAppStub serves the cloud app's ticket API. PUTs are recorded and
+// answered with the queued statuses (200 once they run out); GET /tickets
+// returns tickets.
+type cloudAppStub struct {
+	mu       sync.Mutex
+	statuses []int
+	puts     []stubPut
+	tickets  []*models.CloudTicket
+}
+
+type stubPut struct {
+	path, apiKey, body string
+}
+
+func (s *cloudAppStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/tickets/"):
+		body, _ := io.ReadAll(r.Body)
+		s.puts = append(s.puts, stubPut{path: r.URL.Path, apiKey: r.Header.Get("X-API-Key"), body: string(body)})
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		w.WriteHeader(status)
+	case r.Method == http.MethodGet && r.URL.Path == "/tickets":
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(models.CloudTicketListResponse{Data: s.tickets})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestCloudSync(t *testing.T, db *fakeDB, stub *cloudAppStub) *CloudSyncService {
+	t.Helper()
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+
+	client, err := NewCloudAppClient(srv.URL, "test-key", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	conn := db.open()
+	t.Cleanup(func() { conn.Close() })
+	return NewCloudSyncService(
+		repository.NewCloudSyncRepository(conn, logger),
+		repository.NewDataRepository(conn, repository.Timeouts{}, logger),
+		repository.NewTokenRepository(conn, repository.Timeouts{}, logger),
+		client, time.Second, 0, 3, logger,
+	)
+}
+
+var cloudSyncItemColumns = []string{
+	"id", "terminal_id", "source", "payload", "status", "attempts", "next_attempt_at",
+	"last_status_code", "last_error", "synced_at", "created_at",
+}
+
+func cloudSyncItemRow(id int64, terminalID, payload string, attempts int64) []driver.Value {
+	now := time.Now()
+	return []driver.Value{id, terminalID, "update", payload, "pending", attempts, now, nil, "", nil, now}
+}
+
+// ============================================================================
+// Tests
+// ============================================================================
+
+func TestCloudSyncPushesDueItems(t *testing.T) {
+	db := &fakeDB{}
+	stub := &cloudAppStub{}
+	svc := newTestCloudSync(t, db, stub)
+
+	payload := `{"terminal_id":"ATM 001","status":"0.NEW"}`
+	db.expect("UPDATE TOP (@p1) cloud_sync_queue", cloudSyncItemColumns, cloudSyncItemRow(7, "ATM 001", payload, 0))
+	svc.syncDue(context.Background())
+
+	if len(stub.puts) != 1 {
+		t.Fatalf("cloud app got %d PUTs, want 1", len(stub.puts))
+	}
+	put := stub.puts[0]
+	if put.path != "/tickets/ATM 001" || put.apiKey != "test-key" || put.body != payload {
+		t.Errorf("PUT %s (key %q) body %s", put.path, put.apiKey, put.body)
+	}
+
+	synced := db.find("synced_at = GETDATE()")
+	if len(synced) != 1 {
+		t.Fatalf("got %d MarkSynced calls, want 1", len(synced))
+	}
+	if got := synced[0].args; got[0] != int64(1) || got[1] != int64(http.StatusOK) || got[2] != int64(7) {
+		t.Errorf("MarkSynced args = %v, want attempts 1, status 200, id 7", got)
+	}
+}
+
+func TestCloudSyncRetriesServerErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempts   int64 // before this push
+		wantStatus string
+		wantWait   time.Duration
+	}{
+		{"first failure is retried", 0, models.CloudSyncPending, cloudSyncBaseBackoff},
+		{"second failure backs off further", 1, models.CloudSyncPending, 2 * cloudSyncBaseBackoff},
+		{"last attempt fails the item", 2, models.CloudSyncFailed, 4 * cloudSyncBaseBackoff},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{}
+			stub := &cloudAppStub{statuses: []int{http.StatusServiceUnavailable}}
+			svc := newTestCloudSync(t, db, stub)
+
+			db.expect("UPDATE TOP (@p1) cloud_sync_queue", cloudSyncItemColumns, cloudSyncItemRow(9, "ATM-002", `{}`, tt.attempts))
+			start := time.Now()
+			svc.syncDue(context.Background())
+
+			failed := db.find("last_error = @p4")
+			if len(failed) != 1 {
+				t.Fatalf("got %d MarkFailed calls, want 1", len(failed))
+			}
+			args := failed[0].args
+			if args[0] != tt.wantStatus || args[1] != tt.attempts+1 || args[2] != int64(http.StatusServiceUnavailable) {
+				t.Errorf("MarkFailed args = %v, want status %s, attempts %d, code 503", args, tt.wantStatus, tt.attempts+1)
+			}
+			next, _ := args[4].(time.Time)
+			if wait := next.Sub(start); wait < tt.wantWait || wait > tt.wantWait+time.Second {
+				t.Errorf("next attempt in %s, want %s", wait, tt.wantWait)
+			}
+			if len(db.find("synced_at = GETDATE()")) != 0 {
+				t.Error("failed push was marked synced")
+			}
+		})
+	}
+
+	t.Run("retry after a failure succeeds", func(t *testing.T) {
+		db := &fakeDB{}
+		stub := &cloudAppStub{statuses: []int{http.StatusBadGateway}}
+		svc := newTestCloudSync(t, db, stub)
+
+		db.expect("UPDATE TOP (@p1) cloud_sync_queue", cloudSyncItemColumns, cloudSyncItemRow(3, "ATM-003", `{}`, 0))
+		svc.syncDue(context.Background())
+		db.expect("UPDATE TOP (@p1) cloud_sync_queue", cloudSyncItemColumns, cloudSyncItemRow(3, "ATM-003", `{}`, 1))
+		svc.syncDue(context.Background())
+
+		if len(stub.puts) != 2 {
+			t.Fatalf("cloud app got %d PUTs, want 2", len(stub.puts))
+		}
+		synced := db.find("synced_at = GETDATE()")
+		if len(synced) != 1 || synced[0].args[0] != int64(2) {
+			t.Errorf("MarkSynced calls = %v, want one with attempts 2", synced)
+		}
+	})
+}
+
+func TestCloudSyncEnqueueSupersedesPending(t *testing.T) {
+	db := &fakeDB{}
+	svc := newTestCloudSync(t, db, &cloudAppStub{})
+
+	row := &models.DataRow{TerminalID: "ATM-004", Status: models.NullString{NullString: sql.NullString{String: "1.OPEN", Valid: true}}}
+	if err := svc.Enqueue(models.CloudSyncSourceUpdate, row); err != nil {
+		t.Fatal(err)
+	}
+
+	var order []string
+	for _, c := range db.calls {
+		switch {
+		case strings.Contains(c.query, "SET status = 'superseded'"):
+			order = append(order, "supersede "+c.args[0].(string))
+		case strings.Contains(c.query, "INSERT INTO cloud_sync_queue"):
+			order = append(order, "insert "+c.args[0].(string))
+			var ticket models.CloudTicket
+			if err := json.Unmarshal([]byte(c.args[2].(string)), &ticket); err != nil || ticket.Status != "1.OPEN" || ticket.UpdatedAt == "" {
+				t.Errorf("queued payload %s", c.args[2])
+			}
+		case c.query == "COMMIT":
+			order = append(order, "commit")
+		}
+	}
+	want := []string{"supersede ATM-004", "insert ATM-004", "commit"}
+	if strings.Join(order, ", ") != strings.Join(want, ", ") {
+		t.Errorf("statements = %v, want %v", order, want)
+	}
+}
+
+func TestCloudSyncReconcile(t *testing.T) {
+	db := &fakeDB{}
+	stub := &cloudAppStub{tickets: []*models.CloudTicket{
+		{TerminalID: "ATM-001", TerminalName: "One", Status: "0.NEW"},
+		{TerminalID: "ATM-002", TerminalName: "Two", Status: "0.NEW"},
+		{TerminalID: "ATM-009", TerminalName: "Gone", Status: "0.NEW"},
+	}}
+	svc := newTestCloudSync(t, db, stub)
+
+	dataRow := func(id, name, status string) []driver.Value {
+		v := make([]driver.Value, 27)
+		v[0], v[1], v[8], v[9], v[11], v[14] = id, name, int64(0), status, int64(0), float64(0)
+		return v
+	}
+	columns := make([]string, 27)
+	db.expect("SELECT COUNT(*)", []string{""}, []driver.Value{int64(3)})
+	db.expect("op.[Terminal Name]", columns,
+		dataRow("ATM-001", "One", "0.NEW"),
+		dataRow("ATM-002", "Two", "2.Kirim FLM"),
+		dataRow("ATM-003", "Three", "0.NEW"),
+	)
+	db.expect("INSERT INTO cloud_sync_reconciliations", []string{"id"}, []driver.Value{int64(5)})
+
+	rec, err := svc.Reconcile(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.ID != 5 || rec.LocalCount != 3 || rec.RemoteCount != 3 || rec.Matched != 1 ||
+		rec.Mismatched != 1 || rec.MissingRemote != 1 || rec.ExtraRemote != 1 || rec.Requeued != 2 {
+		t.Errorf("reconciliation = %+v", rec)
+	}
+
+	wantDiffs := []models.CloudSyncDiff{
+		{TerminalID: "ATM-002", Kind: models.CloudDiffMismatch, Fields: []string{"status"}},
+		{TerminalID: "ATM-003", Kind: models.CloudDiffMissingRemote},
+		{TerminalID: "ATM-009", Kind: models.CloudDiffExtraRemote},
+	}
+	if got, want := mustJSON(t, rec.Details), mustJSON(t, wantDiffs); got != want {
+		t.Errorf("details = %s, want %s", got, want)
+	}
+
+	var requeued []string
+	for _, c := range db.find("INSERT INTO cloud_sync_queue") {
+		if c.args[1] != models.CloudSyncSourceReconcile {
+			t.Errorf("repair queued with source %v", c.args[1])
+		}
+		requeued = append(requeued, c.args[0].(string))
+	}
+	if strings.Join(requeued, ",") != "ATM-002,ATM-003" {
+		t.Errorf("requeued %v, want ATM-002 and ATM-003", requeued)
+	}
+	if len(stub.puts) != 0 {
+		t.Errorf("reconciliation pushed %d tickets directly; repairs go through the queue", len(stub.puts))
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
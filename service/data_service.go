@@ -1,8 +1,12 @@
 package service
 
 import (
+	"api-gateway/metrics"
 	"api-gateway/models"
 	"api-gateway/repository"
+	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,8 +15,11 @@ import (
 
 // DataService handles business logic for the unified /api/v1/data endpoint.
 type DataService struct {
-	repo   *repository.DataRepository
-	logger *logrus.Logger
+	repo      *repository.DataRepository
+	sla       *SLAService       // optional; nil when the token database is unavailable
+	webhooks  *WebhookService   // optional; nil when the token database is unavailable
+	cloudSync *CloudSyncService // optional; nil when the token database is unavailable
+	logger    *logrus.Logger
 
 	// Metadata caching
 	metadataCache     *models.MetadataResponse
@@ -21,55 +28,254 @@ type DataService struct {
 	metadataCacheTTL  time.Duration
 }
 
-// NewDataService creates a new DataService instance.
-func NewDataService(repo *repository.DataRepository, logger *logrus.Logger) *DataService {
+// NewDataService creates a new DataService instance. slaService, webhookService
+// and cloudSyncService may be nil, in which case SLA fields stay null and no
+// webhooks or cloud syncs are queued.
+func NewDataService(repo *repository.DataRepository, slaService *SLAService, webhookService *WebhookService, cloudSyncService *CloudSyncService, logger *logrus.Logger) *DataService {
 	return &DataService{
 		repo:             repo,
+		sla:              slaService,
+		webhooks:         webhookService,
+		cloudSync:        cloudSyncService,
 		logger:           logger,
 		metadataCacheTTL: 1 * time.Hour,
 	}
 }
 
+// SetMetadataCacheTTL changes how long GetMetadata reuses its result. A
+// shorter TTL also applies to the result already cached.
+func (s *DataService) SetMetadataCacheTTL(ttl time.Duration) {
+	s.metadataCacheMux.Lock()
+	s.metadataCacheTTL = ttl
+	s.metadataCacheMux.Unlock()
+}
+
 // GetAll retrieves data rows with optional vendor scoping, pagination, sorting, and filtering.
-func (s *DataService) GetAll(filter *repository.VendorFilter, p repository.QueryParams) ([]*models.DataRow, int, error) {
-	s.logger.Info("Fetching data rows")
-	return s.repo.GetAll(filter, p)
+// When slaBreached is set, SLA state is computed for every matching row and the
+// breached (or non-breached) rows are paginated in memory.
+func (s *DataService) GetAll(ctx context.Context, filter *repository.VendorFilter, p repository.QueryParams, slaBreached *bool) ([]*models.DataRow, int, error) {
+	s.logger.WithContext(ctx).Info("Fetching data rows")
+	if slaBreached == nil {
+		rows, total, err := s.repo.GetAll(ctx, filter, p)
+		if err != nil {
+			return nil, 0, err
+		}
+		s.applySLA(ctx, rows...)
+		return rows, total, nil
+	}
+
+	// Breach state is computed in Go, so the database cannot paginate for us
+	page, pageSize := p.Page, p.PageSize
+	p.Page = 0
+	rows, _, err := s.repo.GetAll(ctx, filter, p)
+	if err != nil {
+		return nil, 0, err
+	}
+	s.applySLA(ctx, rows...)
+
+	matched := make([]*models.DataRow, 0, len(rows))
+	for _, row := range rows {
+		if row.SLABreached != nil && *row.SLABreached == *slaBreached {
+			matched = append(matched, row)
+		}
+	}
+
+	total := len(matched)
+	if page > 0 {
+		start := (page - 1) * pageSize
+		if start > total {
+			start = total
+		}
+		end := start + pageSize
+		if end > total {
+			end = total
+		}
+		matched = matched[start:end]
+	}
+	return matched, total, nil
 }
 
 // GetByTerminalID retrieves a single row by terminal ID with vendor scoping.
-func (s *DataService) GetByTerminalID(terminalID string, filter *repository.VendorFilter) (*models.DataRow, error) {
-	s.logger.Infof("Fetching data row for terminal: %s", terminalID)
-	return s.repo.GetByTerminalID(terminalID, filter)
+func (s *DataService) GetByTerminalID(ctx context.Context, terminalID string, filter *repository.VendorFilter) (*models.DataRow, error) {
+	s.logger.WithContext(ctx).Infof("Fetching data row for terminal: %s", terminalID)
+	row, err := s.repo.GetByTerminalID(ctx, terminalID, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.applySLA(ctx, row)
+	return row, nil
 }
 
 // Update modifies ticket fields with vendor filter enforcement.
-func (s *DataService) Update(terminalID string, req *models.DataUpdateRequest, filter *repository.VendorFilter) (*models.DataRow, error) {
-	s.logger.Infof("Updating data row for terminal: %s", terminalID)
-	return s.repo.Update(terminalID, req, filter)
+func (s *DataService) Update(ctx context.Context, terminalID string, req *models.DataUpdateRequest, filter *repository.VendorFilter) (*models.DataRow, error) {
+	s.logger.WithContext(ctx).Infof("Updating data row for terminal: %s", terminalID)
+
+	closeTime, err := models.NormalizeTicketTime(req.CloseTime)
+	if err != nil {
+		return nil, fmt.Errorf("%w: close_time: %v", ErrInvalidInput, err)
+	}
+	normalized := *req
+	normalized.CloseTime = closeTime
+
+	row, err := s.repo.Update(ctx, terminalID, &normalized, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.applySLA(ctx, row)
+
+	// The update is already committed; queueing failures are logged, not returned
+	if s.cloudSync != nil {
+		if err := s.cloudSync.Enqueue(models.CloudSyncSourceUpdate, row); err != nil {
+			s.logger.WithContext(ctx).Errorf("Failed to queue cloud sync for terminal %s: %v", terminalID, err)
+		}
+	}
+	if s.webhooks != nil {
+		eventType := models.WebhookTicketUpdated
+		if normalized.CloseTime != "" {
+			eventType = models.WebhookTicketClosed
+		}
+		if err := s.webhooks.Enqueue(eventType, row); err != nil {
+			s.logger.WithContext(ctx).Errorf("Failed to queue %s webhooks for terminal %s: %v", eventType, terminalID, err)
+		}
+	}
+	return row, nil
+}
+
+// applySLA fills the computed SLA fields when an SLA service is configured.
+func (s *DataService) applySLA(ctx context.Context, rows ...*models.DataRow) {
+	if s.sla != nil {
+		s.sla.Apply(ctx, rows)
+	}
+}
+
+// GetTicketsByFLM groups the open tickets in the vendor scope by machine FLM branch.
+// Optional status and priority values narrow the tickets; groups are sorted by
+// ticket count (largest first), then by FLM code.
+func (s *DataService) GetTicketsByFLM(ctx context.Context, filter *repository.VendorFilter, status, priority string) ([]models.FLMTicketsGroup, int, error) {
+	s.logger.WithContext(ctx).Info("Fetching tickets grouped by FLM")
+	rows, total, err := s.repo.GetAll(ctx, filter, repository.QueryParams{
+		SortBy:    "tickets_duration",
+		SortOrder: "desc",
+		Status:    status,
+		Priority:  priority,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	index := make(map[string]int)
+	groups := make([]models.FLMTicketsGroup, 0)
+	for _, row := range rows {
+		flm := row.FLM.String
+		i, ok := index[flm]
+		if !ok {
+			i = len(groups)
+			index[flm] = i
+			groups = append(groups, models.FLMTicketsGroup{
+				FLM:     flm,
+				Area:    models.GetFLMArea(flm),
+				Tickets: []*models.OpenTicket{},
+			})
+		}
+		groups[i].Tickets = append(groups[i].Tickets, row.ToOpenTicket())
+		groups[i].Count++
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+		return groups[i].FLM < groups[j].FLM
+	})
+
+	return groups, total, nil
+}
+
+// GetStats aggregates the open tickets in the vendor scope by status, priority
+// and mode. Each breakdown is sorted by count (largest first), then by value.
+func (s *DataService) GetStats(ctx context.Context, filter *repository.VendorFilter) (*models.TicketStatistics, error) {
+	s.logger.WithContext(ctx).Info("Aggregating ticket statistics")
+	rows, total, err := s.repo.GetAll(ctx, filter, repository.QueryParams{})
+	if err != nil {
+		return nil, err
+	}
+	s.applySLA(ctx, rows...)
+
+	byStatus := map[string]int{}
+	byPriority := map[string]int{}
+	byMode := map[string]int{}
+	stats := &models.TicketStatistics{TotalCount: total}
+	var duration float64
+	for _, row := range rows {
+		byStatus[row.Status.String]++
+		byPriority[row.Priority.String]++
+		byMode[row.Mode.String]++
+		duration += row.TicketsDuration
+		if row.SLABreached != nil && *row.SLABreached {
+			stats.SLABreached++
+		}
+	}
+	if len(rows) > 0 {
+		stats.AvgDuration = duration / float64(len(rows))
+	}
+
+	for _, kv := range sortedCounts(byStatus) {
+		stats.ByStatus = append(stats.ByStatus, models.StatusCount{Status: kv.key, Count: kv.count})
+	}
+	for _, kv := range sortedCounts(byPriority) {
+		stats.ByPriority = append(stats.ByPriority, models.PriorityCount{Priority: kv.key, Count: kv.count})
+	}
+	for _, kv := range sortedCounts(byMode) {
+		stats.ByMode = append(stats.ByMode, models.ModeCount{Mode: kv.key, Count: kv.count})
+	}
+	return stats, nil
+}
+
+type keyCount struct {
+	key   string
+	count int
+}
+
+// sortedCounts orders a count map by count (largest first), then by key.
+func sortedCounts(m map[string]int) []keyCount {
+	out := make([]keyCount, 0, len(m))
+	for k, v := range m {
+		out = append(out, keyCount{k, v})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].count != out[j].count {
+			return out[i].count > out[j].count
+		}
+		return out[i].key < out[j].key
+	})
+	return out
 }
 
-// GetMetadata returns distinct status/mode/priority values with 1-hour caching.
-func (s *DataService) GetMetadata() (*models.MetadataResponse, error) {
+// GetMetadata returns distinct status/mode/priority values, cached for
+// METADATA_CACHE_TTL (1 hour by default).
+func (s *DataService) GetMetadata(ctx context.Context) (*models.MetadataResponse, error) {
 	s.metadataCacheMux.RLock()
 	if s.metadataCache != nil && time.Since(s.metadataLastFetch) < s.metadataCacheTTL {
-		s.logger.Info("Returning cached metadata")
+		metrics.MetadataCacheLookups.WithLabelValues("hit").Inc()
+		s.logger.WithContext(ctx).Info("Returning cached metadata")
 		cached := s.metadataCache
 		s.metadataCacheMux.RUnlock()
 		return cached, nil
 	}
 	s.metadataCacheMux.RUnlock()
 
-	s.logger.Info("Fetching fresh metadata from database")
+	metrics.MetadataCacheLookups.WithLabelValues("miss").Inc()
+	s.logger.WithContext(ctx).Info("Fetching fresh metadata from database")
 
-	statuses, err := s.repo.GetDistinctStatuses()
+	statuses, err := s.repo.GetDistinctStatuses(ctx)
 	if err != nil {
 		return nil, err
 	}
-	modes, err := s.repo.GetDistinctModes()
+	modes, err := s.repo.GetDistinctModes(ctx)
 	if err != nil {
 		return nil, err
 	}
-	priorities, err := s.repo.GetDistinctPriorities()
+	priorities, err := s.repo.GetDistinctPriorities(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,16 @@
 package service
 
-import "errors"
+import (
+	"api-gateway/models"
+	"api-gateway/repository"
+	"context"
+	"errors"
+	"net/http"
+)
+
+// StatusClientClosedRequest is the non-standard status (nginx's 499) recorded
+// when the client went away before the response was ready.
+const StatusClientClosedRequest = 499
 
 // Common service errors
 // These errors provide standardized error messages across the service layer
@@ -9,4 +19,85 @@ var (
 	ErrTicketNotFound      = errors.New("ticket not found")
 	ErrMachineNotFound     = errors.New("machine not found")
 	ErrInvalidInput        = errors.New("invalid input data")
+	ErrCloudSyncDisabled   = errors.New("cloud sync is not configured (CLOUD_APP_URL is empty)")
+
+	// Returned by the repositories and passed through unchanged
+	ErrNotFound         = repository.ErrNotFound
+	ErrOutOfScope       = repository.ErrNotAccessible
+	ErrNoFieldsToUpdate = repository.ErrNoFieldsToUpdate
+	ErrQueryTimeout     = repository.ErrQueryTimeout
+
+	// Token authentication
+	ErrMissingToken       = errors.New("X-API-Token header is required")
+	ErrInvalidToken       = errors.New("invalid token")
+	ErrTokenRevoked       = errors.New("token has been revoked")
+	ErrTokenExpired       = errors.New("token has expired")
+	ErrTokenDisabled      = errors.New("token is disabled")
+	ErrIPNotAllowed       = errors.New("IP address not whitelisted")
+	ErrClientCertRequired = errors.New("token requires a client certificate")
+	ErrClientCertMismatch = errors.New("client certificate does not match the token")
+	ErrRateLimited        = errors.New("rate limit exceeded")
+	ErrTokenDBUnavailable = errors.New("token management system is not configured")
+
+	// Idempotency-Key handling
+	ErrIdempotencyKeyReused  = errors.New("Idempotency-Key was already used with a different request")
+	ErrIdempotencyInProgress = errors.New("a request with this Idempotency-Key is still in progress")
 )
+
+// errorKind is the stable code and HTTP status of a sentinel error.
+type errorKind struct {
+	err    error
+	code   string
+	status int
+}
+
+// errorKinds maps every sentinel to its code. Order matters only for errors
+// that wrap several sentinels; the first match wins.
+var errorKinds = []errorKind{
+	{ErrInvalidInput, models.ErrCodeInvalidRequest, http.StatusBadRequest},
+	{ErrNoFieldsToUpdate, models.ErrCodeNoFieldsToUpdate, http.StatusBadRequest},
+	{ErrOutOfScope, models.ErrCodeOutOfScope, http.StatusForbidden},
+	{ErrNotFound, models.ErrCodeNotFound, http.StatusNotFound},
+	{ErrTicketNotFound, models.ErrCodeNotFound, http.StatusNotFound},
+	{ErrMachineNotFound, models.ErrCodeNotFound, http.StatusNotFound},
+	{ErrTicketAlreadyExists, models.ErrCodeConflict, http.StatusConflict},
+	{ErrIdempotencyKeyReused, models.ErrCodeIdempotencyReused, http.StatusUnprocessableEntity},
+	{ErrIdempotencyInProgress, models.ErrCodeIdempotencyPending, http.StatusConflict},
+	{ErrMissingToken, models.ErrCodeMissingToken, http.StatusUnauthorized},
+	{ErrInvalidToken, models.ErrCodeInvalidToken, http.StatusUnauthorized},
+	{ErrTokenRevoked, models.ErrCodeTokenRevoked, http.StatusUnauthorized},
+	{ErrTokenExpired, models.ErrCodeTokenExpired, http.StatusUnauthorized},
+	{ErrTokenDisabled, models.ErrCodeTokenDisabled, http.StatusUnauthorized},
+	{ErrIPNotAllowed, models.ErrCodeIPNotAllowed, http.StatusForbidden},
+	{ErrClientCertRequired, models.ErrCodeClientCertRequired, http.StatusUnauthorized},
+	{ErrClientCertMismatch, models.ErrCodeClientCertMismatch, http.StatusForbidden},
+	{ErrRateLimited, models.ErrCodeRateLimited, http.StatusTooManyRequests},
+	{ErrCloudSyncDisabled, models.ErrCodeServiceUnavailable, http.StatusServiceUnavailable},
+	{ErrTokenDBUnavailable, models.ErrCodeServiceUnavailable, http.StatusServiceUnavailable},
+	{ErrQueryTimeout, models.ErrCodeQueryTimeout, http.StatusGatewayTimeout},
+	{context.Canceled, models.ErrCodeRequestCanceled, StatusClientClosedRequest},
+}
+
+func kindOf(err error) errorKind {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			return k
+		}
+	}
+	return errorKind{err: err, code: models.ErrCodeInternal, status: http.StatusInternalServerError}
+}
+
+// ErrorCode returns the stable machine-readable code for err, or
+// internal_error when err matches no sentinel. It returns "" for nil.
+func ErrorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	return kindOf(err).code
+}
+
+// ErrorStatus returns the HTTP status that err maps to (500 when it matches
+// no sentinel).
+func ErrorStatus(err error) int {
+	return kindOf(err).status
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"api-gateway/metrics"
+	"api-gateway/models"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HealthDependency is one dependency checked by the HealthService prober.
+// A nil Ping marks it as not configured; it is reported but never probed.
+type HealthDependency struct {
+	Name     string
+	Critical bool // down makes the gateway not ready; otherwise only degraded
+	Ping     func(ctx context.Context) error
+}
+
+// HealthService probes dependencies in the background and caches the results,
+// so /readyz and /health answer immediately however slow a dependency is.
+// Every dependency is pinged on its own goroutine with a timeout; a ping still
+// running after the timeout is reported as down until it returns.
+type HealthService struct {
+	deps     []HealthDependency
+	interval time.Duration
+	timeout  time.Duration
+	logger   *logrus.Logger
+
+	mu       sync.RWMutex
+	results  map[string]*models.DependencyHealth
+	inFlight map[string]time.Time // probe start per dependency still being pinged
+}
+
+// NewHealthService creates a new HealthService instance that pings deps every
+// interval, giving each ping timeout to answer.
+func NewHealthService(deps []HealthDependency, interval, timeout time.Duration, logger *logrus.Logger) *HealthService {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	s := &HealthService{
+		deps:     deps,
+		interval: interval,
+		timeout:  timeout,
+		logger:   logger,
+		results:  make(map[string]*models.DependencyHealth, len(deps)),
+		inFlight: make(map[string]time.Time),
+	}
+	for _, dep := range deps {
+		status := models.DependencyUnknown
+		if dep.Ping == nil {
+			status = models.DependencyNotConfigured
+		}
+		s.results[dep.Name] = &models.DependencyHealth{Name: dep.Name, Status: status, Critical: dep.Critical}
+	}
+	return s
+}
+
+// Run probes every dependency immediately and then every interval until ctx
+// is cancelled.
+func (s *HealthService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Infof("Dependency health prober started (interval %s, timeout %s)", s.interval, s.timeout)
+	for {
+		s.probeAll(ctx)
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Dependency health prober stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeAll starts a ping for every configured dependency that is not still
+// being pinged from an earlier round.
+func (s *HealthService) probeAll(ctx context.Context) {
+	for _, dep := range s.deps {
+		if dep.Ping == nil {
+			continue
+		}
+		s.mu.Lock()
+		if _, busy := s.inFlight[dep.Name]; busy {
+			s.mu.Unlock()
+			continue
+		}
+		s.inFlight[dep.Name] = time.Now()
+		s.mu.Unlock()
+
+		go s.probe(ctx, dep)
+	}
+}
+
+// probe pings one dependency and records the outcome.
+func (s *HealthService) probe(ctx context.Context, dep HealthDependency) {
+	pingCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := dep.Ping(pingCtx)
+	latency := time.Since(start)
+	if err != nil && ctx.Err() != nil {
+		// Shutting down; the failure says nothing about the dependency
+		s.mu.Lock()
+		delete(s.inFlight, dep.Name)
+		s.mu.Unlock()
+		return
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	r := s.results[dep.Name]
+	previous := r.Status
+	r.LatencyMs = float64(latency.Microseconds()) / 1000
+	r.LastCheckedAt = &now
+	if err != nil {
+		r.Status = models.DependencyDown
+		r.Error = err.Error()
+	} else {
+		r.Status = models.DependencyUp
+		r.Error = ""
+		r.LastSuccessAt = &now
+	}
+	delete(s.inFlight, dep.Name)
+	s.mu.Unlock()
+
+	// Log state changes only, not every probe
+	if err != nil {
+		metrics.DependencyUp.WithLabelValues(dep.Name).Set(0)
+		if previous != models.DependencyDown {
+			s.logger.Warnf("Dependency %s is down: %v", dep.Name, err)
+		}
+		return
+	}
+	metrics.DependencyUp.WithLabelValues(dep.Name).Set(1)
+	if previous != models.DependencyUp {
+		s.logger.Infof("Dependency %s is up (%s)", dep.Name, latency.Round(time.Microsecond))
+	}
+}
+
+// Readiness returns the cached state of every dependency and the overall
+// status: not_ready when a critical dependency is down or has not been probed
+// yet, degraded when only non-critical ones are down, ready otherwise.
+// Dependencies that are not configured do not count against readiness.
+func (s *HealthService) Readiness() *models.ReadinessReport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	report := &models.ReadinessReport{
+		Status:       models.ReadinessReady,
+		Dependencies: make([]models.DependencyHealth, 0, len(s.deps)),
+	}
+	for _, dep := range s.deps {
+		r := *s.results[dep.Name]
+		if started, busy := s.inFlight[dep.Name]; busy && time.Since(started) > s.timeout {
+			r.Status = models.DependencyDown
+			r.LatencyMs = float64(time.Since(started).Microseconds()) / 1000
+			r.Error = fmt.Sprintf("no response within %s", s.timeout)
+		}
+		report.Dependencies = append(report.Dependencies, r)
+
+		switch r.Status {
+		case models.DependencyUp, models.DependencyNotConfigured:
+		default:
+			if r.Critical {
+				report.Status = models.ReadinessNotReady
+			} else if report.Status == models.ReadinessReady {
+				report.Status = models.ReadinessDegraded
+			}
+		}
+	}
+	return report
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"api-gateway/models"
+	"api-gateway/repository"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const idempotencyPurgeEvery = time.Hour
+
+// IdempotencyService remembers the response to each Idempotency-Key a token
+// sends so that a retried mutating request is answered from the stored
+// response instead of being applied twice.
+type IdempotencyService struct {
+	repo       *repository.IdempotencyRepository
+	ttl        time.Duration
+	staleAfter time.Duration
+	logger     *logrus.Logger
+}
+
+// NewIdempotencyService creates a new IdempotencyService instance. Keys and
+// their responses are kept for ttl. A key whose request has not finished
+// after staleAfter (the process died mid-request) is freed for a retry, so
+// requests must not run past Deadline.
+func NewIdempotencyService(repo *repository.IdempotencyRepository, ttl, staleAfter time.Duration, logger *logrus.Logger) *IdempotencyService {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	if staleAfter <= 0 {
+		staleAfter = 2 * time.Minute
+	}
+	return &IdempotencyService{
+		repo:       repo,
+		ttl:        ttl,
+		staleAfter: staleAfter,
+		logger:     logger,
+	}
+}
+
+// Begin claims key for the token's request with the given fingerprint.
+//
+// When the key is new it returns the reserved record and replay=false; the
+// caller must then Complete or Release it. When the key already holds a
+// finished response for the same request it returns that record with
+// replay=true. Reusing a key for a different request returns
+// ErrIdempotencyKeyReused, and retrying while the first request is still
+// running returns ErrIdempotencyInProgress.
+func (s *IdempotencyService) Begin(ctx context.Context, tokenID int, key, method, path, requestHash string) (*models.IdempotencyRecord, bool, error) {
+	now := time.Now()
+	rec := &models.IdempotencyRecord{
+		TokenID:     tokenID,
+		Key:         key,
+		Method:      method,
+		Path:        path,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+	}
+
+	reserved, err := s.repo.Reserve(ctx, rec, now.Add(-s.staleAfter))
+	if err != nil {
+		return nil, false, err
+	}
+	if reserved {
+		return rec, false, nil
+	}
+
+	existing, err := s.repo.Get(ctx, tokenID, key)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Purged between Reserve and Get; the client can simply retry
+		return nil, false, ErrIdempotencyInProgress
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if existing.RequestHash != requestHash {
+		return nil, false, ErrIdempotencyKeyReused
+	}
+	if existing.Status != models.IdempotencyCompleted {
+		return nil, false, ErrIdempotencyInProgress
+	}
+	return existing, true, nil
+}
+
+// Deadline is when a retry may take over rec's key. The request holding it
+// must be cancelled by then, or the retry would apply it a second time.
+func (s *IdempotencyService) Deadline(rec *models.IdempotencyRecord) time.Time {
+	return rec.CreatedAt.Add(s.staleAfter)
+}
+
+// Complete stores the response of a request started with Begin. If it cannot
+// be stored the key is released instead, so a retry runs the request again
+// rather than waiting for the stale-lock timeout. It still runs when the
+// client has gone away, since the request itself has already been applied.
+func (s *IdempotencyService) Complete(ctx context.Context, rec *models.IdempotencyRecord, status int, contentType string, body []byte) {
+	ctx = context.WithoutCancel(ctx)
+	if err := s.repo.Complete(ctx, rec.ID, status, contentType, body); err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("idempotency_key", rec.Key).Warn("Failed to store idempotent response")
+		s.Release(ctx, rec)
+	}
+}
+
+// Release frees the key of a request started with Begin without storing a
+// response, so the client may retry it. Like Complete, it outlives the
+// client's connection.
+func (s *IdempotencyService) Release(ctx context.Context, rec *models.IdempotencyRecord) {
+	ctx = context.WithoutCancel(ctx)
+	if err := s.repo.Delete(ctx, rec.ID); err != nil {
+		s.logger.WithContext(ctx).WithError(err).WithField("idempotency_key", rec.Key).Warn("Failed to release idempotency key")
+	}
+}
+
+// Run purges expired keys every hour until ctx is cancelled.
+func (s *IdempotencyService) Run(ctx context.Context) {
+	ticker := time.NewTicker(idempotencyPurgeEvery)
+	defer ticker.Stop()
+
+	s.logger.Infof("Idempotency key purge started (window %s)", s.ttl)
+	for {
+		if n, err := s.repo.DeleteExpired(ctx, time.Now()); err != nil {
+			s.logger.WithError(err).Warn("Failed to purge expired idempotency keys")
+		} else if n > 0 {
+			s.logger.Debugf("Purged %d expired idempotency keys", n)
+		}
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Idempotency key purge stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
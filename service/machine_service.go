@@ -0,0 +1,35 @@
+package service
+
+import (
+	"api-gateway/models"
+	"api-gateway/repository"
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MachineService reads machine master records within a token's vendor scope.
+type MachineService struct {
+	repo   *repository.MachineRepository
+	logger *logrus.Logger
+}
+
+// NewMachineService creates a new MachineService instance.
+func NewMachineService(repo *repository.MachineRepository, logger *logrus.Logger) *MachineService {
+	return &MachineService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// GetByTerminalID retrieves one machine record with vendor scoping.
+func (s *MachineService) GetByTerminalID(ctx context.Context, terminalID string, filter *repository.VendorFilter) (*models.ATMI, error) {
+	s.logger.WithContext(ctx).Infof("Fetching machine for terminal: %s", terminalID)
+	return s.repo.GetByTerminalID(ctx, terminalID, filter)
+}
+
+// GetByTerminalIDs retrieves the machine records of several terminals, keyed by
+// terminal ID, with vendor scoping.
+func (s *MachineService) GetByTerminalIDs(ctx context.Context, terminalIDs []string, filter *repository.VendorFilter) (map[string]*models.ATMI, error) {
+	return s.repo.GetByTerminalIDs(ctx, terminalIDs, filter)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"api-gateway/repository"
+	"context"
+	"sync"
+	"time"
+)
+
+// Rate limiter backends accepted by RATE_LIMIT_BACKEND
+const (
+	RateLimitBackendMemory   = "memory"
+	RateLimitBackendDatabase = "database"
+)
+
+// rateWindows are the windows a token's limits apply to, in the order they
+// are checked. limits passed to a RateLimiter are keyed by name.
+var rateWindows = []struct {
+	name string
+	size time.Duration
+}{
+	{"minute", time.Minute},
+	{"hour", time.Hour},
+	{"day", 24 * time.Hour},
+}
+
+// RateLimiter decides whether a token may make another request. Allow counts
+// the request against every window with a positive limit, unless one of them
+// is already full; it then counts nothing and returns that window's name.
+type RateLimiter interface {
+	Allow(ctx context.Context, tokenID int, limits map[string]int) (exceeded string, err error)
+}
+
+// MemoryRateLimiter keeps sliding-window counters in process. It needs no
+// database round-trip, but every gateway instance enforces the limits on its
+// own share of the traffic. Counters are kept for every token seen, three
+// per token.
+type MemoryRateLimiter struct {
+	mu       sync.Mutex
+	counters map[memoryRateKey]*slidingWindow
+}
+
+type memoryRateKey struct {
+	tokenID int
+	window  string
+}
+
+// slidingWindow approximates a sliding window from two fixed ones: the
+// previous window's count is weighted by how much of it still overlaps.
+type slidingWindow struct {
+	start      time.Time // Start of the current fixed window
+	prev, curr int
+}
+
+// NewMemoryRateLimiter creates a new MemoryRateLimiter instance.
+func NewMemoryRateLimiter() *MemoryRateLimiter {
+	return &MemoryRateLimiter{counters: make(map[memoryRateKey]*slidingWindow)}
+}
+
+// Allow implements RateLimiter.
+func (l *MemoryRateLimiter) Allow(_ context.Context, tokenID int, limits map[string]int) (string, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var take []*slidingWindow
+	for _, rw := range rateWindows {
+		limit := limits[rw.name]
+		if limit <= 0 {
+			continue
+		}
+		key := memoryRateKey{tokenID, rw.name}
+		w := l.counters[key]
+		if w == nil {
+			w = &slidingWindow{}
+			l.counters[key] = w
+		}
+		if w.count(now, rw.size) >= float64(limit) {
+			return rw.name, nil
+		}
+		take = append(take, w)
+	}
+	for _, w := range take {
+		w.curr++
+	}
+	return "", nil
+}
+
+// count rolls w forward to now and returns its estimated number of requests
+// in the last size.
+func (w *slidingWindow) count(now time.Time, size time.Duration) float64 {
+	start := now.Truncate(size)
+	if !start.Equal(w.start) {
+		if start.Sub(w.start) == size {
+			w.prev = w.curr
+		} else {
+			w.prev = 0
+		}
+		w.curr = 0
+		w.start = start
+	}
+	overlap := 1 - float64(now.Sub(start))/float64(size)
+	return float64(w.prev)*overlap + float64(w.curr)
+}
+
+// DBRateLimiter keeps fixed-window counters in token_rate_limits, so all
+// gateway instances share the limits. Each check is one atomic statement.
+type DBRateLimiter struct {
+	repo *repository.TokenRepository
+}
+
+// NewDBRateLimiter creates a new DBRateLimiter instance.
+func NewDBRateLimiter(repo *repository.TokenRepository) *DBRateLimiter {
+	return &DBRateLimiter{repo: repo}
+}
+
+// Allow implements RateLimiter. Minute and hour windows start on the minute
+// and hour; day windows start at local midnight.
+func (l *DBRateLimiter) Allow(ctx context.Context, tokenID int, limits map[string]int) (string, error) {
+	now := time.Now()
+
+	var windows []repository.RateLimitWindow
+	for _, rw := range rateWindows {
+		limit := limits[rw.name]
+		if limit <= 0 {
+			continue
+		}
+		start := now.Truncate(rw.size)
+		if rw.name == "day" {
+			start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		}
+		windows = append(windows, repository.RateLimitWindow{
+			Type:  rw.name,
+			Start: start,
+			End:   start.Add(rw.size),
+			Limit: limit,
+		})
+	}
+	return l.repo.TakeRateLimit(ctx, tokenID, windows)
+}
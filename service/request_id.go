@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// maxRequestIDLength matches token_usage_logs.request_id and audit_logs.request_id
+const maxRequestIDLength = 100
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID, which is then
+// written to the log entries, usage log rows and audit rows of the request.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored by WithRequestID, or ""
+// when ctx has none.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns the client-supplied ID when it is usable (1-100
+// printable ASCII characters, no spaces) and a fresh UUID otherwise.
+func NewRequestID(supplied string) string {
+	if validRequestID(supplied) {
+		return supplied
+	}
+	return uuid.New().String()
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,417 @@
+package service
+
+import (
+	"api-gateway/models"
+	"api-gateway/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SLAService manages SLA policies and calendars and computes the SLA fields
+// (sla_due_at, sla_remaining_minutes, sla_breached) on data rows.
+type SLAService struct {
+	repo      *repository.SLARepository
+	tokenRepo *repository.TokenRepository // audit logging
+	logger    *logrus.Logger
+
+	// Policy/calendar caching — evaluated on every data request
+	cache     *slaSnapshot
+	cacheMux  sync.RWMutex
+	lastFetch time.Time
+	cacheTTL  time.Duration
+}
+
+// slaSnapshot is an immutable view of the active policies and compiled calendars.
+type slaSnapshot struct {
+	policies  []*models.SLAPolicy
+	calendars map[int]*models.SLACalendar
+}
+
+// NewSLAService creates a new SLAService instance.
+func NewSLAService(repo *repository.SLARepository, tokenRepo *repository.TokenRepository, logger *logrus.Logger) *SLAService {
+	return &SLAService{
+		repo:      repo,
+		tokenRepo: tokenRepo,
+		logger:    logger,
+		cacheTTL:  1 * time.Minute,
+	}
+}
+
+// ============================================================================
+// SLA Evaluation
+// ============================================================================
+
+// Apply computes the SLA fields on each row. Rows without a matching policy or
+// without a parseable start time are left with null SLA fields. Policy load
+// failures are logged rather than failing the data request.
+func (s *SLAService) Apply(ctx context.Context, rows []*models.DataRow) {
+	snap, err := s.snapshot(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Errorf("Failed to load SLA policies: %v", err)
+		return
+	}
+	if len(snap.policies) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for _, row := range rows {
+		s.evaluate(row, snap, now)
+	}
+}
+
+// evaluate fills the SLA fields of a single row.
+func (s *SLAService) evaluate(row *models.DataRow, snap *slaSnapshot, now time.Time) {
+	policy := matchPolicy(snap.policies, row.Priority.String, row.FLMName.String)
+	if policy == nil {
+		return
+	}
+
+	var cal *models.SLACalendar
+	if policy.CalendarID != nil {
+		cal = snap.calendars[*policy.CalendarID]
+	}
+
+	start := row.IncidentStartTime
+	if !start.Valid {
+		if start = row.OpenTime; !start.Valid {
+			return
+		}
+	}
+
+	addMinutes := func(minutes int) time.Time {
+		if cal == nil {
+			return start.Time.Add(time.Duration(minutes) * time.Minute)
+		}
+		return cal.AddBusinessMinutes(start.Time, minutes)
+	}
+
+	// Closed tickets are measured at close time, open tickets at "now"
+	end := now
+	if row.CloseTime.Valid {
+		end = row.CloseTime.Time
+	}
+
+	due := addMinutes(policy.ResolutionMinutes)
+	remaining := int(due.Sub(end).Minutes())
+	breached := end.After(due)
+
+	row.SLAPolicy = &policy.Name
+	row.SLADueAt = &due
+	row.SLARemainingMinutes = &remaining
+	row.SLABreached = &breached
+
+	if policy.ResponseMinutes != nil {
+		responseDue := addMinutes(*policy.ResponseMinutes)
+		responseBreached := row.Status.String == models.StatusNew && now.After(responseDue)
+		row.SLAResponseDueAt = &responseDue
+		row.SLAResponseBreached = &responseBreached
+	}
+}
+
+// matchPolicy picks the policy for a priority/vendor pair. A vendor-specific
+// policy wins over the priority's default (vendor-less) policy.
+func matchPolicy(policies []*models.SLAPolicy, priority, vendor string) *models.SLAPolicy {
+	var fallback *models.SLAPolicy
+	for _, p := range policies {
+		if p.Priority != priority {
+			continue
+		}
+		if p.VendorName == nil {
+			fallback = p
+			continue
+		}
+		if vendor != "" && strings.EqualFold(*p.VendorName, vendor) {
+			return p
+		}
+	}
+	return fallback
+}
+
+// snapshot returns the cached active policies and compiled calendars,
+// reloading them once the cache TTL has passed.
+func (s *SLAService) snapshot(ctx context.Context) (*slaSnapshot, error) {
+	s.cacheMux.RLock()
+	if s.cache != nil && time.Since(s.lastFetch) < s.cacheTTL {
+		cached := s.cache
+		s.cacheMux.RUnlock()
+		return cached, nil
+	}
+	s.cacheMux.RUnlock()
+
+	policies, err := s.repo.GetAllPolicies(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	calendars, err := s.repo.GetAllCalendars(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &slaSnapshot{
+		policies:  policies,
+		calendars: make(map[int]*models.SLACalendar, len(calendars)),
+	}
+	for _, cal := range calendars {
+		if err := cal.Compile(); err != nil {
+			s.logger.Warnf("Skipping invalid SLA calendar %d (%s): %v", cal.ID, cal.Name, err)
+			continue
+		}
+		snap.calendars[cal.ID] = cal
+	}
+
+	s.cacheMux.Lock()
+	s.cache = snap
+	s.lastFetch = time.Now()
+	s.cacheMux.Unlock()
+
+	return snap, nil
+}
+
+// invalidate drops the cached snapshot after an admin change.
+func (s *SLAService) invalidate() {
+	s.cacheMux.Lock()
+	s.cache = nil
+	s.cacheMux.Unlock()
+}
+
+// ============================================================================
+// Calendar Management
+// ============================================================================
+
+// GetAllCalendars retrieves all SLA calendars
+func (s *SLAService) GetAllCalendars(ctx context.Context) ([]*models.SLACalendar, error) {
+	return s.repo.GetAllCalendars(ctx)
+}
+
+// GetCalendarByID retrieves an SLA calendar by ID
+func (s *SLAService) GetCalendarByID(ctx context.Context, id int) (*models.SLACalendar, error) {
+	return s.repo.GetCalendarByID(ctx, id)
+}
+
+// CreateCalendar validates and creates a new SLA calendar
+func (s *SLAService) CreateCalendar(ctx context.Context, req *models.SLACalendarRequest, createdBy int) (*models.SLACalendar, error) {
+	cal, err := calendarFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.repo.CreateCalendar(ctx, cal, createdBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SLA calendar: %v", err)
+	}
+	s.invalidate()
+
+	newJSON, _ := json.Marshal(req)
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &createdBy, Action: "create_sla_calendar",
+		ResourceType: "sla_calendar", ResourceID: &id,
+		NewValues:   string(newJSON),
+		Description: fmt.Sprintf("Created SLA calendar: %s", cal.Name),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+
+	return s.repo.GetCalendarByID(ctx, id)
+}
+
+// UpdateCalendar validates and replaces an existing SLA calendar
+func (s *SLAService) UpdateCalendar(ctx context.Context, id int, req *models.SLACalendarRequest, updatedBy int) (*models.SLACalendar, error) {
+	oldCal, err := s.repo.GetCalendarByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	cal, err := calendarFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	cal.ID = id
+
+	if err := s.repo.UpdateCalendar(ctx, cal); err != nil {
+		return nil, fmt.Errorf("failed to update SLA calendar: %v", err)
+	}
+	s.invalidate()
+
+	oldJSON, _ := json.Marshal(oldCal)
+	newJSON, _ := json.Marshal(req)
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &updatedBy, Action: "update_sla_calendar",
+		ResourceType: "sla_calendar", ResourceID: &id,
+		OldValues: string(oldJSON), NewValues: string(newJSON),
+		Description: fmt.Sprintf("Updated SLA calendar: %s", oldCal.Name),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+
+	return s.repo.GetCalendarByID(ctx, id)
+}
+
+// DeleteCalendar deletes an SLA calendar
+func (s *SLAService) DeleteCalendar(ctx context.Context, id int, deletedBy int) error {
+	cal, err := s.repo.GetCalendarByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteCalendar(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &deletedBy, Action: "delete_sla_calendar",
+		ResourceType: "sla_calendar", ResourceID: &id,
+		Description: fmt.Sprintf("Deleted SLA calendar: %s", cal.Name),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+	return nil
+}
+
+// calendarFromRequest builds and validates an SLACalendar from an API request.
+func calendarFromRequest(req *models.SLACalendarRequest) (*models.SLACalendar, error) {
+	cal := &models.SLACalendar{
+		Name:      req.Name,
+		Timezone:  req.Timezone,
+		WorkDays:  models.FormatWorkDays(req.WorkDays),
+		WorkStart: req.WorkStart,
+		WorkEnd:   req.WorkEnd,
+		Holidays:  req.Holidays,
+	}
+	if err := cal.Compile(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return cal, nil
+}
+
+// ============================================================================
+// Policy Management
+// ============================================================================
+
+// GetAllPolicies retrieves all SLA policies
+func (s *SLAService) GetAllPolicies(ctx context.Context) ([]*models.SLAPolicy, error) {
+	return s.repo.GetAllPolicies(ctx, false)
+}
+
+// GetPolicyByID retrieves an SLA policy by ID
+func (s *SLAService) GetPolicyByID(ctx context.Context, id int) (*models.SLAPolicy, error) {
+	return s.repo.GetPolicyByID(ctx, id)
+}
+
+// CreatePolicy validates and creates a new SLA policy
+func (s *SLAService) CreatePolicy(ctx context.Context, req *models.SLAPolicyRequest, createdBy int) (*models.SLAPolicy, error) {
+	policy, err := s.policyFromRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.repo.CreatePolicy(ctx, policy, createdBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SLA policy: %v", err)
+	}
+	s.invalidate()
+
+	newJSON, _ := json.Marshal(req)
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &createdBy, Action: "create_sla_policy",
+		ResourceType: "sla_policy", ResourceID: &id,
+		NewValues:   string(newJSON),
+		Description: fmt.Sprintf("Created SLA policy: %s", policy.Name),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+
+	return s.repo.GetPolicyByID(ctx, id)
+}
+
+// UpdatePolicy validates and replaces an existing SLA policy
+func (s *SLAService) UpdatePolicy(ctx context.Context, id int, req *models.SLAPolicyRequest, updatedBy int) (*models.SLAPolicy, error) {
+	oldPolicy, err := s.repo.GetPolicyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := s.policyFromRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	policy.ID = id
+
+	if err := s.repo.UpdatePolicy(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to update SLA policy: %v", err)
+	}
+	s.invalidate()
+
+	oldJSON, _ := json.Marshal(oldPolicy)
+	newJSON, _ := json.Marshal(req)
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &updatedBy, Action: "update_sla_policy",
+		ResourceType: "sla_policy", ResourceID: &id,
+		OldValues: string(oldJSON), NewValues: string(newJSON),
+		Description: fmt.Sprintf("Updated SLA policy: %s", oldPolicy.Name),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+
+	return s.repo.GetPolicyByID(ctx, id)
+}
+
+// DeletePolicy deletes an SLA policy
+func (s *SLAService) DeletePolicy(ctx context.Context, id int, deletedBy int) error {
+	policy, err := s.repo.GetPolicyByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeletePolicy(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &deletedBy, Action: "delete_sla_policy",
+		ResourceType: "sla_policy", ResourceID: &id,
+		Description: fmt.Sprintf("Deleted SLA policy: %s", policy.Name),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+	return nil
+}
+
+// policyFromRequest builds and validates an SLAPolicy from an API request.
+// Policies are active unless stated otherwise.
+func (s *SLAService) policyFromRequest(ctx context.Context, req *models.SLAPolicyRequest) (*models.SLAPolicy, error) {
+	if req.CalendarID != nil {
+		_, err := s.repo.GetCalendarByID(ctx, *req.CalendarID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%w: calendar %d does not exist", ErrInvalidInput, *req.CalendarID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.ResponseMinutes != nil && *req.ResponseMinutes > req.ResolutionMinutes {
+		return nil, fmt.Errorf("%w: response_minutes cannot exceed resolution_minutes", ErrInvalidInput)
+	}
+
+	var vendorName *string
+	if req.VendorName != nil && strings.TrimSpace(*req.VendorName) != "" {
+		v := strings.TrimSpace(*req.VendorName)
+		vendorName = &v
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	return &models.SLAPolicy{
+		Name:              req.Name,
+		Priority:          req.Priority,
+		VendorName:        vendorName,
+		ResponseMinutes:   req.ResponseMinutes,
+		ResolutionMinutes: req.ResolutionMinutes,
+		CalendarID:        req.CalendarID,
+		IsActive:          isActive,
+	}, nil
+}
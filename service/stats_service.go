@@ -0,0 +1,197 @@
+package service
+
+import (
+	"api-gateway/models"
+	"api-gateway/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StatsService handles business logic for the /api/v1/stats endpoints and the
+// admin-managed criticality rules that drive them.
+type StatsService struct {
+	dataRepo  *repository.DataRepository
+	ruleRepo  *repository.CriticalityRepository
+	tokenRepo *repository.TokenRepository // audit logging
+	logger    *logrus.Logger
+}
+
+// NewStatsService creates a new StatsService instance.
+func NewStatsService(
+	dataRepo *repository.DataRepository,
+	ruleRepo *repository.CriticalityRepository,
+	tokenRepo *repository.TokenRepository,
+	logger *logrus.Logger,
+) *StatsService {
+	return &StatsService{
+		dataRepo:  dataRepo,
+		ruleRepo:  ruleRepo,
+		tokenRepo: tokenRepo,
+		logger:    logger,
+	}
+}
+
+// ============================================================================
+// Critical Terminals
+// ============================================================================
+
+// GetCriticalTerminals returns every open ticket in the vendor scope that matches
+// at least one active criticality rule, longest-running first.
+func (s *StatsService) GetCriticalTerminals(ctx context.Context, filter *repository.VendorFilter) ([]models.CriticalTerminal, error) {
+	rules, err := s.ruleRepo.GetAllRules(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load criticality rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return []models.CriticalTerminal{}, nil
+	}
+
+	rows, err := s.dataRepo.GetAllWithLocation(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.CriticalTerminal, 0)
+	for _, row := range rows {
+		var matched []string
+		for _, rule := range rules {
+			if rule.Matches(row) {
+				matched = append(matched, rule.Name)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		result = append(result, models.CriticalTerminal{
+			TerminalID:   row.TerminalID,
+			TerminalName: row.TerminalName,
+			Location:     row.Location(),
+			Status:       row.Mode.String,
+			TicketStatus: row.Status.String,
+			Priority:     row.Priority.String,
+			Duration:     row.TicketsDuration,
+			Problem:      row.CurrentProblem.String,
+			FLM:          row.FLM.String,
+			SLM:          row.SLM.String,
+			GPS:          row.GPS,
+			MatchedRules: matched,
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Duration > result[j].Duration
+	})
+
+	s.logger.WithContext(ctx).Infof("Found %d critical terminals across %d open tickets", len(result), len(rows))
+	return result, nil
+}
+
+// ============================================================================
+// Criticality Rule Management
+// ============================================================================
+
+// GetAllRules retrieves all criticality rules
+func (s *StatsService) GetAllRules(ctx context.Context) ([]*models.CriticalityRule, error) {
+	return s.ruleRepo.GetAllRules(ctx, false)
+}
+
+// GetRuleByID retrieves a criticality rule by ID
+func (s *StatsService) GetRuleByID(ctx context.Context, id int) (*models.CriticalityRule, error) {
+	return s.ruleRepo.GetRuleByID(ctx, id)
+}
+
+// CreateRule creates a new criticality rule
+func (s *StatsService) CreateRule(ctx context.Context, req *models.CriticalityRuleRequest, createdBy int) (*models.CriticalityRule, error) {
+	if !req.HasConditions() {
+		return nil, ErrInvalidInput
+	}
+
+	rule := ruleFromRequest(req)
+	id, err := s.ruleRepo.CreateRule(ctx, rule, createdBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create criticality rule: %w", err)
+	}
+
+	newJSON, _ := json.Marshal(req)
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &createdBy, Action: "create_criticality_rule",
+		ResourceType: "criticality_rule", ResourceID: &id,
+		NewValues:   string(newJSON),
+		Description: fmt.Sprintf("Created criticality rule: %s", rule.Name),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+
+	s.logger.WithContext(ctx).Infof("Created criticality rule: %s (ID: %d)", rule.Name, id)
+	return s.ruleRepo.GetRuleByID(ctx, id)
+}
+
+// UpdateRule replaces an existing criticality rule
+func (s *StatsService) UpdateRule(ctx context.Context, id int, req *models.CriticalityRuleRequest, updatedBy int) (*models.CriticalityRule, error) {
+	if !req.HasConditions() {
+		return nil, ErrInvalidInput
+	}
+
+	oldRule, err := s.ruleRepo.GetRuleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := ruleFromRequest(req)
+	rule.ID = id
+	if err := s.ruleRepo.UpdateRule(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to update criticality rule: %w", err)
+	}
+
+	oldJSON, _ := json.Marshal(oldRule)
+	newJSON, _ := json.Marshal(req)
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &updatedBy, Action: "update_criticality_rule",
+		ResourceType: "criticality_rule", ResourceID: &id,
+		OldValues: string(oldJSON), NewValues: string(newJSON),
+		Description: fmt.Sprintf("Updated criticality rule: %s", oldRule.Name),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+
+	return s.ruleRepo.GetRuleByID(ctx, id)
+}
+
+// DeleteRule deletes a criticality rule permanently
+func (s *StatsService) DeleteRule(ctx context.Context, id int, deletedBy int) error {
+	rule, err := s.ruleRepo.GetRuleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.ruleRepo.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &deletedBy, Action: "delete_criticality_rule",
+		ResourceType: "criticality_rule", ResourceID: &id,
+		Description: fmt.Sprintf("Deleted criticality rule: %s", rule.Name),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+	return nil
+}
+
+// ruleFromRequest builds a CriticalityRule from an API request; rules are active unless stated otherwise.
+func ruleFromRequest(req *models.CriticalityRuleRequest) *models.CriticalityRule {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	return &models.CriticalityRule{
+		Name:               req.Name,
+		Description:        req.Description,
+		Priority:           req.Priority,
+		Mode:               req.Mode,
+		Status:             req.Status,
+		MinTicketsDuration: req.MinTicketsDuration,
+		MaxBalance:         req.MaxBalance,
+		Region:             req.Region,
+		IsActive:           isActive,
+	}
+}
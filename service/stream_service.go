@@ -0,0 +1,268 @@
+package service
+
+import (
+	"api-gateway/models"
+	"api-gateway/repository"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// streamBufferSize is how many undelivered events a subscriber may fall behind
+// before it is dropped. Dropped clients reconnect and resume via Last-Event-ID.
+const streamBufferSize = 256
+
+// StreamService detects ticket changes with one shared rowversion watermark poll
+// and fans them out to SSE subscribers, so database load does not grow with the
+// number of connected dashboards.
+type StreamService struct {
+	repo     *repository.DataRepository
+	sla      *SLAService     // optional; nil leaves SLA fields null
+	webhooks *WebhookService // optional; receives ticket.created events
+	logger   *logrus.Logger
+	interval time.Duration
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	known       map[string]*models.DataRow // last seen row per terminal
+	watermark   int64
+	ready       bool // baseline loaded
+	closed      bool // CloseSubscriptions was called
+
+	// Recent events kept for Last-Event-ID resume
+	seq         uint64
+	history     []*models.ChangeEvent
+	historySize int
+}
+
+// Subscription is one connected stream client.
+// Events is closed when the subscriber is dropped for falling behind or the
+// service closes its subscriptions on shutdown.
+type Subscription struct {
+	Events chan *models.ChangeEvent
+	filter *repository.VendorFilter
+}
+
+// NewStreamService creates a new StreamService instance. slaService and
+// webhookService may be nil.
+func NewStreamService(repo *repository.DataRepository, slaService *SLAService, webhookService *WebhookService, interval time.Duration, historySize int, logger *logrus.Logger) *StreamService {
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	if historySize <= 0 {
+		historySize = 1000
+	}
+	return &StreamService{
+		repo:        repo,
+		sla:         slaService,
+		webhooks:    webhookService,
+		logger:      logger,
+		interval:    interval,
+		subscribers: make(map[*Subscription]struct{}),
+		known:       make(map[string]*models.DataRow),
+		historySize: historySize,
+	}
+}
+
+// Run polls for changes until ctx is cancelled.
+func (s *StreamService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.logger.Infof("Data change stream started (poll interval %s)", s.interval)
+	for {
+		s.poll(ctx)
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Data change stream stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll runs one change-detection cycle. The first successful cycle only records
+// the baseline; every later cycle publishes created/updated/closed events.
+func (s *StreamService) poll(ctx context.Context) {
+	rows, watermark, err := s.repo.GetChangedSince(ctx, s.watermark)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.Errorf("Stream poll failed: %v", err)
+		}
+		return
+	}
+	ids, err := s.repo.GetOpenTerminalIDs(ctx)
+	if err != nil {
+		s.logger.Errorf("Stream poll failed: %v", err)
+		return
+	}
+
+	created := s.detect(ctx, rows, watermark, ids)
+
+	// New tickets only surface here (they are inserted by other systems), so this
+	// is where ticket.created webhooks are queued. The diff is in memory, so
+	// tickets created while the gateway was down raise none (documented as
+	// best-effort under /webhooks)
+	if s.webhooks != nil && len(created) > 0 {
+		if err := s.webhooks.Enqueue(models.WebhookTicketCreated, created...); err != nil {
+			s.logger.Errorf("Failed to queue ticket.created webhooks: %v", err)
+		}
+	}
+}
+
+// detect diffs one poll result against the known rows, publishes the resulting
+// events and returns the rows of newly created tickets.
+func (s *StreamService) detect(ctx context.Context, rows []*models.DataRow, watermark int64, ids map[string]struct{}) []*models.DataRow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.watermark = watermark
+	if !s.ready {
+		for _, row := range rows {
+			s.known[row.TerminalID] = row
+		}
+		s.ready = true
+		s.logger.Infof("Data change stream baseline loaded (%d tickets)", len(s.known))
+		return nil
+	}
+
+	now := time.Now()
+	events := make([]*models.ChangeEvent, 0, len(rows))
+	for _, row := range rows {
+		prev := s.known[row.TerminalID]
+		eventType := models.ChangeUpdated
+		switch {
+		case prev == nil:
+			eventType = models.ChangeCreated
+		case row.CloseTime.Valid && !prev.CloseTime.Valid:
+			eventType = models.ChangeClosed
+		}
+		s.known[row.TerminalID] = row
+		events = append(events, &models.ChangeEvent{
+			Type: eventType, TerminalID: row.TerminalID, OccurredAt: now, Data: row,
+		})
+	}
+
+	// Tickets removed from open_ticket are reported as closed with their last
+	// known row. That row was already published, and subscribers may still be
+	// encoding it, so the event gets a copy for SLA evaluation to fill in.
+	for id, row := range s.known {
+		if _, ok := ids[id]; ok {
+			continue
+		}
+		delete(s.known, id)
+		last := *row
+		events = append(events, &models.ChangeEvent{
+			Type: models.ChangeClosed, TerminalID: id, OccurredAt: now, Data: &last,
+		})
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+	if s.sla != nil {
+		changed := make([]*models.DataRow, len(events))
+		for i, ev := range events {
+			changed[i] = ev.Data
+		}
+		s.sla.Apply(ctx, changed)
+	}
+	var created []*models.DataRow
+	for _, ev := range events {
+		s.publish(ev)
+		if ev.Type == models.ChangeCreated {
+			created = append(created, ev.Data)
+		}
+	}
+	return created
+}
+
+// publish assigns the next event ID, records the event for resume and delivers it
+// to every subscriber whose vendor filter covers the row. Callers hold s.mu.
+func (s *StreamService) publish(ev *models.ChangeEvent) {
+	s.seq++
+	ev.ID = s.seq
+
+	s.history = append(s.history, ev)
+	if len(s.history) > s.historySize {
+		s.history = s.history[len(s.history)-s.historySize:]
+	}
+
+	for sub := range s.subscribers {
+		if !sub.filter.Matches(ev.Data) {
+			continue
+		}
+		select {
+		case sub.Events <- ev:
+		default:
+			s.logger.Warn("Dropping slow stream subscriber")
+			delete(s.subscribers, sub)
+			close(sub.Events)
+		}
+	}
+}
+
+// Subscribe registers a subscriber scoped to filter. When lastEventID is set, the
+// events after it that are still held are returned as a backlog; reset is true
+// when they are no longer held (or the ID is unknown) and the client should
+// refetch GET /api/v1/data instead.
+func (s *StreamService) Subscribe(filter *repository.VendorFilter, lastEventID string) (sub *Subscription, backlog []*models.ChangeEvent, reset bool) {
+	sub = &Subscription{
+		Events: make(chan *models.ChangeEvent, streamBufferSize),
+		filter: filter,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		close(sub.Events)
+		return sub, nil, false
+	}
+	s.subscribers[sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, false
+	}
+	lastID, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil || lastID > s.seq {
+		return sub, nil, true
+	}
+	if lastID == s.seq {
+		return sub, nil, false
+	}
+	if len(s.history) == 0 || lastID+1 < s.history[0].ID {
+		return sub, nil, true
+	}
+	for _, ev := range s.history {
+		if ev.ID > lastID && filter.Matches(ev.Data) {
+			backlog = append(backlog, ev)
+		}
+	}
+	return sub, backlog, false
+}
+
+// Unsubscribe removes a subscriber. Safe to call after it was dropped.
+func (s *StreamService) Unsubscribe(sub *Subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subscribers[sub]; ok {
+		delete(s.subscribers, sub)
+		close(sub.Events)
+	}
+}
+
+// CloseSubscriptions ends every subscription, and any made afterwards, so that
+// stream handlers return and their connections can drain on shutdown.
+// Clients reconnect and resume via Last-Event-ID.
+func (s *StreamService) CloseSubscriptions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for sub := range s.subscribers {
+		delete(s.subscribers, sub)
+		close(sub.Events)
+	}
+}
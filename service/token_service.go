@@ -1,42 +1,69 @@
 package service
 
 import (
+	"api-gateway/metrics"
 	"api-gateway/models"
 	"api-gateway/repository"
+	"api-gateway/tracing"
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
 	"database/sql"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // TokenService handles business logic for token management
 type TokenService struct {
-	repo   *repository.TokenRepository
-	logger *logrus.Logger
+	repo      *repository.TokenRepository
+	usageLogs *UsageLogService
+	limiter   RateLimiter
+	logger    *logrus.Logger
+
+	// Limits of new tokens that do not set their own; replaced on config reload
+	limitsMu      sync.RWMutex
+	defaultLimits [3]int // per minute, hour, day
 }
 
 // NewTokenService creates a new token service instance
-func NewTokenService(repo *repository.TokenRepository, logger *logrus.Logger) *TokenService {
+func NewTokenService(repo *repository.TokenRepository, usageLogs *UsageLogService, limiter RateLimiter, logger *logrus.Logger) *TokenService {
 	return &TokenService{
-		repo:   repo,
-		logger: logger,
+		repo:          repo,
+		usageLogs:     usageLogs,
+		limiter:       limiter,
+		logger:        logger,
+		defaultLimits: [3]int{100, 5000, 100000},
 	}
 }
 
+// SetDefaultRateLimits changes the limits given to tokens created without
+// their own. Tokens that already exist keep theirs.
+func (s *TokenService) SetDefaultRateLimits(perMinute, perHour, perDay int) {
+	s.limitsMu.Lock()
+	s.defaultLimits = [3]int{perMinute, perHour, perDay}
+	s.limitsMu.Unlock()
+}
+
 // ============================================================================
 // Admin Authentication
 // ============================================================================
 
 // Login authenticates an admin user and creates a session
-func (s *TokenService) Login(username, password, ipAddress, userAgent string) (*models.LoginResponse, error) {
-	admin, err := s.repo.GetAdminByUsername(username)
+func (s *TokenService) Login(ctx context.Context, username, password, ipAddress, userAgent string) (*models.LoginResponse, error) {
+	admin, err := s.repo.GetAdminByUsername(ctx, username)
 	if err != nil {
-		s.logger.Warnf("Login attempt failed for username: %s", username)
+		s.logger.WithContext(ctx).Warnf("Login attempt failed for username: %s", username)
 		return &models.LoginResponse{
 			Success: false,
 			Message: "Invalid username or password",
@@ -45,7 +72,7 @@ func (s *TokenService) Login(username, password, ipAddress, userAgent string) (*
 
 	err = bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte(password))
 	if err != nil {
-		s.logger.Warnf("Invalid password for username: %s", username)
+		s.logger.WithContext(ctx).Warnf("Invalid password for username: %s", username)
 		return &models.LoginResponse{
 			Success: false,
 			Message: "Invalid username or password",
@@ -66,14 +93,14 @@ func (s *TokenService) Login(username, password, ipAddress, userAgent string) (*
 		ExpiresAt:    expiresAt,
 	}
 
-	err = s.repo.CreateSession(session)
+	err = s.repo.CreateSession(ctx, session)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session: %v", err)
 	}
 
-	_ = s.repo.UpdateAdminLastLogin(admin.ID, ipAddress)
+	_ = s.repo.UpdateAdminLastLogin(ctx, admin.ID, ipAddress)
 
-	s.logger.Infof("Admin user '%s' logged in successfully", username)
+	s.logger.WithContext(ctx).Infof("Admin user '%s' logged in successfully", username)
 
 	return &models.LoginResponse{
 		Success:      true,
@@ -85,20 +112,20 @@ func (s *TokenService) Login(username, password, ipAddress, userAgent string) (*
 }
 
 // Logout deletes a session
-func (s *TokenService) Logout(sessionToken string) error {
-	return s.repo.DeleteSession(sessionToken)
+func (s *TokenService) Logout(ctx context.Context, sessionToken string) error {
+	return s.repo.DeleteSession(ctx, sessionToken)
 }
 
 // ValidateSession validates a session token and returns the admin user
-func (s *TokenService) ValidateSession(sessionToken string) (*models.AdminUser, error) {
-	session, err := s.repo.GetSessionByToken(sessionToken)
+func (s *TokenService) ValidateSession(ctx context.Context, sessionToken string) (*models.AdminUser, error) {
+	session, err := s.repo.GetSessionByToken(ctx, sessionToken)
 	if err != nil {
 		return nil, fmt.Errorf("invalid or expired session")
 	}
 
-	_ = s.repo.UpdateSessionAccess(session.ID)
+	_ = s.repo.UpdateSessionAccess(ctx, session.ID)
 
-	admin, err := s.repo.GetAdminByID(session.AdminUserID)
+	admin, err := s.repo.GetAdminByID(ctx, session.AdminUserID)
 	if err != nil {
 		return nil, fmt.Errorf("admin user not found")
 	}
@@ -111,7 +138,7 @@ func (s *TokenService) ValidateSession(sessionToken string) (*models.AdminUser,
 // ============================================================================
 
 // CreateAPIToken creates a new API token
-func (s *TokenService) CreateAPIToken(req *models.CreateTokenRequest, createdBy int) (*models.APIToken, error) {
+func (s *TokenService) CreateAPIToken(ctx context.Context, req *models.CreateTokenRequest, createdBy int) (*models.APIToken, error) {
 	tokenValue, err := s.generateAPIToken(req.Environment)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %v", err)
@@ -119,18 +146,26 @@ func (s *TokenService) CreateAPIToken(req *models.CreateTokenRequest, createdBy
 
 	prefix := s.extractTokenPrefix(tokenValue)
 
+	fingerprint, err := normalizeFingerprint(req.ClientCertFingerprint)
+	if err != nil {
+		return nil, err
+	}
+
 	scopesJSON, _ := repository.ConvertToJSON(req.Scopes)
 	ipWhitelistJSON, _ := repository.ConvertToJSON(req.IPWhitelist)
 	allowedOriginsJSON, _ := repository.ConvertToJSON(req.AllowedOrigins)
 
+	s.limitsMu.RLock()
+	defaults := s.defaultLimits
+	s.limitsMu.RUnlock()
 	if req.RateLimitPerMinute == 0 {
-		req.RateLimitPerMinute = 100
+		req.RateLimitPerMinute = defaults[0]
 	}
 	if req.RateLimitPerHour == 0 {
-		req.RateLimitPerHour = 5000
+		req.RateLimitPerHour = defaults[1]
 	}
 	if req.RateLimitPerDay == 0 {
-		req.RateLimitPerDay = 100000
+		req.RateLimitPerDay = defaults[2]
 	}
 
 	token := &models.APIToken{
@@ -150,13 +185,16 @@ func (s *TokenService) CreateAPIToken(req *models.CreateTokenRequest, createdBy
 		FilterColumn:       req.FilterColumn,
 		FilterValue:        req.FilterValue,
 		IsSuperToken:       req.IsSuperToken,
+
+		ClientCertFingerprint: fingerprint,
+		ClientCertSubject:     strings.TrimSpace(req.ClientCertSubject),
 	}
 
 	if req.ExpiresAt != nil {
 		token.ExpiresAt = models.NullTime{NullTime: sql.NullTime{Valid: true, Time: *req.ExpiresAt}}
 	}
 
-	id, err := s.repo.CreateAPIToken(token, createdBy)
+	id, err := s.repo.CreateAPIToken(ctx, token, createdBy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create token: %v", err)
 	}
@@ -167,20 +205,21 @@ func (s *TokenService) CreateAPIToken(req *models.CreateTokenRequest, createdBy
 		"vendor_name": req.VendorName, "filter_column": req.FilterColumn,
 		"filter_value": req.FilterValue, "is_super_token": req.IsSuperToken,
 	})
-	_ = s.repo.CreateAuditLog(&models.AuditLog{
+	_ = s.repo.CreateAuditLog(ctx, &models.AuditLog{
 		AdminUserID: &createdBy, Action: "create_token",
 		ResourceType: "token", ResourceID: &id,
 		NewValues:   string(newValuesJSON),
 		Description: fmt.Sprintf("Created API token: %s", token.Name),
+		RequestID:   RequestIDFromContext(ctx),
 	})
 
-	s.logger.Infof("Created new API token: %s (ID: %d, vendor: %s, super: %v)", token.Name, id, token.VendorName, token.IsSuperToken)
+	s.logger.WithContext(ctx).Infof("Created new API token: %s (ID: %d, vendor: %s, super: %v)", token.Name, id, token.VendorName, token.IsSuperToken)
 	return token, nil
 }
 
 // GetAllTokens retrieves all API tokens (with masked token values)
-func (s *TokenService) GetAllTokens() ([]*models.APIToken, error) {
-	tokens, err := s.repo.GetAllAPITokens()
+func (s *TokenService) GetAllTokens(ctx context.Context) ([]*models.APIToken, error) {
+	tokens, err := s.repo.GetAllAPITokens(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -191,8 +230,8 @@ func (s *TokenService) GetAllTokens() ([]*models.APIToken, error) {
 }
 
 // GetTokenByID retrieves a token by ID (with masked token value)
-func (s *TokenService) GetTokenByID(id int) (*models.APIToken, error) {
-	token, err := s.repo.GetAPITokenByID(id)
+func (s *TokenService) GetTokenByID(ctx context.Context, id int) (*models.APIToken, error) {
+	token, err := s.repo.GetAPITokenByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -201,8 +240,8 @@ func (s *TokenService) GetTokenByID(id int) (*models.APIToken, error) {
 }
 
 // UpdateToken updates an existing API token
-func (s *TokenService) UpdateToken(id int, req *models.UpdateTokenRequest, updatedBy int) (*models.APIToken, error) {
-	oldToken, err := s.repo.GetAPITokenByID(id)
+func (s *TokenService) UpdateToken(ctx context.Context, id int, req *models.UpdateTokenRequest, updatedBy int) (*models.APIToken, error) {
+	oldToken, err := s.repo.GetAPITokenByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -252,70 +291,85 @@ func (s *TokenService) UpdateToken(id int, req *models.UpdateTokenRequest, updat
 	if req.IsSuperToken != nil {
 		updates["is_super_token"] = *req.IsSuperToken
 	}
+	// Client certificate binding; "" removes it
+	if req.ClientCertFingerprint != nil {
+		fingerprint, err := normalizeFingerprint(*req.ClientCertFingerprint)
+		if err != nil {
+			return nil, err
+		}
+		updates["client_cert_fingerprint"] = fingerprint
+	}
+	if req.ClientCertSubject != nil {
+		updates["client_cert_subject"] = strings.TrimSpace(*req.ClientCertSubject)
+	}
 
 	if len(updates) == 0 {
-		return s.GetTokenByID(id)
+		return s.GetTokenByID(ctx, id)
 	}
 
-	err = s.repo.UpdateAPIToken(id, updates)
+	err = s.repo.UpdateAPIToken(ctx, id, updates)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update token: %v", err)
 	}
 
 	oldJSON, _ := json.Marshal(map[string]string{"name": oldToken.Name})
 	newJSON, _ := json.Marshal(updates)
-	_ = s.repo.CreateAuditLog(&models.AuditLog{
+	_ = s.repo.CreateAuditLog(ctx, &models.AuditLog{
 		AdminUserID: &updatedBy, Action: "update_token",
 		ResourceType: "token", ResourceID: &id,
 		OldValues: string(oldJSON), NewValues: string(newJSON),
 		Description: fmt.Sprintf("Updated API token: %s", oldToken.Name),
+		RequestID:   RequestIDFromContext(ctx),
 	})
 
-	return s.GetTokenByID(id)
+	return s.GetTokenByID(ctx, id)
 }
 
 // DisableToken disables a token
-func (s *TokenService) DisableToken(id int, disabledBy int) error {
-	err := s.repo.DisableToken(id)
+func (s *TokenService) DisableToken(ctx context.Context, id int, disabledBy int) error {
+	err := s.repo.DisableToken(ctx, id)
 	if err != nil {
 		return err
 	}
-	_ = s.repo.CreateAuditLog(&models.AuditLog{
+	_ = s.repo.CreateAuditLog(ctx, &models.AuditLog{
 		AdminUserID: &disabledBy, Action: "disable_token",
 		ResourceType: "token", ResourceID: &id,
 		Description: fmt.Sprintf("Disabled API token ID: %d", id),
+		RequestID:   RequestIDFromContext(ctx),
 	})
 	return nil
 }
 
 // EnableToken enables a token
-func (s *TokenService) EnableToken(id int, enabledBy int) error {
-	err := s.repo.EnableToken(id)
+func (s *TokenService) EnableToken(ctx context.Context, id int, enabledBy int) error {
+	err := s.repo.EnableToken(ctx, id)
 	if err != nil {
 		return err
 	}
-	_ = s.repo.CreateAuditLog(&models.AuditLog{
+	_ = s.repo.CreateAuditLog(ctx, &models.AuditLog{
 		AdminUserID: &enabledBy, Action: "enable_token",
 		ResourceType: "token", ResourceID: &id,
 		Description: fmt.Sprintf("Enabled API token ID: %d", id),
+		RequestID:   RequestIDFromContext(ctx),
 	})
 	return nil
 }
 
 // DeleteToken deletes a token permanently
-func (s *TokenService) DeleteToken(id int, deletedBy int) error {
-	token, err := s.repo.GetAPITokenByID(id)
+func (s *TokenService) DeleteToken(ctx context.Context, id int, deletedBy int) error {
+	token, err := s.repo.GetAPITokenByID(ctx, id)
 	if err != nil {
 		return err
 	}
-	err = s.repo.DeleteToken(id)
+	err = s.repo.DeleteToken(ctx, id)
 	if err != nil {
 		return err
 	}
-	_ = s.repo.CreateAuditLog(&models.AuditLog{
+	_ = s.repo.CreateAuditLog(ctx, &models.AuditLog{
 		AdminUserID: &deletedBy, Action: "delete_token",
 		ResourceType: "token", ResourceID: &id,
 		Description: fmt.Sprintf("Deleted API token: %s", token.Name),
+		RequestID:   RequestIDFromContext(ctx),
 	})
 	return nil
 }
@@ -325,21 +379,28 @@ func (s *TokenService) DeleteToken(id int, deletedBy int) error {
 // ============================================================================
 
 // ValidateAPIToken validates a token and checks all security constraints.
-// Returns the token along with its resolved vendor filter context.
-func (s *TokenService) ValidateAPIToken(tokenValue, ipAddress string) (*models.APIToken, error) {
-	token, err := s.repo.GetAPITokenByToken(tokenValue)
+// clientCert is the verified TLS client certificate of the request, or nil;
+// it is checked against the token's certificate binding, if any.
+func (s *TokenService) ValidateAPIToken(ctx context.Context, tokenValue, ipAddress string, clientCert *x509.Certificate) (_ *models.APIToken, err error) {
+	ctx, span := tracing.Start(ctx, "TokenService.ValidateAPIToken")
+	defer func() { tracing.End(span, err) }()
+
+	token, err := s.repo.GetAPITokenByToken(ctx, tokenValue)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrInvalidToken
+	}
 	if err != nil {
-		return nil, fmt.Errorf("invalid token")
+		return nil, fmt.Errorf("failed to look up token: %w", err)
 	}
 
 	if !token.IsValid() {
 		if token.IsRevoked() {
-			return nil, fmt.Errorf("token has been revoked")
+			return nil, ErrTokenRevoked
 		}
 		if token.IsExpired() {
-			return nil, fmt.Errorf("token has expired")
+			return nil, ErrTokenExpired
 		}
-		return nil, fmt.Errorf("token is disabled")
+		return nil, ErrTokenDisabled
 	}
 
 	if token.IPWhitelist != "" && token.IPWhitelist != "[]" {
@@ -353,14 +414,47 @@ func (s *TokenService) ValidateAPIToken(tokenValue, ipAddress string) (*models.A
 				}
 			}
 			if !allowed {
-				return nil, fmt.Errorf("IP address not whitelisted")
+				return nil, ErrIPNotAllowed
 			}
 		}
 	}
 
+	if token.ClientCertFingerprint != "" || token.ClientCertSubject != "" {
+		if clientCert == nil {
+			return nil, ErrClientCertRequired
+		}
+		if token.ClientCertFingerprint != "" && CertificateFingerprint(clientCert) != token.ClientCertFingerprint {
+			return nil, ErrClientCertMismatch
+		}
+		if token.ClientCertSubject != "" && clientCert.Subject.String() != token.ClientCertSubject {
+			return nil, ErrClientCertMismatch
+		}
+	}
+
+	span.SetAttributes(attribute.Int("token.id", token.ID))
 	return token, nil
 }
 
+// CertificateFingerprint returns the SHA-256 of cert's DER encoding in
+// lowercase hex, the form stored in api_tokens.client_cert_fingerprint.
+func CertificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalizeFingerprint accepts a SHA-256 fingerprint with or without colon or
+// space separators, in either case, and returns it as lowercase hex.
+func normalizeFingerprint(fingerprint string) (string, error) {
+	f := strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(fingerprint))
+	if f == "" {
+		return "", nil
+	}
+	if b, err := hex.DecodeString(f); err != nil || len(b) != sha256.Size {
+		return "", fmt.Errorf("%w: client_cert_fingerprint must be a SHA-256 fingerprint (64 hex digits)", ErrInvalidInput)
+	}
+	return f, nil
+}
+
 // GetVendorFilter extracts the vendor filter context from a validated token.
 func (s *TokenService) GetVendorFilter(token *models.APIToken) *models.TokenVendorFilter {
 	return &models.TokenVendorFilter{
@@ -371,65 +465,37 @@ func (s *TokenService) GetVendorFilter(token *models.APIToken) *models.TokenVend
 	}
 }
 
-// CheckRateLimit checks if token has exceeded rate limits
-func (s *TokenService) CheckRateLimit(tokenID int, rateLimits map[string]int) (bool, string, error) {
-	now := time.Now()
-
-	checks := []struct {
-		windowType string
-		truncate   time.Duration
-		duration   time.Duration
-	}{
-		{"minute", time.Minute, time.Minute},
-		{"hour", time.Hour, time.Hour},
-	}
-
-	for _, check := range checks {
-		limit, ok := rateLimits[check.windowType]
-		if !ok || limit <= 0 {
-			continue
-		}
-
-		windowStart := now.Truncate(check.truncate)
-		windowEnd := windowStart.Add(check.duration)
-
-		count, err := s.repo.GetRateLimitCount(tokenID, check.windowType, windowStart)
-		if err != nil {
-			return false, "", err
-		}
-		if count >= limit {
-			return false, fmt.Sprintf("Rate limit exceeded (per %s)", check.windowType), nil
-		}
-
-		_ = s.repo.IncrementRateLimit(tokenID, check.windowType, windowStart, windowEnd)
+// CheckRateLimit checks if token has exceeded rate limits and, if not,
+// counts the request. rateLimits is keyed by window (minute, hour, day); a
+// limit of 0 is not enforced.
+func (s *TokenService) CheckRateLimit(ctx context.Context, tokenID int, rateLimits map[string]int) (allowed bool, message string, err error) {
+	ctx, span := tracing.Start(ctx, "TokenService.CheckRateLimit", attribute.Int("token.id", tokenID))
+	defer func() {
+		span.SetAttributes(attribute.Bool("rate_limit.allowed", allowed))
+		tracing.End(span, err)
+	}()
+
+	exceeded, err := s.limiter.Allow(ctx, tokenID, rateLimits)
+	if err != nil {
+		return false, "", err
 	}
-
-	// Day check
-	if limit, ok := rateLimits["day"]; ok && limit > 0 {
-		windowStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-		windowEnd := windowStart.Add(24 * time.Hour)
-
-		count, err := s.repo.GetRateLimitCount(tokenID, "day", windowStart)
-		if err != nil {
-			return false, "", err
-		}
-		if count >= limit {
-			return false, "Rate limit exceeded (per day)", nil
-		}
-		_ = s.repo.IncrementRateLimit(tokenID, "day", windowStart, windowEnd)
+	if exceeded != "" {
+		metrics.RateLimitRejections.WithLabelValues(exceeded).Inc()
+		return false, fmt.Sprintf("Rate limit exceeded (per %s)", exceeded), nil
 	}
-
 	return true, "", nil
 }
 
-// LogTokenUsage logs API token usage
+// LogTokenUsage records API token usage without holding up the request: the
+// row is queued for the batched usage log writer.
 func (s *TokenService) LogTokenUsage(log *models.TokenUsageLog) {
-	if err := s.repo.CreateUsageLog(log); err != nil {
-		s.logger.Errorf("Failed to log token usage: %v", err)
-	}
-	if err := s.repo.UpdateTokenUsage(log.TokenID, log.IPAddress, log.Endpoint); err != nil {
-		s.logger.Warnf("Failed to update token usage: %v", err)
-	}
+	s.usageLogs.Enqueue(log)
+}
+
+// DrainUsageLogs writes the queued usage logs and stops the writer, or gives
+// up when ctx is done.
+func (s *TokenService) DrainUsageLogs(ctx context.Context) error {
+	return s.usageLogs.Drain(ctx)
 }
 
 // ============================================================================
@@ -437,17 +503,17 @@ func (s *TokenService) LogTokenUsage(log *models.TokenUsageLog) {
 // ============================================================================
 
 // GetTokenAnalytics retrieves analytics for a specific token
-func (s *TokenService) GetTokenAnalytics(tokenID int, days int) (*models.TokenAnalytics, error) {
-	return s.repo.GetTokenAnalytics(tokenID, days)
+func (s *TokenService) GetTokenAnalytics(ctx context.Context, tokenID int, days int) (*models.TokenAnalytics, error) {
+	return s.repo.GetTokenAnalytics(ctx, tokenID, days)
 }
 
 // GetDashboardStats retrieves overall dashboard statistics
-func (s *TokenService) GetDashboardStats() (*models.TokenDashboardStats, error) {
-	stats, err := s.repo.GetDashboardStats()
+func (s *TokenService) GetDashboardStats(ctx context.Context) (*models.TokenDashboardStats, error) {
+	stats, err := s.repo.GetDashboardStats(ctx)
 	if err != nil {
 		return nil, err
 	}
-	recentLogs, err := s.repo.GetRecentUsageLogs(20)
+	recentLogs, err := s.repo.GetRecentUsageLogs(ctx, 20)
 	if err == nil {
 		stats.RecentActivity = recentLogs
 	}
@@ -455,23 +521,33 @@ func (s *TokenService) GetDashboardStats() (*models.TokenDashboardStats, error)
 }
 
 // GetEndpointStats retrieves endpoint statistics
-func (s *TokenService) GetEndpointStats(days int, limit int) ([]*models.EndpointStats, error) {
-	return s.repo.GetEndpointStats(days, limit)
+func (s *TokenService) GetEndpointStats(ctx context.Context, days int, limit int) ([]*models.EndpointStats, error) {
+	return s.repo.GetEndpointStats(ctx, days, limit)
 }
 
 // GetDailyUsage retrieves daily usage for charts
-func (s *TokenService) GetDailyUsage(tokenID *int, days int) ([]*models.DailyUsage, error) {
-	return s.repo.GetDailyUsage(tokenID, days)
+func (s *TokenService) GetDailyUsage(ctx context.Context, tokenID *int, days int) ([]*models.DailyUsage, error) {
+	return s.repo.GetDailyUsage(ctx, tokenID, days)
+}
+
+// GetErrorStats retrieves failed request counts per error code
+func (s *TokenService) GetErrorStats(ctx context.Context, tokenID *int, days int) ([]*models.ErrorCodeStats, error) {
+	return s.repo.GetErrorStats(ctx, tokenID, days)
+}
+
+// GetAPIVersionUsage retrieves per-token v1/v2 request counts
+func (s *TokenService) GetAPIVersionUsage(ctx context.Context, days int) ([]*models.APIVersionUsage, error) {
+	return s.repo.GetAPIVersionUsage(ctx, days)
 }
 
 // GetUsageLogsByTokenID retrieves usage logs for a specific token
-func (s *TokenService) GetUsageLogsByTokenID(tokenID int, limit int) ([]*models.TokenUsageLog, error) {
-	return s.repo.GetUsageLogsByTokenID(tokenID, limit)
+func (s *TokenService) GetUsageLogsByTokenID(ctx context.Context, tokenID int, limit int) ([]*models.TokenUsageLog, error) {
+	return s.repo.GetUsageLogsByTokenID(ctx, tokenID, limit)
 }
 
 // GetAuditLogs retrieves audit logs
-func (s *TokenService) GetAuditLogs(limit int) ([]*models.AuditLog, error) {
-	return s.repo.GetAuditLogs(limit)
+func (s *TokenService) GetAuditLogs(ctx context.Context, limit int) ([]*models.AuditLog, error) {
+	return s.repo.GetAuditLogs(ctx, limit)
 }
 
 // ============================================================================
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"api-gateway/metrics"
+	"api-gateway/models"
+	"api-gateway/repository"
+	"api-gateway/tracing"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// UsageLogService writes token usage logs in the background. Enqueue never
+// holds up a request: rows go into a bounded queue that one worker writes in
+// batches, and each token's request count and last use are summed in memory
+// and applied to api_tokens once per flush instead of once per request.
+type UsageLogService struct {
+	repo       *repository.TokenRepository
+	queue      chan *models.TokenUsageLog
+	batchSize  int
+	flushEvery time.Duration
+	sampleRate float64
+	logger     *logrus.Logger
+
+	mu    sync.Mutex
+	usage map[int]*repository.TokenUsageDelta // Per token since the last flush
+
+	drain chan context.Context // Drain hands Run its deadline
+	done  chan struct{}        // Closed when Run returns
+}
+
+// NewUsageLogService creates a new UsageLogService instance. Up to queueSize
+// rows wait for the writer, which flushes every batchSize rows or every
+// flushEvery. sampleRate is the fraction of successful requests whose rows are
+// kept; failed requests are always logged.
+func NewUsageLogService(repo *repository.TokenRepository, queueSize, batchSize int, flushEvery time.Duration, sampleRate float64, logger *logrus.Logger) *UsageLogService {
+	return &UsageLogService{
+		repo:       repo,
+		queue:      make(chan *models.TokenUsageLog, queueSize),
+		batchSize:  batchSize,
+		flushEvery: flushEvery,
+		sampleRate: sampleRate,
+		logger:     logger,
+		usage:      make(map[int]*repository.TokenUsageDelta),
+		drain:      make(chan context.Context),
+		done:       make(chan struct{}),
+	}
+}
+
+// Enqueue counts the request against its token and queues its log row. The
+// row is dropped when it is sampled out or the queue is full; the token's
+// counters include it either way.
+func (s *UsageLogService) Enqueue(log *models.TokenUsageLog) {
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+
+	if log.TokenID > 0 {
+		s.mu.Lock()
+		d := s.usage[log.TokenID]
+		if d == nil {
+			d = &repository.TokenUsageDelta{TokenID: log.TokenID}
+			s.usage[log.TokenID] = d
+		}
+		d.Requests++
+		if !log.CreatedAt.Before(d.LastUsedAt) {
+			d.LastUsedAt, d.LastUsedIP, d.LastUsedEndpoint = log.CreatedAt, log.IPAddress, log.Endpoint
+		}
+		s.mu.Unlock()
+	}
+
+	if log.StatusCode < 400 && s.sampleRate < 1 && rand.Float64() >= s.sampleRate {
+		metrics.UsageLogsDropped.WithLabelValues("sampled").Inc()
+		return
+	}
+	select {
+	case s.queue <- log:
+		metrics.UsageLogQueueDepth.Inc()
+	default:
+		metrics.UsageLogsDropped.WithLabelValues("queue_full").Inc()
+	}
+}
+
+// Run writes the queued rows whenever batchSize have gathered and every
+// flushEvery, until Drain is called. It then writes what is left under
+// Drain's deadline and returns.
+func (s *UsageLogService) Run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.flushEvery)
+	defer ticker.Stop()
+
+	s.logger.Infof("Usage log writer started (batch %d, every %s, sample rate %g)", s.batchSize, s.flushEvery, s.sampleRate)
+	batch := make([]*models.TokenUsageLog, 0, s.batchSize)
+	for {
+		select {
+		case log := <-s.queue:
+			metrics.UsageLogQueueDepth.Dec()
+			batch = append(batch, log)
+			if len(batch) < s.batchSize {
+				continue
+			}
+			s.flush(context.Background(), batch)
+			batch = batch[:0]
+		case <-ticker.C:
+			s.flush(context.Background(), batch)
+			batch = batch[:0]
+		case ctx := <-s.drain:
+			for len(s.queue) > 0 {
+				batch = append(batch, <-s.queue)
+				metrics.UsageLogQueueDepth.Dec()
+			}
+			s.flush(ctx, batch)
+			s.logger.Info("Usage log writer stopped")
+			return
+		}
+	}
+}
+
+// Drain makes Run write everything still queued and stop, and waits for it
+// until ctx is done. Call it once the servers have stopped, so no more rows
+// arrive.
+func (s *UsageLogService) Drain(ctx context.Context) error {
+	select {
+	case s.drain <- ctx:
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d usage log rows still queued: %w", len(s.queue), ctx.Err())
+	}
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("usage log writes still pending: %w", ctx.Err())
+	}
+}
+
+// flush writes batch and the token counters gathered since the last flush.
+func (s *UsageLogService) flush(ctx context.Context, batch []*models.TokenUsageLog) {
+	s.mu.Lock()
+	usage := s.usage
+	s.usage = make(map[int]*repository.TokenUsageDelta)
+	s.mu.Unlock()
+	if len(batch) == 0 && len(usage) == 0 {
+		return
+	}
+
+	ctx, span := tracing.Start(ctx, "UsageLogService.flush",
+		attribute.Int("usage_log.rows", len(batch)), attribute.Int("usage_log.tokens", len(usage)))
+	defer span.End()
+
+	if err := s.repo.CreateUsageLogs(ctx, batch); err != nil {
+		metrics.UsageLogWriteFailures.Add(float64(len(batch)))
+		span.RecordError(err)
+		s.logger.Errorf("Failed to write %d token usage logs: %v", len(batch), err)
+	}
+
+	if len(usage) == 0 {
+		return
+	}
+	deltas := make([]repository.TokenUsageDelta, 0, len(usage))
+	for _, d := range usage {
+		deltas = append(deltas, *d)
+	}
+	if err := s.repo.AddTokenUsage(ctx, deltas); err != nil {
+		span.RecordError(err)
+		s.logger.Warnf("Failed to update usage of %d tokens, retrying with the next flush: %v", len(deltas), err)
+		s.restore(usage)
+	}
+}
+
+// restore merges counters whose write failed back into those gathered since,
+// so the next flush applies both.
+func (s *UsageLogService) restore(usage map[int]*repository.TokenUsageDelta) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, old := range usage {
+		d := s.usage[id]
+		if d == nil {
+			s.usage[id] = old
+			continue
+		}
+		d.Requests += old.Requests
+		if old.LastUsedAt.After(d.LastUsedAt) {
+			d.LastUsedAt, d.LastUsedIP, d.LastUsedEndpoint = old.LastUsedAt, old.LastUsedIP, old.LastUsedEndpoint
+		}
+	}
+}
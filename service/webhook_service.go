@@ -0,0 +1,421 @@
+package service
+
+import (
+	"api-gateway/models"
+	"api-gateway/repository"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	webhookBatchSize   = 50
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+)
+
+// WebhookService manages webhook subscriptions, queues ticket events in the
+// outbox and delivers them with signed, retried POST requests.
+type WebhookService struct {
+	repo      *repository.WebhookRepository
+	tokenRepo *repository.TokenRepository // token lookup + audit logging
+	client    *http.Client
+	logger    *logrus.Logger
+
+	pollInterval time.Duration
+	maxAttempts  int
+}
+
+// NewWebhookService creates a new WebhookService instance.
+func NewWebhookService(repo *repository.WebhookRepository, tokenRepo *repository.TokenRepository, pollInterval, timeout time.Duration, maxAttempts int, logger *logrus.Logger) *WebhookService {
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 8
+	}
+	return &WebhookService{
+		repo:         repo,
+		tokenRepo:    tokenRepo,
+		client:       &http.Client{Timeout: timeout},
+		logger:       logger,
+		pollInterval: pollInterval,
+		maxAttempts:  maxAttempts,
+	}
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed
+// with the subscription secret, as sent in X-Webhook-Signature (after "sha256=").
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ============================================================================
+// Event Queueing
+// ============================================================================
+
+// Enqueue writes one outbox row per active subscription that wants eventType and
+// whose token's vendor scope covers the row.
+func (s *WebhookService) Enqueue(eventType string, rows ...*models.DataRow) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	subs, err := s.repo.GetActiveSubscriptions()
+	if err != nil {
+		return fmt.Errorf("failed to load webhook subscriptions: %v", err)
+	}
+
+	var deliveries []*models.WebhookDelivery
+	now := time.Now()
+	for _, row := range rows {
+		eventID := uuid.New().String()
+		var payload []byte
+
+		for _, sub := range subs {
+			if !sub.WantsEvent(eventType) {
+				continue
+			}
+			filter := repository.ResolveVendorFilter(sub.TokenFilterColumn, sub.TokenFilterValue, sub.TokenIsSuper)
+			if !filter.Matches(row) {
+				continue
+			}
+			if payload == nil {
+				if payload, err = json.Marshal(models.WebhookPayload{
+					EventID: eventID, EventType: eventType, OccurredAt: now, Data: row,
+				}); err != nil {
+					return err
+				}
+			}
+			deliveries = append(deliveries, &models.WebhookDelivery{
+				SubscriptionID: sub.ID,
+				EventID:        eventID,
+				EventType:      eventType,
+				TerminalID:     row.TerminalID,
+				Payload:        string(payload),
+			})
+		}
+	}
+
+	if err := s.repo.EnqueueDeliveries(deliveries); err != nil {
+		return fmt.Errorf("failed to queue webhook deliveries: %v", err)
+	}
+	return nil
+}
+
+// ============================================================================
+// Delivery Worker
+// ============================================================================
+
+// Run delivers due outbox rows until ctx is cancelled.
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	s.logger.Infof("Webhook delivery worker started (poll interval %s)", s.pollInterval)
+	for {
+		s.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Webhook delivery worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDue claims and delivers batches until nothing is due.
+func (s *WebhookService) deliverDue(ctx context.Context) {
+	// The lease outlives one request timeout so a slow attempt is not claimed twice
+	lease := 2*s.client.Timeout + 30*time.Second
+
+	for ctx.Err() == nil {
+		deliveries, err := s.repo.ClaimDueDeliveries(webhookBatchSize, lease)
+		if err != nil {
+			s.logger.Errorf("Failed to claim webhook deliveries: %v", err)
+			return
+		}
+		for _, d := range deliveries {
+			s.deliver(ctx, d)
+		}
+		if len(deliveries) < webhookBatchSize {
+			return
+		}
+	}
+}
+
+// deliver makes one delivery attempt and records the outcome.
+func (s *WebhookService) deliver(ctx context.Context, d *models.WebhookDelivery) {
+	attempts := d.Attempts + 1
+	statusCode, err := s.post(ctx, d)
+	if err == nil {
+		if err := s.repo.MarkDelivered(d.ID, attempts, statusCode); err != nil {
+			s.logger.Errorf("Failed to mark webhook delivery %d delivered: %v", d.ID, err)
+		}
+		return
+	}
+
+	var code *int
+	if statusCode > 0 {
+		code = &statusCode
+	}
+	dead := attempts >= s.maxAttempts
+	next := time.Now().Add(webhookBackoff(attempts))
+	if dead {
+		s.logger.Warnf("Webhook delivery %d to %s dead after %d attempts: %v", d.ID, d.URL, attempts, err)
+	}
+	if err := s.repo.MarkFailed(d.ID, attempts, code, err.Error(), next, dead); err != nil {
+		s.logger.Errorf("Failed to record webhook delivery %d failure: %v", d.ID, err)
+	}
+}
+
+// post sends the signed payload. Any 2xx response counts as delivered.
+func (s *WebhookService) post(ctx context.Context, d *models.WebhookDelivery) (int, error) {
+	body := []byte(d.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, strings.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "api-gateway-webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", d.EventID)
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatInt(d.ID, 10))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(d.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// webhookBackoff returns the wait before the next attempt: 30s doubling per
+// attempt, capped at 6h.
+func webhookBackoff(attempts int) time.Duration {
+	d := webhookBaseBackoff
+	for i := 1; i < attempts && d < webhookMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > webhookMaxBackoff {
+		d = webhookMaxBackoff
+	}
+	return d
+}
+
+// ============================================================================
+// Subscription Management
+// ============================================================================
+
+// GetSubscriptions retrieves the webhook subscriptions of a token
+func (s *WebhookService) GetSubscriptions(ctx context.Context, tokenID int) ([]*models.WebhookSubscription, error) {
+	if _, err := s.tokenRepo.GetAPITokenByID(ctx, tokenID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSubscriptionsByToken(tokenID)
+}
+
+// GetSubscription retrieves one subscription, which must belong to the token
+func (s *WebhookService) GetSubscription(tokenID, id int) (*models.WebhookSubscription, error) {
+	sub, err := s.repo.GetSubscriptionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.TokenID != tokenID {
+		return nil, fmt.Errorf("webhook subscription %w", ErrNotFound)
+	}
+	return sub, nil
+}
+
+// CreateSubscription creates a subscription for a token. The signing secret is
+// generated when the request has none; it is returned only here.
+func (s *WebhookService) CreateSubscription(ctx context.Context, tokenID int, req *models.WebhookSubscriptionRequest, createdBy int) (*models.WebhookSubscription, string, error) {
+	token, err := s.tokenRepo.GetAPITokenByID(ctx, tokenID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sub, err := subscriptionFromRequest(req)
+	if err != nil {
+		return nil, "", err
+	}
+	sub.TokenID = tokenID
+	if sub.Secret == "" {
+		if sub.Secret, err = generateWebhookSecret(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	id, err := s.repo.CreateSubscription(sub, createdBy)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create webhook subscription: %v", err)
+	}
+
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &createdBy, Action: "create_webhook",
+		ResourceType: "webhook_subscription", ResourceID: &id,
+		NewValues:   auditJSON(map[string]interface{}{"token_id": tokenID, "url": sub.URL, "event_types": sub.EventTypes}),
+		Description: fmt.Sprintf("Created webhook for API token %s: %s", token.Name, sub.URL),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+
+	created, err := s.repo.GetSubscriptionByID(id)
+	if err != nil {
+		return nil, "", err
+	}
+	return created, sub.Secret, nil
+}
+
+// UpdateSubscription replaces a subscription's URL, event filter and status.
+// The secret is kept unless a new one is supplied.
+func (s *WebhookService) UpdateSubscription(ctx context.Context, tokenID, id int, req *models.WebhookSubscriptionRequest, updatedBy int) (*models.WebhookSubscription, error) {
+	old, err := s.GetSubscription(tokenID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := subscriptionFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	sub.ID, sub.TokenID = id, tokenID
+	if sub.Secret == "" {
+		sub.Secret = old.Secret
+	}
+
+	if err := s.repo.UpdateSubscription(sub); err != nil {
+		return nil, fmt.Errorf("failed to update webhook subscription: %v", err)
+	}
+
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &updatedBy, Action: "update_webhook",
+		ResourceType: "webhook_subscription", ResourceID: &id,
+		OldValues:   auditJSON(map[string]interface{}{"url": old.URL, "event_types": old.EventTypes, "is_active": old.IsActive}),
+		NewValues:   auditJSON(map[string]interface{}{"url": sub.URL, "event_types": sub.EventTypes, "is_active": sub.IsActive, "secret_rotated": req.Secret != ""}),
+		Description: fmt.Sprintf("Updated webhook %d: %s", id, sub.URL),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+
+	return s.repo.GetSubscriptionByID(id)
+}
+
+// DeleteSubscription deletes a subscription together with its outbox rows
+func (s *WebhookService) DeleteSubscription(ctx context.Context, tokenID, id int, deletedBy int) error {
+	sub, err := s.GetSubscription(tokenID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteSubscription(id); err != nil {
+		return err
+	}
+
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &deletedBy, Action: "delete_webhook",
+		ResourceType: "webhook_subscription", ResourceID: &id,
+		Description: fmt.Sprintf("Deleted webhook %d: %s", id, sub.URL),
+		RequestID:   RequestIDFromContext(ctx),
+	})
+	return nil
+}
+
+// ============================================================================
+// Deliveries & Dead Letters
+// ============================================================================
+
+// GetDeliveries lists recent outbox rows of one subscription, optionally by status
+func (s *WebhookService) GetDeliveries(tokenID, id int, status string, limit int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.GetSubscription(tokenID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(&id, status, limit)
+}
+
+// GetDeadLetters lists deliveries that exhausted their attempts, across all subscriptions
+func (s *WebhookService) GetDeadLetters(limit int) ([]*models.WebhookDelivery, error) {
+	return s.repo.ListDeliveries(nil, models.DeliveryDead, limit)
+}
+
+// RetryDelivery puts an undelivered (typically dead) delivery back in the queue
+func (s *WebhookService) RetryDelivery(ctx context.Context, id int64, retriedBy int) error {
+	if err := s.repo.RequeueDelivery(id); err != nil {
+		return err
+	}
+
+	_ = s.tokenRepo.CreateAuditLog(ctx, &models.AuditLog{
+		AdminUserID: &retriedBy, Action: "retry_webhook_delivery",
+		ResourceType: "webhook_delivery",
+		Description:  fmt.Sprintf("Requeued webhook delivery %d", id),
+		RequestID:    RequestIDFromContext(ctx),
+	})
+	return nil
+}
+
+// subscriptionFromRequest builds and validates a subscription from an API request.
+// Subscriptions are active unless stated otherwise.
+func subscriptionFromRequest(req *models.WebhookSubscriptionRequest) (*models.WebhookSubscription, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidInput)
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	eventTypes := req.EventTypes
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+
+	return &models.WebhookSubscription{
+		URL:        req.URL,
+		EventTypes: eventTypes,
+		Secret:     req.Secret,
+		IsActive:   isActive,
+	}, nil
+}
+
+// auditJSON encodes audit log values; secrets must never be passed in
+func auditJSON(v map[string]interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// generateWebhookSecret returns a random signing secret
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}